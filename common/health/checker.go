@@ -0,0 +1,175 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/logger"
+)
+
+// CheckFunc returns an error when a dependency can't serve requests.
+type CheckFunc func(ctx context.Context) error
+
+type namedCheck struct {
+	name     string
+	check    CheckFunc
+	optional bool
+}
+
+type namedInfo struct {
+	name  string
+	value func() string
+}
+
+type Checker struct {
+	checks  []namedCheck
+	info    []namedInfo
+	timeout time.Duration
+	logger  logger.Logger
+
+	// failing holds the checks down on the last run, so a dependency that
+	// stays down is logged once rather than on every probe
+	mu      sync.Mutex
+	failing map[string]bool
+}
+
+func NewChecker(timeout time.Duration, logger logger.Logger) *Checker {
+	return &Checker{timeout: timeout, logger: logger, failing: make(map[string]bool)}
+}
+
+func (hc *Checker) Add(name string, check CheckFunc) {
+	hc.checks = append(hc.checks, namedCheck{name: name, check: check})
+}
+
+// AddOptional adds a check for a dependency the service can run without;
+// its failure only degrades the report.
+func (hc *Checker) AddOptional(name string, check CheckFunc) {
+	hc.checks = append(hc.checks, namedCheck{name: name, check: check, optional: true})
+}
+
+// AddInfo adds a value reported along with the checks, such as which
+// backend is in use.
+func (hc *Checker) AddInfo(name string, value func() string) {
+	hc.info = append(hc.info, namedInfo{name: name, value: value})
+}
+
+// Run runs all checks concurrently, each bounded by the checker timeout.
+// It is down when a required check fails and degraded when only optional
+// ones do.
+func (hc *Checker) Run(ctx context.Context) Report {
+	results := make([]CheckResult, len(hc.checks))
+
+	var wg sync.WaitGroup
+	for i, c := range hc.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = hc.run(ctx, c)
+		}()
+	}
+	wg.Wait()
+
+	hc.logTransitions(results)
+
+	report := Report{Status: StatusUp, Checks: make(map[string]CheckResult, len(hc.checks))}
+	for i, c := range hc.checks {
+		report.Checks[c.name] = results[i]
+
+		if results[i].Status != StatusDown {
+			continue
+		}
+
+		if !c.optional {
+			report.Status = StatusDown
+		} else if report.Status == StatusUp {
+			report.Status = StatusDegraded
+		}
+	}
+
+	if len(hc.info) > 0 {
+		report.Info = make(map[string]string, len(hc.info))
+		for _, info := range hc.info {
+			report.Info[info.name] = info.value()
+		}
+	}
+
+	return report
+}
+
+// logTransitions logs the checks that went down or came back up since the
+// previous run.
+func (hc *Checker) logTransitions(results []CheckResult) {
+	hc.mu.Lock()
+	defer hc.mu.Unlock()
+
+	for i, c := range hc.checks {
+		down := results[i].Status == StatusDown
+		if down == hc.failing[c.name] {
+			continue
+		}
+
+		if down {
+			hc.logger.Error("Readiness check failed", "check", c.name, "error", results[i].Error)
+		} else {
+			hc.logger.Info("Readiness check recovered", "check", c.name)
+		}
+		hc.failing[c.name] = down
+	}
+}
+
+func (hc *Checker) run(ctx context.Context, c namedCheck) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, hc.timeout)
+	defer cancel()
+
+	start := time.Now()
+
+	// checks that ignore ctx still can't hold the probe past the timeout
+	done := make(chan error, 1)
+	go func() {
+		done <- c.check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ErrCheckTimedOut
+	}
+
+	result := CheckResult{Status: StatusUp, LatencyMs: milliseconds(time.Since(start)), Optional: c.optional}
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+
+	return result
+}
+
+// Cached remembers the outcome of check for ttl, so probes of external
+// services don't call them on every request.
+func Cached(check CheckFunc, ttl time.Duration) CheckFunc {
+	var (
+		mu        sync.Mutex
+		lastErr   error
+		checkedAt time.Time
+	)
+
+	return func(ctx context.Context) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if !checkedAt.IsZero() && time.Since(checkedAt) < ttl {
+			return lastErr
+		}
+
+		lastErr = check(ctx)
+		checkedAt = time.Now()
+
+		return lastErr
+	}
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
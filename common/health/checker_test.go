@@ -0,0 +1,138 @@
+//go:build unit
+// +build unit
+
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func up(context.Context) error { return nil }
+
+func TestRun_AllUp(t *testing.T) {
+	mockLog, _ := logger.NewTestLogger()
+	checker := NewChecker(time.Second, *mockLog)
+	checker.Add("postgres", up)
+	checker.Add("redis", up)
+
+	report := checker.Run(context.Background())
+
+	assert.Equal(t, StatusUp, report.Status)
+	assert.Equal(t, StatusUp, report.Checks["postgres"].Status)
+	assert.Equal(t, StatusUp, report.Checks["redis"].Status)
+}
+
+func TestRun_OneDown(t *testing.T) {
+	mockLog, _ := logger.NewTestLogger()
+	checker := NewChecker(time.Second, *mockLog)
+	checker.Add("postgres", up)
+	checker.Add("redis", func(context.Context) error { return errors.New("connection refused") })
+
+	report := checker.Run(context.Background())
+
+	assert.Equal(t, StatusDown, report.Status)
+	assert.Equal(t, StatusUp, report.Checks["postgres"].Status)
+	assert.Equal(t, CheckResult{Status: StatusDown, LatencyMs: report.Checks["redis"].LatencyMs,
+		Error: "connection refused"}, report.Checks["redis"])
+}
+
+func TestRun_TimesOutChecksIgnoringContext(t *testing.T) {
+	mockLog, _ := logger.NewTestLogger()
+	checker := NewChecker(20*time.Millisecond, *mockLog)
+
+	release := make(chan struct{})
+	defer close(release)
+	checker.Add("weather_provider", func(context.Context) error {
+		<-release
+		return nil
+	})
+
+	report := checker.Run(context.Background())
+
+	assert.Equal(t, StatusDown, report.Status)
+	assert.Equal(t, ErrCheckTimedOut.Error(), report.Checks["weather_provider"].Error)
+}
+
+func TestRun_LogsOnlyStateChanges(t *testing.T) {
+	mockLog, logs := logger.NewTestLogger()
+	checker := NewChecker(time.Second, *mockLog)
+
+	var err error
+	checker.Add("redis", func(context.Context) error { return err })
+
+	err = errors.New("connection refused")
+	checker.Run(context.Background())
+	checker.Run(context.Background())
+	assert.Equal(t, 1, strings.Count(logs.Logs(), "Readiness check failed"))
+
+	err = nil
+	checker.Run(context.Background())
+	checker.Run(context.Background())
+	assert.Equal(t, 1, strings.Count(logs.Logs(), "Readiness check recovered"))
+	assert.Equal(t, 1, strings.Count(logs.Logs(), "Readiness check failed"))
+}
+
+func TestCached(t *testing.T) {
+	calls := 0
+	check := Cached(func(context.Context) error {
+		calls++
+		return errors.New("provider down")
+	}, time.Hour)
+
+	assert.EqualError(t, check(context.Background()), "provider down")
+	assert.EqualError(t, check(context.Background()), "provider down")
+	assert.Equal(t, 1, calls)
+}
+
+func TestReadiness(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockLog, _ := logger.NewTestLogger()
+
+	checker := NewChecker(time.Second, *mockLog)
+	checker.Add("rabbitmq", func(context.Context) error { return errors.New("rabbitmq channel is closed") })
+
+	router := gin.New()
+	controller := NewHealthController(checker)
+	router.GET("/healthz", controller.Liveness)
+	router.GET("/readyz", controller.Readiness)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"status":"up"}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	var report Report
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	assert.Equal(t, StatusDown, report.Status)
+	assert.Equal(t, "rabbitmq channel is closed", report.Checks["rabbitmq"].Error)
+}
+
+func TestRun_OptionalDownIsDegraded(t *testing.T) {
+	mockLog, _ := logger.NewTestLogger()
+	checker := NewChecker(time.Second, *mockLog)
+	checker.Add("postgres", up)
+	checker.AddOptional("redis", func(context.Context) error { return errors.New("connection refused") })
+	checker.AddInfo("cache_backend", func() string { return "memory" })
+
+	report := checker.Run(context.Background())
+
+	assert.Equal(t, StatusDegraded, report.Status)
+	assert.True(t, report.Checks["redis"].Optional)
+	assert.Equal(t, map[string]string{"cache_backend": "memory"}, report.Info)
+}
@@ -0,0 +1,21 @@
+package health
+
+import (
+	"context"
+	"net"
+)
+
+// Dial checks that a TCP connection to address can be opened, without
+// speaking its protocol or authenticating.
+func Dial(address string) CheckFunc {
+	return func(ctx context.Context) error {
+		var dialer net.Dialer
+
+		conn, err := dialer.DialContext(ctx, "tcp", address)
+		if err != nil {
+			return err
+		}
+
+		return conn.Close()
+	}
+}
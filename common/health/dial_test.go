@@ -0,0 +1,24 @@
+//go:build unit
+// +build unit
+
+package health
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDial(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := listener.Addr().String()
+
+	assert.NoError(t, Dial(address)(context.Background()))
+
+	require.NoError(t, listener.Close())
+	assert.Error(t, Dial(address)(context.Background()))
+}
@@ -0,0 +1,27 @@
+package health
+
+import "errors"
+
+type Status string
+
+const (
+	StatusUp   Status = "up"
+	StatusDown Status = "down"
+	// only optional checks failed, the instance still serves
+	StatusDegraded Status = "degraded"
+)
+
+var ErrCheckTimedOut = errors.New("check timed out")
+
+type CheckResult struct {
+	Status    Status  `json:"status"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+	Optional  bool    `json:"optional,omitempty"`
+}
+
+type Report struct {
+	Status Status                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks,omitempty"`
+	Info   map[string]string      `json:"info,omitempty"`
+}
@@ -0,0 +1,34 @@
+package health
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HealthController struct {
+	checker *Checker
+}
+
+func NewHealthController(checker *Checker) *HealthController {
+	return &HealthController{checker: checker}
+}
+
+// Liveness answers as long as the process serves HTTP; dependencies are
+// left to Readiness so a broken database doesn't get the process restarted.
+func (hc *HealthController) Liveness(c *gin.Context) {
+	c.JSON(http.StatusOK, Report{Status: StatusUp})
+}
+
+// Readiness reports every dependency check and answers 503 when a required
+// one fails, so the instance is taken out of rotation.
+func (hc *HealthController) Readiness(c *gin.Context) {
+	report := hc.checker.Run(c.Request.Context())
+
+	status := http.StatusOK
+	if report.Status == StatusDown {
+		status = http.StatusServiceUnavailable
+	}
+
+	c.JSON(status, report)
+}
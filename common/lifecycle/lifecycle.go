@@ -0,0 +1,81 @@
+// Package lifecycle stops a service's components in reverse order on shutdown.
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/logger"
+)
+
+// StopFunc stops one component within the deadline of ctx.
+type StopFunc func(ctx context.Context) error
+
+type lifecycleStep struct {
+	name string
+	stop StopFunc
+}
+
+// Lifecycle stops what a service started in reverse order, like defers, but
+// under one deadline so a stuck component can't hold the process forever.
+type Lifecycle struct {
+	steps  []lifecycleStep
+	logger logger.Logger
+}
+
+func New(logger logger.Logger) *Lifecycle {
+	return &Lifecycle{logger: logger}
+}
+
+func (l *Lifecycle) OnStop(name string, stop StopFunc) {
+	l.steps = append(l.steps, lifecycleStep{name: name, stop: stop})
+}
+
+// Stop runs every step even when an earlier one fails or the deadline
+// passes, so connections are still closed.
+func (l *Lifecycle) Stop(timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var errs []error
+	for i := len(l.steps) - 1; i >= 0; i-- {
+		step := l.steps[i]
+
+		l.logger.Info("Stopping", "component", step.name)
+		if err := step.stop(ctx); err != nil {
+			l.logger.Error("Failed to stop", "component", step.name, "error", err)
+			errs = append(errs, fmt.Errorf("%s: %w", step.name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Closer adapts Close methods that don't take a context.
+func Closer(close func() error) StopFunc {
+	return func(context.Context) error {
+		return close()
+	}
+}
+
+// Serve runs the server until it fails or ctx is cancelled by a signal.
+func Serve(ctx context.Context, server *http.Server, logger logger.Logger) error {
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return err
+	case <-ctx.Done():
+		logger.Info("Shutdown signal received")
+		return nil
+	}
+}
@@ -0,0 +1,51 @@
+//go:build unit
+// +build unit
+
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLifecycleStop_ReverseOrder(t *testing.T) {
+	mockLog, _ := logger.NewTestLogger()
+	l := New(*mockLog)
+
+	var stopped []string
+	for _, name := range []string{"rabbitmq", "database", "redis", "http server"} {
+		l.OnStop(name, Closer(func() error {
+			stopped = append(stopped, name)
+			return nil
+		}))
+	}
+
+	assert.NoError(t, l.Stop(time.Second))
+	assert.Equal(t, []string{"http server", "redis", "database", "rabbitmq"}, stopped)
+}
+
+func TestLifecycleStop_KeepsClosingAfterDeadline(t *testing.T) {
+	mockLog, _ := logger.NewTestLogger()
+	l := New(*mockLog)
+
+	closed := false
+	l.OnStop("database", Closer(func() error {
+		closed = true
+		return nil
+	}))
+	l.OnStop("scheduler", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	err := l.Stop(10 * time.Millisecond)
+
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.ErrorContains(t, err, "scheduler")
+	assert.True(t, closed)
+}
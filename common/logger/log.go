@@ -1,9 +1,11 @@
 package logger
 
 import (
+	"context"
 	"os"
 	"time"
 
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
@@ -54,6 +56,22 @@ func NewLogger() (*Logger, error) {
 	}, nil
 }
 
+// WithContext returns a logger adding the trace and span ids of the span
+// in ctx to every line, or l itself when ctx isn't traced.
+func (l *Logger) WithContext(ctx context.Context) *Logger {
+	spanCtx := trace.SpanContextFromContext(ctx)
+	if !spanCtx.IsValid() {
+		return l
+	}
+
+	return &Logger{
+		logger: l.logger.With(
+			"trace_id", spanCtx.TraceID().String(),
+			"span_id", spanCtx.SpanID().String(),
+		),
+	}
+}
+
 func (l *Logger) Info(msg string, keysAndValues ...any) {
 	l.logger.Infow(msg, keysAndValues...)
 }
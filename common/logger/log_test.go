@@ -0,0 +1,26 @@
+//go:build unit
+// +build unit
+
+package logger
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestWithContext_AddsTraceIDs(t *testing.T) {
+	log, buf := NewTestLogger()
+	spanCtx := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{0x4b, 0xf9},
+		SpanID:  trace.SpanID{0x00, 0xf0},
+	})
+
+	log.WithContext(trace.ContextWithSpanContext(context.Background(), spanCtx)).Info("traced")
+	log.WithContext(context.Background()).Info("untraced")
+
+	assert.Contains(t, buf.Logs(), `"msg":"traced","trace_id":"`+spanCtx.TraceID().String()+`","span_id":"`+spanCtx.SpanID().String()+`"`)
+	assert.Contains(t, buf.Logs(), `{"level":"info","msg":"untraced"}`)
+}
@@ -0,0 +1,30 @@
+package rabbitmq
+
+import (
+	"github.com/rabbitmq/amqp091-go"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+const tracerName = "rabbitmq"
+
+// headerCarrier carries the trace context in AMQP message headers.
+type headerCarrier amqp091.Table
+
+var _ propagation.TextMapCarrier = headerCarrier{}
+
+func (h headerCarrier) Get(key string) string {
+	value, _ := h[key].(string)
+	return value
+}
+
+func (h headerCarrier) Set(key string, value string) {
+	h[key] = value
+}
+
+func (h headerCarrier) Keys() []string {
+	keys := make([]string, 0, len(h))
+	for key := range h {
+		keys = append(keys, key)
+	}
+	return keys
+}
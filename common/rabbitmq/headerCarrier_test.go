@@ -0,0 +1,33 @@
+//go:build unit
+// +build unit
+
+package rabbitmq
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestHeaderCarrier_RoundTrip(t *testing.T) {
+	propagator := propagation.TraceContext{}
+	spanCtx := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x4b, 0xf9, 0x2f, 0x35},
+		SpanID:     trace.SpanID{0x00, 0xf0, 0x67, 0xaa},
+		TraceFlags: trace.FlagsSampled,
+	})
+
+	headers := amqp091.Table{"x-retry": int32(1)}
+	propagator.Inject(trace.ContextWithSpanContext(context.Background(), spanCtx), headerCarrier(headers))
+
+	extracted := trace.SpanContextFromContext(propagator.Extract(context.Background(), headerCarrier(headers)))
+
+	assert.Equal(t, spanCtx.TraceID(), extracted.TraceID())
+	assert.Equal(t, spanCtx.SpanID(), extracted.SpanID())
+	assert.True(t, extracted.IsRemote())
+	assert.Equal(t, int32(1), headers["x-retry"])
+}
@@ -1,10 +1,18 @@
 package rabbitmq
 
 import (
-	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/logger"
+	"context"
+	"errors"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/logger"
 	"github.com/rabbitmq/amqp091-go"
 )
 
+var (
+	ErrConnectionClosed = errors.New("rabbitmq connection is closed")
+	ErrChannelClosed    = errors.New("rabbitmq channel is closed")
+)
+
 type RabbitMQ struct {
 	Conn    *amqp091.Connection
 	Channel *amqp091.Channel
@@ -51,3 +59,14 @@ func (r *RabbitMQ) Close() error {
 	}
 	return nil
 }
+
+// Check reports whether the connection and channel are still open.
+func (r *RabbitMQ) Check(context.Context) error {
+	if r.Conn.IsClosed() {
+		return ErrConnectionClosed
+	}
+	if r.Channel.IsClosed() {
+		return ErrChannelClosed
+	}
+	return nil
+}
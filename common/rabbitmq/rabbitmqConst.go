@@ -0,0 +1,9 @@
+package rabbitmq
+
+const SendEmail = "send_email"
+
+const WeatherUpdate = "weather_update"
+
+const WeatherDigest = "weather_digest"
+
+const SubscriptionSuppressed = "subscription_suppressed"
@@ -0,0 +1,173 @@
+package rabbitmq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/logger"
+	"github.com/rabbitmq/amqp091-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var ErrConsumerStopped = errors.New("rabbitmq consumer stopped")
+
+type RabbitMQConsumer struct {
+	channel *amqp091.Channel
+	logger  logger.Logger
+
+	mu       sync.Mutex
+	tags     []string
+	stopping chan struct{}
+	workers  sync.WaitGroup
+	running  atomic.Int32
+}
+
+func NewRabbitMQConsumer(channel *amqp091.Channel, logger logger.Logger) *RabbitMQConsumer {
+	return &RabbitMQConsumer{channel: channel, logger: logger, stopping: make(chan struct{})}
+}
+
+// Consume hands every message of queue to handler, with a context carrying
+// the trace the publisher started. A message the handler fails on is
+// requeued once; failing on the redelivery drops it.
+func (c *RabbitMQConsumer) Consume(queue string, handler func(ctx context.Context, body []byte) error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.isStopping() {
+		c.logger.Info("Not consuming, shutting down", "queue", queue)
+		return
+	}
+
+	tag := fmt.Sprintf("%s-%d", queue, len(c.tags))
+
+	msgs, err := c.channel.Consume(
+		queue,
+		tag,
+		false, // auto-ack
+		false, // exclusive
+		false, // no-local
+		false, // no-wait
+		nil,
+	)
+	if err != nil {
+		c.logger.Error("Failed to register a consumer", "error", err)
+		return
+	}
+
+	c.tags = append(c.tags, tag)
+	c.workers.Add(1)
+	c.running.Add(1)
+
+	go func() {
+		defer c.workers.Done()
+		defer c.running.Add(-1)
+
+		for msg := range msgs {
+			// prefetched messages are handed back, so another instance
+			// processes them instead of this one dying halfway through
+			if c.isStopping() {
+				if err := msg.Nack(false, true); err != nil {
+					c.logger.Error("Failed to nack message", "error", err)
+				}
+				continue
+			}
+
+			if err := c.handle(queue, msg, handler); err != nil {
+				requeue := !msg.Redelivered
+				c.logger.Error("Failed to handle message", "queue", queue, "requeue", requeue, "error", err)
+
+				if err := msg.Nack(false, requeue); err != nil {
+					c.logger.Error("Failed to nack message", "error", err)
+				}
+				continue
+			}
+
+			if err := msg.Ack(false); err != nil {
+				c.logger.Error("Failed to ack message", "error", err)
+			}
+		}
+	}()
+}
+
+func (c *RabbitMQConsumer) handle(queue string, msg amqp091.Delivery,
+	handler func(ctx context.Context, body []byte) error) error {
+	ctx := otel.GetTextMapPropagator().Extract(context.Background(), headerCarrier(msg.Headers))
+	ctx, span := otel.Tracer(tracerName).Start(ctx, queue+" process",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			semconv.MessagingSystemRabbitmq,
+			semconv.MessagingOperationTypeDeliver,
+			semconv.MessagingDestinationName(queue),
+		),
+	)
+	defer span.End()
+
+	err := handler(ctx, msg.Body)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return err
+}
+
+// Shutdown cancels the consumers and waits for the messages being handled,
+// requeueing the ones that were delivered but not started yet.
+func (c *RabbitMQConsumer) Shutdown(ctx context.Context) error {
+	c.mu.Lock()
+	if !c.isStopping() {
+		close(c.stopping)
+	}
+	tags := c.tags
+	c.mu.Unlock()
+
+	for _, tag := range tags {
+		if err := c.channel.Cancel(tag, false); err != nil {
+			c.logger.Error("Failed to cancel consumer", "consumer", tag, "error", err)
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		c.workers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Check fails once a consumer stopped receiving, e.g. because the broker
+// closed the channel, or the consumers are shutting down.
+func (c *RabbitMQConsumer) Check(context.Context) error {
+	c.mu.Lock()
+	registered := len(c.tags)
+	c.mu.Unlock()
+
+	if c.isStopping() {
+		return fmt.Errorf("%w: shutting down", ErrConsumerStopped)
+	}
+	if running := int(c.running.Load()); running < registered {
+		return fmt.Errorf("%w: %d of %d running", ErrConsumerStopped, running, registered)
+	}
+	return nil
+}
+
+func (c *RabbitMQConsumer) isStopping() bool {
+	select {
+	case <-c.stopping:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,77 @@
+package rabbitmq
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/rabbitmq/amqp091-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type RabbitMQPublisher struct {
+	Channel *amqp091.Channel
+}
+
+func NewRabbitMQPublisher(channel *amqp091.Channel) *RabbitMQPublisher {
+	return &RabbitMQPublisher{Channel: channel}
+}
+
+// Publish sends payload as JSON to queue, with the trace context of ctx
+// in the message headers so the consumer continues the same trace.
+func (p *RabbitMQPublisher) Publish(ctx context.Context, queue string, payload any) error {
+	ctx, span := otel.Tracer(tracerName).Start(ctx, queue+" publish",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			semconv.MessagingSystemRabbitmq,
+			semconv.MessagingOperationTypePublish,
+			semconv.MessagingDestinationName(queue),
+		),
+	)
+	defer span.End()
+
+	err := p.publish(ctx, queue, payload)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return err
+}
+
+func (p *RabbitMQPublisher) publish(ctx context.Context, queue string, payload any) error {
+
+	if p.Channel == nil {
+		return fmt.Errorf("channel is nil")
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message for queue %s: %w", queue, err)
+	}
+
+	headers := amqp091.Table{}
+	otel.GetTextMapPropagator().Inject(ctx, headerCarrier(headers))
+
+	err = p.Channel.PublishWithContext(
+		ctx,
+		"",    // exchange
+		queue, // routing key (queue name)
+		false, // mandatory
+		false, // immediate
+		amqp091.Publishing{
+			ContentType: "application/json",
+			Headers:     headers,
+			Body:        data,
+		},
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to publish to queue %s: %w", queue, err)
+	}
+
+	return nil
+}
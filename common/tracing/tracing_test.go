@@ -0,0 +1,36 @@
+//go:build unit
+// +build unit
+
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+func TestSetup_NoneStillPropagates(t *testing.T) {
+	shutdown, err := Setup(context.Background(), "test", ExporterNone, 1)
+	require.NoError(t, err)
+
+	carrier := propagation.MapCarrier{
+		"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+	}
+	ctx := otel.GetTextMapPropagator().Extract(context.Background(), carrier)
+
+	out := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, out)
+
+	assert.Equal(t, carrier["traceparent"], out["traceparent"])
+	assert.NoError(t, shutdown(context.Background()))
+}
+
+func TestSetup_UnknownExporter(t *testing.T) {
+	_, err := Setup(context.Background(), "test", "jaeger", 1)
+
+	assert.ErrorIs(t, err, ErrUnknownExporter)
+}
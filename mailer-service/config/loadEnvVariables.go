@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/kelseyhightower/envconfig"
@@ -13,15 +14,60 @@ type Config struct {
 
 	ApiURL string `envconfig:"API_URL" required:"true"`
 
+	DBHost     string `envconfig:"DB_HOST" required:"true"`
+	DBPort     int    `envconfig:"DB_PORT" required:"true"`
+	DBUsername string `envconfig:"DB_USERNAME" required:"true"`
+	DBPassword string `envconfig:"DB_PASSWORD" required:"true"`
+	DBName     string `envconfig:"DB_NAME" required:"true"`
+
+	WebhookSecret string `envconfig:"WEBHOOK_SECRET"`
+	AdminAPIKey   string `envconfig:"ADMIN_API_KEY"`
+
 	MailEmail    string `envconfig:"MAIL_EMAIL" required:"true"`
-	MailPassword string `envconfig:"MAIL_PASSWORD" required:"true"`
+	MailPassword string `envconfig:"MAIL_PASSWORD"`
+	MailUsername string `envconfig:"MAIL_USERNAME"`
 
 	RabbitMQUrl string `envconfig:"RABBITMQ_URL" required:"true"`
 	MQUsername  string `envconfig:"MQ_USERNAME" required:"true"`
 	MQPassword  string `envconfig:"MQ_PASSWORD" required:"true"`
+	MQPrefetch  int    `envconfig:"MQ_PREFETCH"`
+
+	// The client IP is taken from X-Forwarded-For only for requests coming
+	// from these addresses or CIDRs; by default no proxy is trusted.
+	TrustedProxies []string `envconfig:"TRUSTED_PROXIES"`
+
+	// On SIGINT or SIGTERM emails being sent get this long to finish
+	// before connections are closed.
+	ShutdownTimeout time.Duration `envconfig:"SHUTDOWN_TIMEOUT"`
+
+	// Spans are exported to OTLP (see OTEL_EXPORTER_OTLP_ENDPOINT), to stdout
+	// or nowhere; this share of new traces is sampled, 0 sampling none.
+	TracingExporter    string  `envconfig:"TRACING_EXPORTER"`
+	TracingSampleRatio float64 `envconfig:"TRACING_SAMPLE_RATIO" default:"1"`
+
+	// /readyz gives each dependency check this long; the SMTP server is
+	// dialed at most once per TTL.
+	ReadinessCheckTimeout time.Duration `envconfig:"READINESS_CHECK_TIMEOUT"`
+	ReadinessSMTPTTL      time.Duration `envconfig:"READINESS_SMTP_TTL"`
 
-	MailDialerHost string `envconfig:"MAIL_DIALER_HOST"`
-	MailDialerPort int    `envconfig:"MAIL_DIALER_PORT"`
+	MailTransport    string `envconfig:"MAIL_TRANSPORT"`
+	MailTemplatesDir string `envconfig:"MAIL_TEMPLATES_DIR"`
+
+	MailDialerHost      string        `envconfig:"MAIL_DIALER_HOST"`
+	MailDialerPort      int           `envconfig:"MAIL_DIALER_PORT"`
+	MailSMTPTLSMode     string        `envconfig:"MAIL_SMTP_TLS_MODE"`
+	MailSMTPPoolSize    int           `envconfig:"MAIL_SMTP_POOL_SIZE"`
+	MailSMTPIdleTimeout time.Duration `envconfig:"MAIL_SMTP_IDLE_TIMEOUT"`
+
+	MailFileDir string `envconfig:"MAIL_FILE_DIR"`
+
+	MailRatePerSecond       float64 `envconfig:"MAIL_RATE_PER_SECOND"`
+	MailRateBurst           int     `envconfig:"MAIL_RATE_BURST"`
+	MailDomainRatePerSecond float64 `envconfig:"MAIL_DOMAIN_RATE_PER_SECOND"`
+	MailDomainRateBurst     int     `envconfig:"MAIL_DOMAIN_RATE_BURST"`
+
+	MailHTTPUrl    string `envconfig:"MAIL_HTTP_URL"`
+	MailHTTPApiKey string `envconfig:"MAIL_HTTP_API_KEY"`
 }
 
 func LoadEnvVariables() (*Config, error) {
@@ -60,12 +106,25 @@ func (c *Config) validate() error {
 		errors = append(errors, "API_URL is required")
 	}
 
+	if c.DBHost == "" {
+		errors = append(errors, "DB_HOST is required")
+	}
+	if c.DBPort == 0 {
+		c.DBPort = 5432 // Default PostgreSQL port
+	}
+	if c.DBUsername == "" {
+		errors = append(errors, "DB_USERNAME is required")
+	}
+	if c.DBPassword == "" {
+		errors = append(errors, "DB_PASSWORD is required")
+	}
+	if c.DBName == "" {
+		errors = append(errors, "DB_NAME is required")
+	}
+
 	if c.MailEmail == "" {
 		errors = append(errors, "MAIL_EMAIL is required")
 	}
-	if c.MailPassword == "" {
-		errors = append(errors, "MAIL_PASSWORD is required")
-	}
 
 	if c.RabbitMQUrl == "" {
 		errors = append(errors, "RABBITMQ_URL is required")
@@ -77,17 +136,115 @@ func (c *Config) validate() error {
 		errors = append(errors, "MQ_PASSWORD is required")
 	}
 
-	if c.MailDialerHost == "" {
-		c.MailDialerHost = "smtp.gmail.com" // Default SMTP host
+	if c.MQPrefetch == 0 {
+		c.MQPrefetch = 10
+	}
+
+	if c.ShutdownTimeout <= 0 {
+		c.ShutdownTimeout = 30 * time.Second
+	}
+
+	if c.TracingExporter == "" {
+		c.TracingExporter = "none"
+	}
+	if c.TracingSampleRatio < 0 || c.TracingSampleRatio > 1 {
+		errors = append(errors, "TRACING_SAMPLE_RATIO must be between 0 and 1")
 	}
 
-	if c.MailDialerPort == 0 {
-		c.MailDialerPort = 587 // Default SMTP port
+	if c.ReadinessCheckTimeout <= 0 {
+		c.ReadinessCheckTimeout = 2 * time.Second
+	}
+	if c.ReadinessSMTPTTL <= 0 {
+		c.ReadinessSMTPTTL = 30 * time.Second
 	}
 
+	errors = append(errors, c.validateMailTransport()...)
+	errors = append(errors, c.validateRateLimits()...)
+
 	if len(errors) > 0 {
 		return fmt.Errorf("missing required environment variables: %v", errors)
 	}
 
 	return nil
 }
+
+func (c *Config) validateMailTransport() []string {
+	errors := []string{}
+
+	if c.MailTransport == "" {
+		c.MailTransport = "smtp"
+	}
+
+	switch c.MailTransport {
+	case "smtp":
+		if c.MailDialerHost == "" {
+			c.MailDialerHost = "smtp.gmail.com" // Default SMTP host
+		}
+		if c.MailDialerPort == 0 {
+			c.MailDialerPort = 587 // Default SMTP port
+		}
+		// a password without a username authenticates as the sender address
+		if c.MailUsername == "" && c.MailPassword != "" {
+			c.MailUsername = c.MailEmail
+		}
+		if c.MailSMTPPoolSize == 0 {
+			c.MailSMTPPoolSize = 2
+		}
+		if c.MailSMTPIdleTimeout == 0 {
+			c.MailSMTPIdleTimeout = 30 * time.Second
+		}
+	case "file":
+		if c.MailFileDir == "" {
+			c.MailFileDir = "./mail"
+		}
+	case "http":
+		if c.MailHTTPUrl == "" {
+			errors = append(errors, "MAIL_HTTP_URL is required for http transport")
+		}
+		if c.MailHTTPApiKey == "" {
+			errors = append(errors, "MAIL_HTTP_API_KEY is required for http transport")
+		}
+	default:
+		errors = append(errors, "MAIL_TRANSPORT must be one of smtp, file, http")
+	}
+
+	return errors
+}
+
+// A negative rate disables the limit, zero values get the defaults.
+func (c *Config) validateRateLimits() []string {
+	errors := []string{}
+
+	if c.MailRatePerSecond == 0 {
+		c.MailRatePerSecond = 10
+	}
+	if c.MailRateBurst == 0 {
+		c.MailRateBurst = 20
+	}
+	if c.MailDomainRatePerSecond == 0 {
+		c.MailDomainRatePerSecond = 5
+	}
+	if c.MailDomainRateBurst == 0 {
+		c.MailDomainRateBurst = 10
+	}
+
+	if c.MailRateBurst < 0 || c.MailDomainRateBurst < 0 {
+		errors = append(errors, "MAIL_RATE_BURST and MAIL_DOMAIN_RATE_BURST must be positive")
+	}
+	if c.MQPrefetch < 0 {
+		errors = append(errors, "MQ_PREFETCH must be positive")
+	}
+
+	return errors
+}
+
+func (c *Config) GetDSNString() string {
+	host := c.DBHost
+	port := c.DBPort
+	user := c.DBUsername
+	password := c.DBPassword
+	dbName := c.DBName
+
+	dsn := fmt.Sprintf("host=%v user=%v password=%v dbname=%v port=%v sslmode=disable", host, user, password, dbName, port)
+	return dsn
+}
@@ -1,20 +1,42 @@
 package app
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
 	"strconv"
+	"syscall"
 
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/health"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/lifecycle"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/logger"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/rabbitmq"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/tracing"
 	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/mailer-service/config"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/mailer-service/internal/db"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/mailer-service/internal/delivery"
 	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/mailer-service/internal/emailBuilder"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/mailer-service/internal/i18n"
 	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/mailer-service/internal/mailer"
-	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/mailer-service/internal/rabbitmq"
-	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/mailer-service/logger"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/mailer-service/internal/middleware"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/mailer-service/internal/repository"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/mailer-service/internal/routes"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/mailer-service/internal/suppression"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/mailer-service/internal/transport"
 	"github.com/gin-gonic/gin"
-	"gopkg.in/gomail.v2"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"gorm.io/gorm"
 )
 
+const serviceName = "mailer-service"
+
 func Run() error {
+	ctx, stopSignals := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stopSignals()
 
 	logger, err := logger.NewLogger()
 	if err != nil {
@@ -35,42 +57,163 @@ func Run() error {
 		return err
 	}
 
+	// Steps are stopped in reverse: the HTTP server and the consumers
+	// first, then the mail transport, the database, RabbitMQ and tracing.
+	shutdown := lifecycle.New(*logger)
+	defer func() {
+		if err := shutdown.Stop(config.ShutdownTimeout); err != nil {
+			logger.Error("Shutdown finished with errors", "error", err)
+			return
+		}
+		logger.Info("Shutdown complete")
+	}()
+
+	shutdownTracing, err := tracing.Setup(ctx, serviceName, config.TracingExporter, config.TracingSampleRatio)
+	if err != nil {
+		return err
+	}
+	shutdown.OnStop("tracing", shutdownTracing)
+
 	rabbit, err := rabbitmq.ConnectToRabbitMQ(config.RabbitMQUrl, *logger)
 	if err != nil {
 		return err
 	}
-	defer rabbit.Conn.Close()
-	defer rabbit.Channel.Close()
+	shutdown.OnStop("rabbitmq", lifecycle.Closer(rabbit.Close))
 
 	if err := declareQueues(rabbit); err != nil {
 		return err
 	}
 
-	initServices(*config, *rabbit, *logger)
+	// Unacked messages are capped, so a rate limited send holds back
+	// delivery from the broker instead of piling up in memory.
+	if err := rabbit.Channel.Qos(config.MQPrefetch, 0, false); err != nil {
+		return fmt.Errorf("failed to set RabbitMQ prefetch: %w", err)
+	}
+
+	db, err := db.ConnectToDatabase(*config, *logger)
+	if err != nil {
+		return err
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		logger.Error("Failed to get sql.DB from gorm.DB", "error", err)
+		return err
+	}
+	shutdown.OnStop("database", lifecycle.Closer(sqlDB.Close))
+
+	mailTransport, err := transport.NewTransport(*config, *logger)
+	if err != nil {
+		return err
+	}
+	shutdown.OnStop("mail transport", lifecycle.Closer(mailTransport.Close))
+
+	services, err := initServices(*config, db, *rabbit, mailTransport, *logger)
+	if err != nil {
+		return err
+	}
+
+	rabbitmqConsumer := rabbitmq.NewRabbitMQConsumer(rabbit.Channel, *logger)
+	services.mailerService.StartEmailWorker(rabbitmqConsumer)
+	shutdown.OnStop("rabbitmq consumers", rabbitmqConsumer.Shutdown)
 
 	router := gin.Default()
+	if err := router.SetTrustedProxies(config.TrustedProxies); err != nil {
+		return fmt.Errorf("invalid TRUSTED_PROXIES: %w", err)
+	}
+	router.Use(otelgin.Middleware(serviceName))
+
+	checker := health.NewChecker(config.ReadinessCheckTimeout, *logger)
+	checker.Add("postgres", sqlDB.PingContext)
+	checker.Add("rabbitmq", rabbit.Check)
+	checker.Add("rabbitmq_consumers", rabbitmqConsumer.Check)
+	if config.MailTransport == transport.TransportSMTP {
+		smtpAddress := net.JoinHostPort(config.MailDialerHost, strconv.Itoa(config.MailDialerPort))
+		checker.Add("smtp", health.Cached(health.Dial(smtpAddress), config.ReadinessSMTPTTL))
+	}
+	routes.HealthRoute(router, health.NewHealthController(checker))
+
+	initRoutes(router, *config, services, *logger)
+
+	server := &http.Server{
+		Addr:    ":" + strconv.Itoa(config.MailerPort),
+		Handler: router,
+	}
+	shutdown.OnStop("http server", server.Shutdown)
 
-	port := strconv.Itoa(config.MailerPort)
-	return router.Run(":" + port)
+	return lifecycle.Serve(ctx, server, *logger)
 }
 
-func initServices(config config.Config, rabbit rabbitmq.RabbitMQ, logger logger.Logger) {
-	emailBuilder := emailBuilder.NewWeatherEmailBuilder(config.ApiURL, logger)
+func initServices(config config.Config, database *gorm.DB, rabbit rabbitmq.RabbitMQ,
+	mailTransport transport.Transport, logger logger.Logger) (*Services, error) {
+	templates, err := emailBuilder.TemplatesFS(config.MailTemplatesDir)
+	if err != nil {
+		return nil, err
+	}
+
+	renderer, err := emailBuilder.NewTemplateRenderer(templates)
+	if err != nil {
+		return nil, err
+	}
+
+	catalog, err := i18n.LoadCatalog(templates)
+	if err != nil {
+		return nil, err
+	}
+
+	emailBuilder := emailBuilder.NewWeatherEmailBuilder(config.ApiURL, renderer, catalog, logger)
+
+	suppressionRepo := repository.NewSuppressionRepository(database)
+	eventPublisher := rabbitmq.NewRabbitMQPublisher(rabbit.Channel)
+	suppressionService := suppression.NewSuppressionService(suppressionRepo, eventPublisher, logger)
+
+	deliveryRepo := repository.NewDeliveryRepository(database)
+	deliveryService := delivery.NewDeliveryService(deliveryRepo, logger)
 
-	mailEmail := config.MailEmail
-	dialer := gomail.NewDialer(config.MailDialerHost, config.MailDialerPort, mailEmail, config.MailPassword)
-	mailerService := mailer.NewMailerService(mailEmail, dialer, emailBuilder, logger)
+	mailerService := mailer.NewMailerService(config.MailEmail, mailTransport, emailBuilder,
+		suppressionService, deliveryService, logger)
 
-	rabbitmqConsumer := rabbitmq.NewRabbitMQConsumer(rabbit.Channel, logger)
+	return &Services{
+		mailerService:      mailerService,
+		suppressionService: suppressionService,
+		deliveryService:    deliveryService,
+	}, nil
+}
+
+func initRoutes(router *gin.Engine, config config.Config, services *Services, logger logger.Logger) {
+	suppressionController := suppression.NewSuppressionController(services.suppressionService)
+	deliveryController := delivery.NewDeliveryController(services.deliveryService)
+	previewController := mailer.NewPreviewController(services.mailerService)
+
+	// endpoints without a configured secret stay disabled rather than open
+	if config.WebhookSecret != "" {
+		routes.WebhookRoute(router.Group("/webhooks"), config.WebhookSecret, suppressionController)
+	} else {
+		logger.Info("WEBHOOK_SECRET is not set, bounce webhook is disabled")
+	}
 
-	go mailerService.StartEmailWorker(rabbitmqConsumer)
+	if config.AdminAPIKey != "" {
+		admin := router.Group("/admin", middleware.RequireSecret("X-API-Key", config.AdminAPIKey))
+		routes.SuppressionRoute(admin, suppressionController)
+		routes.DeliveryRoute(admin, deliveryController)
+		routes.PreviewRoute(admin, previewController)
+	} else {
+		logger.Info("ADMIN_API_KEY is not set, admin endpoints are disabled")
+	}
+}
 
+type Services struct {
+	mailerService      *mailer.MailService
+	suppressionService *suppression.SuppressionService
+	deliveryService    *delivery.DeliveryService
 }
 
 func declareQueues(r *rabbitmq.RabbitMQ) error {
 	queues := []string{
 		rabbitmq.SendEmail,
 		rabbitmq.WeatherUpdate,
+		rabbitmq.WeatherDigest,
+		rabbitmq.SubscriptionSuppressed,
 	}
 
 	for _, q := range queues {
@@ -0,0 +1,35 @@
+package db
+
+import (
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/logger"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/mailer-service/config"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/plugin/opentelemetry/tracing"
+)
+
+func ConnectToDatabase(config config.Config, logger logger.Logger) (*gorm.DB, error) {
+
+	dsn := config.GetDSNString()
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		logger.Error("Failed to connect to database", "error", err)
+		return nil, err
+	}
+
+	logger.Info("Connected to database", "host", config.DBHost, "dbname", config.DBName)
+
+	// queries run with a message or request context become spans of its trace
+	if err := db.Use(tracing.NewPlugin(tracing.WithoutMetrics())); err != nil {
+		logger.Error("Failed to enable database tracing", "error", err)
+		return nil, err
+	}
+
+	if err := AutomatedMigration(db); err != nil {
+		logger.Error("Failed to run database migrations", "error", err)
+		return nil, err
+	}
+
+	return db, nil
+}
@@ -0,0 +1,11 @@
+package db
+
+import (
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/mailer-service/internal/delivery"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/mailer-service/internal/suppression"
+	"gorm.io/gorm"
+)
+
+func AutomatedMigration(db *gorm.DB) error {
+	return db.AutoMigrate(&suppression.Suppression{}, &delivery.Delivery{})
+}
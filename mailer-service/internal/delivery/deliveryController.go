@@ -0,0 +1,48 @@
+package delivery
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type deliveryService interface {
+	GetByMessageID(ctx context.Context, messageID string) (*Delivery, error)
+	ListByRecipient(ctx context.Context, recipient string, limit int) ([]Delivery, error)
+}
+
+type DeliveryController struct {
+	service deliveryService
+}
+
+func NewDeliveryController(service deliveryService) *DeliveryController {
+	return &DeliveryController{service: service}
+}
+
+func (dc *DeliveryController) ListByRecipient(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "0"))
+	if err != nil {
+		c.String(http.StatusBadRequest, "invalid limit")
+		return
+	}
+
+	entries, err := dc.service.ListByRecipient(c.Request.Context(), c.Query("recipient"), limit)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
+
+func (dc *DeliveryController) GetByMessageID(c *gin.Context) {
+	entry, err := dc.service.GetByMessageID(c.Request.Context(), c.Param("messageId"))
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, entry)
+}
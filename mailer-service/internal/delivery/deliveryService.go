@@ -0,0 +1,84 @@
+package delivery
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/logger"
+	"gorm.io/gorm"
+)
+
+type deliveryRepository interface {
+	RecordAttempt(ctx context.Context, d Delivery) error
+	FindByMessageID(ctx context.Context, messageID string) (*Delivery, error)
+	FindByRecipient(ctx context.Context, recipient string, limit int) ([]Delivery, error)
+}
+
+type DeliveryService struct {
+	repository deliveryRepository
+	logger     logger.Logger
+}
+
+func NewDeliveryService(repository deliveryRepository, logger logger.Logger) *DeliveryService {
+	return &DeliveryService{
+		repository: repository,
+		logger:     logger,
+	}
+}
+
+// Record stores the outcome of a send attempt. Failures are only logged,
+// the delivery log must never block sending.
+func (s *DeliveryService) Record(ctx context.Context, attempt Attempt) {
+	entry := Delivery{
+		MessageID:         attempt.MessageID,
+		SubscriptionToken: attempt.SubscriptionToken,
+		EmailType:         attempt.EmailType,
+		Recipient:         strings.ToLower(attempt.Recipient),
+		Status:            attempt.Status,
+		Attempts:          1,
+		ProviderResponse:  attempt.ProviderResponse,
+	}
+
+	if attempt.Status == StatusSent {
+		now := time.Now()
+		entry.SentAt = &now
+	}
+
+	if err := s.repository.RecordAttempt(ctx, entry); err != nil {
+		s.logger.WithContext(ctx).Error("Failed to save delivery", "messageID", attempt.MessageID, "error", err)
+	}
+}
+
+func (s *DeliveryService) GetByMessageID(ctx context.Context, messageID string) (*Delivery, error) {
+	entry, err := s.repository.FindByMessageID(ctx, messageID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrDeliveryNotFound
+	}
+	if err != nil {
+		s.logger.WithContext(ctx).Error("Failed to load delivery", "messageID", messageID, "error", err)
+		return nil, ErrFailedToLoad
+	}
+	return entry, nil
+}
+
+// ListByRecipient returns the newest deliveries to recipient first.
+func (s *DeliveryService) ListByRecipient(ctx context.Context, recipient string, limit int) ([]Delivery, error) {
+	recipient = strings.ToLower(strings.TrimSpace(recipient))
+	if recipient == "" {
+		return nil, ErrRecipientRequired
+	}
+
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+	limit = min(limit, maxListLimit)
+
+	entries, err := s.repository.FindByRecipient(ctx, recipient, limit)
+	if err != nil {
+		s.logger.WithContext(ctx).Error("Failed to load deliveries", "recipient", recipient, "error", err)
+		return nil, ErrFailedToLoad
+	}
+	return entries, nil
+}
@@ -0,0 +1,118 @@
+//go:build unit
+// +build unit
+
+package delivery
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// --- Mocks ---
+
+type mockRepository struct {
+	mock.Mock
+}
+
+func (m *mockRepository) RecordAttempt(_ context.Context, d Delivery) error {
+	args := m.Called(d)
+	return args.Error(0)
+}
+
+func (m *mockRepository) FindByMessageID(_ context.Context, messageID string) (*Delivery, error) {
+	args := m.Called(messageID)
+	if d, ok := args.Get(0).(*Delivery); ok {
+		return d, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockRepository) FindByRecipient(_ context.Context, recipient string, limit int) ([]Delivery, error) {
+	args := m.Called(recipient, limit)
+	return args.Get(0).([]Delivery), args.Error(1)
+}
+
+// --- Tests ---
+
+func setupDeliveryTest(t *testing.T) (*mockRepository, *DeliveryService) {
+	repo := new(mockRepository)
+	mockLog, _ := logger.NewTestLogger()
+	return repo, NewDeliveryService(repo, *mockLog)
+}
+
+func TestRecord_FirstAttempt(t *testing.T) {
+	repo, service := setupDeliveryTest(t)
+
+	repo.On("RecordAttempt", mock.MatchedBy(func(d Delivery) bool {
+		return d.MessageID == "msg-1" && d.Recipient == "user@example.com" &&
+			d.SubscriptionToken == "token123" && d.Status == StatusSent &&
+			d.Attempts == 1 && d.SentAt != nil
+	})).Return(nil)
+
+	service.Record(context.Background(), Attempt{
+		MessageID:         "msg-1",
+		SubscriptionToken: "token123",
+		EmailType:         "ConfirmSuccess",
+		Recipient:         "User@Example.com",
+		Status:            StatusSent,
+	})
+
+	repo.AssertExpectations(t)
+}
+
+func TestRecord_FailedAttemptHasNoSentAt(t *testing.T) {
+	repo, service := setupDeliveryTest(t)
+
+	repo.On("RecordAttempt", mock.MatchedBy(func(d Delivery) bool {
+		return d.MessageID == "msg-1" && d.Status == StatusFailed &&
+			d.ProviderResponse == "550 rejected" && d.SentAt == nil
+	})).Return(nil)
+
+	service.Record(context.Background(), Attempt{MessageID: "msg-1", Status: StatusFailed, ProviderResponse: "550 rejected"})
+
+	repo.AssertExpectations(t)
+}
+
+func TestRecord_SaveErrorIsOnlyLogged(t *testing.T) {
+	repo, service := setupDeliveryTest(t)
+
+	repo.On("RecordAttempt", mock.Anything).Return(errors.New("connection refused")).Once()
+
+	assert.NotPanics(t, func() {
+		service.Record(context.Background(), Attempt{MessageID: "msg-1", Status: StatusSent})
+	})
+	repo.AssertExpectations(t)
+}
+
+func TestGetByMessageID_NotFound(t *testing.T) {
+	repo, service := setupDeliveryTest(t)
+
+	repo.On("FindByMessageID", "missing").Return(nil, gorm.ErrRecordNotFound)
+
+	_, err := service.GetByMessageID(context.Background(), "missing")
+	assert.ErrorIs(t, err, ErrDeliveryNotFound)
+}
+
+func TestListByRecipient(t *testing.T) {
+	repo, service := setupDeliveryTest(t)
+
+	repo.On("FindByRecipient", "user@example.com", defaultListLimit).Return([]Delivery{{MessageID: "msg-1"}}, nil)
+	repo.On("FindByRecipient", "user@example.com", maxListLimit).Return([]Delivery{}, nil)
+
+	entries, err := service.ListByRecipient(context.Background(), " User@example.com", 0)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	_, err = service.ListByRecipient(context.Background(), "user@example.com", 10000)
+	require.NoError(t, err)
+
+	_, err = service.ListByRecipient(context.Background(), "", 10)
+	assert.ErrorIs(t, err, ErrRecipientRequired)
+}
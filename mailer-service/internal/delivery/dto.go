@@ -0,0 +1,24 @@
+package delivery
+
+import "errors"
+
+var (
+	ErrRecipientRequired = errors.New("recipient query parameter is required")
+	ErrDeliveryNotFound  = errors.New("delivery not found")
+	ErrFailedToLoad      = errors.New("failed to load deliveries")
+)
+
+// Attempt is the outcome of one try to send an email.
+type Attempt struct {
+	MessageID         string
+	SubscriptionToken string
+	EmailType         string
+	Recipient         string
+	Status            Status
+	ProviderResponse  string
+}
+
+const (
+	defaultListLimit = 50
+	maxListLimit     = 500
+)
@@ -0,0 +1,21 @@
+package delivery
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func HandleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrRecipientRequired):
+		c.String(http.StatusBadRequest, err.Error())
+
+	case errors.Is(err, ErrDeliveryNotFound):
+		c.String(http.StatusNotFound, err.Error())
+
+	default:
+		c.String(http.StatusInternalServerError, err.Error())
+	}
+}
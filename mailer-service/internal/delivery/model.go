@@ -0,0 +1,27 @@
+package delivery
+
+import "time"
+
+type Status string
+
+const (
+	StatusSent       Status = "sent"
+	StatusFailed     Status = "failed"
+	StatusSuppressed Status = "suppressed"
+)
+
+// Delivery is the latest state of one email. Every redelivery of the same
+// job is another attempt on the same row.
+type Delivery struct {
+	ID                uint       `gorm:"primaryKey" json:"-"`
+	MessageID         string     `gorm:"uniqueIndex;not null" json:"message_id"`
+	SubscriptionToken string     `gorm:"index" json:"subscription_token"`
+	EmailType         string     `gorm:"type:varchar(32);not null" json:"email_type"`
+	Recipient         string     `gorm:"index;not null" json:"recipient"`
+	Status            Status     `gorm:"type:varchar(16);not null" json:"status"`
+	Attempts          int        `gorm:"not null;default:0" json:"attempts"`
+	ProviderResponse  string     `json:"provider_response,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+	SentAt            *time.Time `json:"sent_at,omitempty"`
+}
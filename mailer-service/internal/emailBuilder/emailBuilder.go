@@ -1,80 +1,171 @@
 package emailBuilder
 
 import (
-	"fmt"
-	"html"
 	"time"
 
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/logger"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/mailer-service/internal/i18n"
 	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/mailer-service/internal/mailer"
-	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/mailer-service/logger"
 )
 
+type templateRenderer interface {
+	Render(emailType mailer.EmailType, data any) (mailer.EmailContent, error)
+}
+
+type catalog interface {
+	Localizer(language string) i18n.Localizer
+}
+
+// Template data types embed the subscriber's localizer, so templates can call
+// {{.T "key"}}, {{.HTML "key"}}, {{.N "key" count}}, {{.Date t}} and
+// {{.Decimal v 1}}.
+type confirmationData struct {
+	i18n.Localizer
+	City             string
+	FrequencyKey     string
+	ConfirmationLink string
+}
+
+type confirmSuccessData struct {
+	i18n.Localizer
+	City            string
+	EmailsPerDay    int
+	UnsubscribeLink string
+}
+
+type weatherUpdateData struct {
+	i18n.Localizer
+	City            string
+	SentAt          time.Time
+	Temperature     float64
+	Humidity        float64
+	Description     string
+	Forecast        *mailer.ForecastDTO
+	UnsubscribeLink string
+}
+
+type digestData struct {
+	i18n.Localizer
+	SentAt time.Time
+	Cities []digestCity
+}
+
+type digestCity struct {
+	City            string
+	Temperature     float64
+	Humidity        float64
+	Description     string
+	Forecast        *mailer.ForecastDTO
+	UnsubscribeLink string
+}
+
 type WeatherEmailBuilder struct {
-	appUrl string
-	logger logger.Logger
+	appUrl   string
+	renderer templateRenderer
+	catalog  catalog
+	logger   logger.Logger
 }
 
-func NewWeatherEmailBuilder(appUrl string, logger logger.Logger) *WeatherEmailBuilder {
+func NewWeatherEmailBuilder(appUrl string, renderer templateRenderer,
+	catalog catalog, logger logger.Logger) *WeatherEmailBuilder {
 	return &WeatherEmailBuilder{
-		appUrl: appUrl,
-		logger: logger,
+		appUrl:   appUrl,
+		renderer: renderer,
+		catalog:  catalog,
+		logger:   logger,
 	}
 }
 
+// BuildWeatherUpdateEmail renders the current conditions, followed by
+// today's forecast when one is given.
 func (w *WeatherEmailBuilder) BuildWeatherUpdateEmail(
 	sub mailer.SubscriptionDTO,
 	weather mailer.WeatherDTO,
-	time time.Time) string {
-
-	unsubscribeLink := w.buildURL("/api/unsubscribe/") + sub.Token
-
-	escapedCity := html.EscapeString(sub.City)
-	escapedDescription := html.EscapeString(weather.Description)
-
-	return fmt.Sprintf(`
-		<p><strong>Weather update for %s</strong></p>
-		<p><strong>Date:</strong> %s<br>
-		<strong>Time:</strong> %s</p>
-		<p><strong>Temperature:</strong> %.1f°C<br>
-		<strong>Humidity:</strong> %.0f%%<br>
-		<strong>Description:</strong> %s</p>
-		<p><a href="%s">Unsubscribe here</a></p>`,
-		escapedCity,
-		time.Format("January 2, 2006"),
-		time.Format("15:04"),
-		weather.Temperature,
-		weather.Humidity,
-		escapedDescription,
-		unsubscribeLink,
-	)
-}
-
-func (w *WeatherEmailBuilder) BuildConfirmationEmail(sub mailer.SubscriptionDTO) string {
+	forecast *mailer.ForecastDTO,
+	time time.Time) (mailer.EmailContent, error) {
+
+	return w.render(sub, mailer.EmailTypeWeatherUpdate, weatherUpdateData{
+		Localizer:       w.catalog.Localizer(sub.Language),
+		City:            sub.City,
+		SentAt:          time,
+		Temperature:     weather.Temperature,
+		Humidity:        weather.Humidity,
+		Description:     weather.Description,
+		Forecast:        forecast,
+		UnsubscribeLink: w.unsubscribeURL(sub),
+	})
+}
+
+func (w *WeatherEmailBuilder) BuildConfirmationEmail(sub mailer.SubscriptionDTO) (mailer.EmailContent, error) {
 	confirmationLink := w.buildURL("/api/confirm/") + sub.Token
 
 	w.logger.Info("Building confirmation email", "confirmationLink", confirmationLink)
 
-	escapedCity := html.EscapeString(sub.City)
+	return w.render(sub, mailer.EmailTypeCreateSubscription, confirmationData{
+		Localizer:        w.catalog.Localizer(sub.Language),
+		City:             sub.City,
+		FrequencyKey:     "frequency." + string(sub.Frequency),
+		ConfirmationLink: confirmationLink,
+	})
+}
 
-	return fmt.Sprintf(`
-		<p>Hello from Weather Updates!</p>
-		<p>You subscribed for <strong>%s</strong> updates for <strong>%s</strong> weather.</p>
-		<p>Please confirm your subscription by clicking the link below:</p>
-		<p><a href="%s">Your link</a></p>`,
-		string(sub.Frequency), escapedCity, confirmationLink)
+func (w *WeatherEmailBuilder) BuildConfirmSuccessEmail(sub mailer.SubscriptionDTO) (mailer.EmailContent, error) {
+	return w.render(sub, mailer.EmailTypeConfirmSuccess, confirmSuccessData{
+		Localizer:       w.catalog.Localizer(sub.Language),
+		City:            sub.City,
+		EmailsPerDay:    emailsPerDay(sub.Frequency),
+		UnsubscribeLink: w.unsubscribeURL(sub),
+	})
 }
 
-func (w *WeatherEmailBuilder) BuildConfirmSuccessEmail(sub mailer.SubscriptionDTO) string {
-	unsubscribeLink := w.buildURL("/api/unsubscribe/") + sub.Token
-	return fmt.Sprintf(`
-		<p>Hello from Weather Updates!</p>
-		<p>You have successfully confirmed your subscription!</p>
-		<p>If you want to unsubscribe, click the link below:</p>
-		<p><a href="%s">Your link</a></p>`,
-		unsubscribeLink)
+// BuildDigestEmail renders the weather of several cities in one email, each
+// with its own unsubscribe link and, in daily digests, a short forecast. The email has no List-Unsubscribe header:
+// one-click unsubscribe cannot tell which of the cities to drop.
+func (w *WeatherEmailBuilder) BuildDigestEmail(language string,
+	items []mailer.DigestItem, time time.Time) (mailer.EmailContent, error) {
+	cities := make([]digestCity, 0, len(items))
+
+	for _, item := range items {
+		cities = append(cities, digestCity{
+			City:            item.Subscription.City,
+			Temperature:     item.Weather.Temperature,
+			Humidity:        item.Weather.Humidity,
+			Description:     item.Weather.Description,
+			Forecast:        item.Forecast,
+			UnsubscribeLink: w.unsubscribeURL(item.Subscription),
+		})
+	}
+
+	return w.renderer.Render(mailer.EmailTypeWeatherDigest, digestData{
+		Localizer: w.catalog.Localizer(language),
+		SentAt:    time,
+		Cities:    cities,
+	})
+}
+
+func (w *WeatherEmailBuilder) render(sub mailer.SubscriptionDTO,
+	emailType mailer.EmailType, data any) (mailer.EmailContent, error) {
+	content, err := w.renderer.Render(emailType, data)
+	if err != nil {
+		return mailer.EmailContent{}, err
+	}
+
+	content.UnsubscribeURL = w.unsubscribeURL(sub)
+	return content, nil
+}
+
+func (w *WeatherEmailBuilder) unsubscribeURL(sub mailer.SubscriptionDTO) string {
+	return w.buildURL("/api/unsubscribe/") + sub.Token
 }
 
 func (w *WeatherEmailBuilder) buildURL(path string) string {
 	w.logger.Info("Building URL", "appUrl", w.appUrl, "path", path)
 	return w.appUrl + path
 }
+
+func emailsPerDay(freq mailer.Frequency) int {
+	if freq == mailer.FrequencyHourly {
+		return 24
+	}
+	return 1
+}
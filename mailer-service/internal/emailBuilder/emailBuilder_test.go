@@ -0,0 +1,230 @@
+//go:build unit
+// +build unit
+
+package emailBuilder
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/logger"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/mailer-service/internal/i18n"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/mailer-service/internal/mailer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func setupBuilderTest(t *testing.T, overrideDir string) *WeatherEmailBuilder {
+	t.Helper()
+
+	templates, err := TemplatesFS(overrideDir)
+	require.NoError(t, err)
+
+	renderer, err := NewTemplateRenderer(templates)
+	require.NoError(t, err)
+
+	catalog, err := i18n.LoadCatalog(templates)
+	require.NoError(t, err)
+
+	mockLog, _ := logger.NewTestLogger()
+	return NewWeatherEmailBuilder("https://weather.example.com", renderer, catalog, *mockLog)
+}
+
+func assertGolden(t *testing.T, name string, actual string) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name+".golden")
+
+	if *update {
+		require.NoError(t, os.WriteFile(path, []byte(actual), 0o644))
+	}
+
+	expected, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, string(expected), actual)
+}
+
+func TestBuildEmails_Golden(t *testing.T) {
+	builder := setupBuilderTest(t, "")
+
+	weather := mailer.WeatherDTO{Temperature: 21.46, Humidity: 55, Description: "Partly cloudy"}
+	sentAt := time.Date(2025, time.June, 1, 9, 0, 0, 0, time.UTC)
+
+	languages := []struct {
+		language string
+		subjects map[mailer.EmailType]string
+	}{
+		{
+			language: "en",
+			subjects: map[mailer.EmailType]string{
+				mailer.EmailTypeCreateSubscription: "Weather updates confirmation link",
+				mailer.EmailTypeConfirmSuccess:     "Weather updates subscription",
+				mailer.EmailTypeWeatherUpdate:      "Weather Update",
+				mailer.EmailTypeWeatherDigest:      "Your weather digest",
+			},
+		},
+		{
+			language: "uk",
+			subjects: map[mailer.EmailType]string{
+				mailer.EmailTypeCreateSubscription: "Посилання для підтвердження підписки на погоду",
+				mailer.EmailTypeConfirmSuccess:     "Підписка на оновлення погоди",
+				mailer.EmailTypeWeatherUpdate:      "Оновлення погоди",
+				mailer.EmailTypeWeatherDigest:      "Ваш дайджест погоди",
+			},
+		},
+	}
+
+	for _, lang := range languages {
+		sub := mailer.SubscriptionDTO{
+			Email:     "user@example.com",
+			City:      "Kyiv <Center>",
+			Frequency: mailer.FrequencyHourly,
+			Token:     "token123",
+			Language:  lang.language,
+		}
+
+		build := map[mailer.EmailType]func() (mailer.EmailContent, error){
+			mailer.EmailTypeCreateSubscription: func() (mailer.EmailContent, error) {
+				return builder.BuildConfirmationEmail(sub)
+			},
+			mailer.EmailTypeConfirmSuccess: func() (mailer.EmailContent, error) {
+				return builder.BuildConfirmSuccessEmail(sub)
+			},
+			mailer.EmailTypeWeatherUpdate: func() (mailer.EmailContent, error) {
+				return builder.BuildWeatherUpdateEmail(sub, weather, nil, sentAt)
+			},
+			mailer.EmailTypeWeatherDigest: func() (mailer.EmailContent, error) {
+				second := sub
+				second.City = "Lviv"
+				second.Token = "token456"
+
+				return builder.BuildDigestEmail(lang.language, []mailer.DigestItem{
+					{Subscription: sub, Weather: weather},
+					{Subscription: second, Weather: mailer.WeatherDTO{Temperature: 17, Humidity: 70, Description: "Light rain"}},
+				}, sentAt)
+			},
+		}
+
+		require.Len(t, build, len(templateNames), "every email type needs a golden test")
+
+		for emailType, name := range templateNames {
+			t.Run(lang.language+"/"+string(emailType), func(t *testing.T) {
+				content, err := build[emailType]()
+				require.NoError(t, err)
+
+				assert.Equal(t, lang.subjects[emailType], content.Subject)
+				assertGolden(t, name+"."+lang.language+".html", content.HTML)
+				assertGolden(t, name+"."+lang.language+".txt", content.Text)
+			})
+		}
+	}
+}
+
+func TestBuildWeatherUpdateEmail_Forecast_Golden(t *testing.T) {
+	builder := setupBuilderTest(t, "")
+
+	weather := mailer.WeatherDTO{Temperature: 16.2, Humidity: 72, Description: "Sunny"}
+	forecast := &mailer.ForecastDTO{
+		MaxTemperature:      24.4,
+		MinTemperature:      12.6,
+		PrecipitationChance: 40,
+		Sunrise:             "04:47",
+		Sunset:              "21:02",
+		Hourly: []mailer.HourlyForecastDTO{
+			{Time: "09:00", Temperature: 16, PrecipitationChance: 0, Description: "Sunny"},
+			{Time: "12:00", Temperature: 21.5, PrecipitationChance: 10, Description: "Partly cloudy"},
+			{Time: "15:00", Temperature: 24, PrecipitationChance: 40, Description: "Patchy rain"},
+		},
+	}
+	sentAt := time.Date(2025, time.June, 1, 9, 0, 0, 0, time.UTC)
+
+	for _, language := range []string{"en", "uk"} {
+		t.Run(language, func(t *testing.T) {
+			sub := mailer.SubscriptionDTO{
+				Email:     "user@example.com",
+				City:      "Kyiv",
+				Frequency: mailer.FrequencyDaily,
+				Token:     "token123",
+				Language:  language,
+			}
+
+			content, err := builder.BuildWeatherUpdateEmail(sub, weather, forecast, sentAt)
+			require.NoError(t, err)
+
+			assertGolden(t, "weather_update_forecast."+language+".html", content.HTML)
+			assertGolden(t, "weather_update_forecast."+language+".txt", content.Text)
+		})
+	}
+}
+
+func TestBuildDigestEmail_Forecast_Golden(t *testing.T) {
+	builder := setupBuilderTest(t, "")
+
+	sentAt := time.Date(2025, time.June, 1, 9, 0, 0, 0, time.UTC)
+
+	for _, language := range []string{"en", "uk"} {
+		t.Run(language, func(t *testing.T) {
+			kyiv := mailer.SubscriptionDTO{City: "Kyiv", Frequency: mailer.FrequencyDaily, Token: "token123",
+				Language: language}
+			lviv := mailer.SubscriptionDTO{City: "Lviv", Frequency: mailer.FrequencyDaily, Token: "token456",
+				Language: language}
+
+			content, err := builder.BuildDigestEmail(language, []mailer.DigestItem{
+				{Subscription: kyiv, Weather: mailer.WeatherDTO{Temperature: 16.2, Humidity: 72, Description: "Sunny"},
+					Forecast: &mailer.ForecastDTO{MaxTemperature: 24.4, MinTemperature: 12.6,
+						PrecipitationChance: 40, Sunrise: "04:47", Sunset: "21:02"}},
+				// a city whose forecast couldn't be fetched keeps its current conditions
+				{Subscription: lviv, Weather: mailer.WeatherDTO{Temperature: 17, Humidity: 70, Description: "Light rain"}},
+			}, sentAt)
+			require.NoError(t, err)
+
+			assertGolden(t, "digest_forecast."+language+".html", content.HTML)
+			assertGolden(t, "digest_forecast."+language+".txt", content.Text)
+		})
+	}
+}
+
+func TestBuildEmails_UnknownLanguageFallsBackToEnglish(t *testing.T) {
+	builder := setupBuilderTest(t, "")
+
+	content, err := builder.BuildConfirmSuccessEmail(mailer.SubscriptionDTO{Token: "abc", Language: "de"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "Weather updates subscription", content.Subject)
+	assert.Contains(t, content.Text, "You will receive 1 email a day.")
+	assert.Equal(t, "https://weather.example.com/api/unsubscribe/abc", content.UnsubscribeURL)
+}
+
+func TestTemplatesFS_OverrideDirectory(t *testing.T) {
+	dir := t.TempDir()
+	override := `{{define "subject"}}Please confirm{{end}}
+{{define "content"}}Confirm {{.City}}: {{.ConfirmationLink}}{{end}}`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "confirmation.txt"), []byte(override), 0o644))
+
+	builder := setupBuilderTest(t, dir)
+
+	content, err := builder.BuildConfirmationEmail(mailer.SubscriptionDTO{City: "Lviv", Token: "abc"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "Please confirm", content.Subject)
+	assert.Contains(t, content.Text, "Confirm Lviv: https://weather.example.com/api/confirm/abc")
+	// files missing from the override directory fall back to the embedded ones
+	assert.Contains(t, content.HTML, "Please confirm your subscription")
+	assert.Contains(t, content.HTML, `lang="en"`)
+}
+
+func TestRender_UnknownEmailType(t *testing.T) {
+	templates, err := TemplatesFS("")
+	require.NoError(t, err)
+
+	renderer, err := NewTemplateRenderer(templates)
+	require.NoError(t, err)
+
+	_, err = renderer.Render(mailer.EmailType("Unknown"), nil)
+	assert.ErrorIs(t, err, ErrUnknownEmailType)
+}
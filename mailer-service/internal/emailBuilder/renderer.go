@@ -0,0 +1,116 @@
+package emailBuilder
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"os"
+	"strings"
+	texttemplate "text/template"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/mailer-service/internal/mailer"
+)
+
+//go:embed templates
+var embeddedTemplates embed.FS
+
+var ErrUnknownEmailType = errors.New("unknown email type")
+
+var templateNames = map[mailer.EmailType]string{
+	mailer.EmailTypeCreateSubscription: "confirmation",
+	mailer.EmailTypeConfirmSuccess:     "confirm_success",
+	mailer.EmailTypeWeatherUpdate:      "weather_update",
+	mailer.EmailTypeWeatherDigest:      "digest",
+}
+
+type emailTemplate struct {
+	html *htmltemplate.Template
+	text *texttemplate.Template
+}
+
+type TemplateRenderer struct {
+	templates map[mailer.EmailType]emailTemplate
+}
+
+// TemplatesFS returns the embedded templates. When overrideDir is set, files
+// found there take precedence over the embedded ones with the same name.
+func TemplatesFS(overrideDir string) (fs.FS, error) {
+	embedded, err := fs.Sub(embeddedTemplates, "templates")
+	if err != nil {
+		return nil, err
+	}
+
+	if overrideDir == "" {
+		return embedded, nil
+	}
+
+	return overlayFS{override: os.DirFS(overrideDir), base: embedded}, nil
+}
+
+func NewTemplateRenderer(fsys fs.FS) (*TemplateRenderer, error) {
+	templates := make(map[mailer.EmailType]emailTemplate, len(templateNames))
+
+	for emailType, name := range templateNames {
+		html, err := htmltemplate.ParseFS(fsys, "layout.html", name+".html")
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s html template: %w", name, err)
+		}
+
+		text, err := texttemplate.ParseFS(fsys, "layout.txt", name+".txt")
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s text template: %w", name, err)
+		}
+
+		if text.Lookup("subject") == nil {
+			return nil, fmt.Errorf("template %s.txt does not define a subject", name)
+		}
+
+		templates[emailType] = emailTemplate{html: html, text: text}
+	}
+
+	return &TemplateRenderer{templates: templates}, nil
+}
+
+func (r *TemplateRenderer) Render(emailType mailer.EmailType, data any) (mailer.EmailContent, error) {
+	tmpl, ok := r.templates[emailType]
+	if !ok {
+		return mailer.EmailContent{}, fmt.Errorf("%w: %s", ErrUnknownEmailType, emailType)
+	}
+
+	var subject, html, text bytes.Buffer
+
+	if err := tmpl.text.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return mailer.EmailContent{}, fmt.Errorf("failed to render %s subject: %w", emailType, err)
+	}
+	if err := tmpl.html.ExecuteTemplate(&html, "layout", data); err != nil {
+		return mailer.EmailContent{}, fmt.Errorf("failed to render %s html body: %w", emailType, err)
+	}
+	if err := tmpl.text.ExecuteTemplate(&text, "layout", data); err != nil {
+		return mailer.EmailContent{}, fmt.Errorf("failed to render %s text body: %w", emailType, err)
+	}
+
+	return mailer.EmailContent{
+		Subject: strings.TrimSpace(subject.String()),
+		HTML:    html.String(),
+		Text:    text.String(),
+	}, nil
+}
+
+type overlayFS struct {
+	override fs.FS
+	base     fs.FS
+}
+
+func (o overlayFS) Open(name string) (fs.File, error) {
+	file, err := o.override.Open(name)
+	if err == nil {
+		return file, nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	return o.base.Open(name)
+}
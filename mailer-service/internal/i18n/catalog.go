@@ -0,0 +1,162 @@
+package i18n
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+const DefaultLanguage = "en"
+
+// message is either a plain string or a set of plural forms
+// ("one", "few", "many", "other") keyed by CLDR plural category.
+type message struct {
+	text  string
+	forms map[string]string
+}
+
+func (m *message) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &m.text); err == nil {
+		return nil
+	}
+	return json.Unmarshal(data, &m.forms)
+}
+
+type Catalog struct {
+	languages map[string]map[string]message
+}
+
+// LoadCatalog reads every locales/<lang>.json file from fsys.
+// The default language catalogue is required, and every plural message
+// needs an "other" form to fall back to.
+func LoadCatalog(fsys fs.FS) (*Catalog, error) {
+	files, err := fs.Glob(fsys, "locales/*.json")
+	if err != nil {
+		return nil, err
+	}
+
+	languages := make(map[string]map[string]message, len(files))
+
+	for _, file := range files {
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read catalogue %s: %w", file, err)
+		}
+
+		var messages map[string]message
+		if err := json.Unmarshal(data, &messages); err != nil {
+			return nil, fmt.Errorf("failed to parse catalogue %s: %w", file, err)
+		}
+
+		for key, msg := range messages {
+			if _, ok := msg.forms["other"]; msg.forms != nil && !ok {
+				return nil, fmt.Errorf("catalogue %s: plural message %q has no \"other\" form", file, key)
+			}
+		}
+
+		lang := strings.TrimSuffix(path.Base(file), ".json")
+		languages[lang] = messages
+	}
+
+	if _, ok := languages[DefaultLanguage]; !ok {
+		return nil, fmt.Errorf("catalogue for default language %q not found", DefaultLanguage)
+	}
+
+	return &Catalog{languages: languages}, nil
+}
+
+// Localizer returns a localizer for the given language tag ("uk", "uk-UA"),
+// falling back to English for unknown languages.
+func (c *Catalog) Localizer(tag string) Localizer {
+	lang := normalizeTag(tag)
+	if _, ok := c.languages[lang]; !ok {
+		lang = DefaultLanguage
+	}
+
+	return Localizer{
+		lang:     lang,
+		messages: c.languages[lang],
+		fallback: c.languages[DefaultLanguage],
+	}
+}
+
+func normalizeTag(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	return tag
+}
+
+type Localizer struct {
+	lang     string
+	messages map[string]message
+	fallback map[string]message
+}
+
+func (l Localizer) Language() string {
+	return l.lang
+}
+
+// T translates key and formats it with args using fmt verbs.
+// Missing keys fall back to English and then to the key itself.
+func (l Localizer) T(key string, args ...any) string {
+	msg, ok := l.lookup(key)
+	if !ok {
+		return key
+	}
+
+	text := msg.text
+	if msg.forms != nil {
+		text = msg.forms["other"]
+	}
+
+	if len(args) == 0 {
+		return text
+	}
+	return fmt.Sprintf(text, args...)
+}
+
+// HTML translates a key whose message contains markup, such as <strong>.
+// The message is trusted like a template; string args are escaped.
+func (l Localizer) HTML(key string, args ...any) template.HTML {
+	escaped := make([]any, len(args))
+	for i, arg := range args {
+		if s, ok := arg.(string); ok {
+			arg = template.HTMLEscapeString(s)
+		}
+		escaped[i] = arg
+	}
+
+	return template.HTML(l.T(key, escaped...))
+}
+
+// N translates a pluralised key, choosing the form for count. The count is
+// passed as the first format argument, followed by args.
+func (l Localizer) N(key string, count int, args ...any) string {
+	msg, ok := l.lookup(key)
+	if !ok {
+		return key
+	}
+
+	text := msg.text
+	if msg.forms != nil {
+		text, ok = msg.forms[pluralCategory(l.lang, count)]
+		if !ok {
+			text = msg.forms["other"]
+		}
+	}
+
+	return fmt.Sprintf(text, append([]any{count}, args...)...)
+}
+
+func (l Localizer) lookup(key string) (message, bool) {
+	if msg, ok := l.messages[key]; ok {
+		return msg, true
+	}
+	msg, ok := l.fallback[key]
+	return msg, ok
+}
@@ -0,0 +1,120 @@
+//go:build unit
+// +build unit
+
+package i18n
+
+import (
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCatalog(t *testing.T) *Catalog {
+	t.Helper()
+
+	fsys := fstest.MapFS{
+		"locales/en.json": {Data: []byte(`{
+			"greeting": "Hello, %s!",
+			"only_en": "English only",
+			"cities": {"one": "%d city", "other": "%d cities"}
+		}`)},
+		"locales/uk.json": {Data: []byte(`{
+			"greeting": "Привіт, %s!",
+			"cities": {"one": "%d місто", "few": "%d міста", "many": "%d міст", "other": "%d міста"}
+		}`)},
+	}
+
+	catalog, err := LoadCatalog(fsys)
+	require.NoError(t, err)
+	return catalog
+}
+
+func TestLocalizer_Translate(t *testing.T) {
+	catalog := newTestCatalog(t)
+
+	assert.Equal(t, "Hello, Kyiv!", catalog.Localizer("en").T("greeting", "Kyiv"))
+	assert.Equal(t, "Привіт, Kyiv!", catalog.Localizer("uk-UA").T("greeting", "Kyiv"))
+}
+
+func TestLocalizer_Fallbacks(t *testing.T) {
+	catalog := newTestCatalog(t)
+
+	uk := catalog.Localizer("uk")
+	assert.Equal(t, "English only", uk.T("only_en"))
+	assert.Equal(t, "missing.key", uk.T("missing.key"))
+
+	de := catalog.Localizer("de")
+	assert.Equal(t, "en", de.Language())
+	assert.Equal(t, "Hello, Kyiv!", de.T("greeting", "Kyiv"))
+}
+
+func TestLocalizer_Plural(t *testing.T) {
+	catalog := newTestCatalog(t)
+
+	en := catalog.Localizer("en")
+	assert.Equal(t, "1 city", en.N("cities", 1))
+	assert.Equal(t, "3 cities", en.N("cities", 3))
+
+	uk := catalog.Localizer("uk")
+	tests := map[int]string{
+		1:   "1 місто",
+		2:   "2 міста",
+		5:   "5 міст",
+		11:  "11 міст",
+		12:  "12 міст",
+		21:  "21 місто",
+		22:  "22 міста",
+		111: "111 міст",
+	}
+	for count, expected := range tests {
+		assert.Equal(t, expected, uk.N("cities", count))
+	}
+}
+
+func TestLocalizer_Formatting(t *testing.T) {
+	catalog := newTestCatalog(t)
+	date := time.Date(2025, time.March, 8, 14, 5, 0, 0, time.UTC)
+
+	en := catalog.Localizer("en")
+	assert.Equal(t, "March 8, 2025", en.Date(date))
+	assert.Equal(t, "14:05", en.Time(date))
+	assert.Equal(t, "21.5", en.Decimal(21.46, 1))
+
+	uk := catalog.Localizer("uk")
+	assert.Equal(t, "8 березня 2025", uk.Date(date))
+	assert.Equal(t, "21,5", uk.Decimal(21.46, 1))
+}
+
+func TestLoadCatalog_RequiresDefaultLanguage(t *testing.T) {
+	fsys := fstest.MapFS{
+		"locales/uk.json": {Data: []byte(`{"greeting": "Привіт"}`)},
+	}
+
+	_, err := LoadCatalog(fsys)
+	assert.Error(t, err)
+}
+
+func TestLoadCatalog_RequiresOtherPluralForm(t *testing.T) {
+	fsys := fstest.MapFS{
+		"locales/en.json": {Data: []byte(`{"cities": {"one": "%d city", "other": "%d cities"}}`)},
+		"locales/uk.json": {Data: []byte(`{"cities": {"one": "%d місто", "few": "%d міста", "many": "%d міст"}}`)},
+	}
+
+	_, err := LoadCatalog(fsys)
+	assert.ErrorContains(t, err, `"cities"`)
+}
+
+func TestLocalizer_HTMLEscapesArguments(t *testing.T) {
+	fsys := fstest.MapFS{
+		"locales/en.json": {Data: []byte(`{"subscribed": "Updates for <strong>%s</strong>, %d a day"}`)},
+	}
+
+	catalog, err := LoadCatalog(fsys)
+	require.NoError(t, err)
+
+	html := catalog.Localizer("en").HTML("subscribed", "Kyiv <Center>", 24)
+	assert.Equal(t, "Updates for <strong>Kyiv &lt;Center&gt;</strong>, 24 a day", string(html))
+}
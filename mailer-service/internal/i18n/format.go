@@ -0,0 +1,40 @@
+package i18n
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Ukrainian month names in the genitive case, as used in dates ("1 червня").
+var ukrainianMonths = [...]string{
+	"січня", "лютого", "березня", "квітня", "травня", "червня",
+	"липня", "серпня", "вересня", "жовтня", "листопада", "грудня",
+}
+
+func (l Localizer) Date(t time.Time) string {
+	switch l.lang {
+	case "uk":
+		return fmt.Sprintf("%d %s %d", t.Day(), ukrainianMonths[t.Month()-1], t.Year())
+	default:
+		return t.Format("January 2, 2006")
+	}
+}
+
+func (l Localizer) Time(t time.Time) string {
+	return t.Format("15:04")
+}
+
+// Decimal formats v with the given number of decimals and the
+// language's decimal separator.
+func (l Localizer) Decimal(v float64, decimals int) string {
+	formatted := strconv.FormatFloat(v, 'f', decimals, 64)
+
+	switch l.lang {
+	case "uk":
+		return strings.Replace(formatted, ".", ",", 1)
+	default:
+		return formatted
+	}
+}
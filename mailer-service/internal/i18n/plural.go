@@ -0,0 +1,28 @@
+package i18n
+
+// pluralCategory implements the CLDR cardinal plural rules for integers.
+func pluralCategory(lang string, n int) string {
+	if n < 0 {
+		n = -n
+	}
+
+	switch lang {
+	case "uk":
+		mod10 := n % 10
+		mod100 := n % 100
+
+		switch {
+		case mod10 == 1 && mod100 != 11:
+			return "one"
+		case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+			return "few"
+		default:
+			return "many"
+		}
+	default:
+		if n == 1 {
+			return "one"
+		}
+		return "other"
+	}
+}
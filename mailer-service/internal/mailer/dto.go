@@ -1,5 +1,14 @@
 package mailer
 
+import "errors"
+
+var (
+	ErrUnknownEmailType    = errors.New("unknown email type")
+	ErrInvalidRecipient    = errors.New("invalid recipient")
+	ErrRecipientSuppressed = errors.New("recipient is suppressed")
+	ErrSendFailed          = errors.New("failed to send email")
+)
+
 type Frequency string
 
 const (
@@ -13,6 +22,7 @@ type SubscriptionDTO struct {
 	Frequency Frequency
 	Token     string
 	Confirmed bool
+	Language  string
 }
 
 type EmailType string
@@ -20,19 +30,55 @@ type EmailType string
 const (
 	EmailTypeCreateSubscription EmailType = "CreateSubscription"
 	EmailTypeConfirmSuccess     EmailType = "ConfirmSuccess"
+	EmailTypeWeatherUpdate      EmailType = "WeatherUpdate"
+	EmailTypeWeatherDigest      EmailType = "WeatherDigest"
 )
 
+func ParseEmailType(value string) (EmailType, error) {
+	switch EmailType(value) {
+	case EmailTypeCreateSubscription, EmailTypeConfirmSuccess, EmailTypeWeatherUpdate, EmailTypeWeatherDigest:
+		return EmailType(value), nil
+	default:
+		return "", ErrUnknownEmailType
+	}
+}
+
+type EmailContent struct {
+	Subject        string
+	HTML           string
+	Text           string
+	UnsubscribeURL string
+}
+
 type EmailJob struct {
+	MessageID    string
 	To           string
 	EmailType    EmailType
 	Subscription SubscriptionDTO
 }
 
 type WeatherUpdateJob struct {
+	MessageID    string
 	To           string
 	EmailType    string
 	Weather      WeatherDTO
+	Forecast     *ForecastDTO
+	Subscription SubscriptionDTO
+}
+
+type WeatherDigestJob struct {
+	MessageID string
+	To        string
+	Frequency Frequency
+	Language  string
+	Items     []DigestItem
+}
+
+// Forecast is only set in daily digests.
+type DigestItem struct {
 	Subscription SubscriptionDTO
+	Weather      WeatherDTO
+	Forecast     *ForecastDTO
 }
 
 type WeatherDTO struct {
@@ -40,3 +86,21 @@ type WeatherDTO struct {
 	Humidity    float64 `json:"humidity"`
 	Description string  `json:"description"`
 }
+
+// ForecastDTO is today's forecast sent with daily updates. Times are local
+// to the city and already formatted as "15:04".
+type ForecastDTO struct {
+	MaxTemperature      float64             `json:"max_temperature"`
+	MinTemperature      float64             `json:"min_temperature"`
+	PrecipitationChance int                 `json:"precipitation_chance"`
+	Sunrise             string              `json:"sunrise"`
+	Sunset              string              `json:"sunset"`
+	Hourly              []HourlyForecastDTO `json:"hourly"`
+}
+
+type HourlyForecastDTO struct {
+	Time                string  `json:"time"`
+	Temperature         float64 `json:"temperature"`
+	PrecipitationChance int     `json:"precipitation_chance"`
+	Description         string  `json:"description"`
+}
@@ -0,0 +1,25 @@
+package mailer
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func HandleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrUnknownEmailType),
+		errors.Is(err, ErrInvalidRecipient):
+		c.String(http.StatusBadRequest, err.Error())
+
+	case errors.Is(err, ErrRecipientSuppressed):
+		c.String(http.StatusConflict, err.Error())
+
+	case errors.Is(err, ErrSendFailed):
+		c.String(http.StatusBadGateway, err.Error())
+
+	default:
+		c.String(http.StatusInternalServerError, err.Error())
+	}
+}
@@ -1,103 +1,272 @@
 package mailer
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 
-	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/mailer-service/internal/rabbitmq"
-	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/mailer-service/logger"
-
-	"gopkg.in/gomail.v2"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/logger"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/rabbitmq"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/mailer-service/internal/delivery"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/mailer-service/internal/suppression"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/mailer-service/internal/transport"
+	"github.com/google/uuid"
 )
 
-type dialer interface {
-	DialAndSend(msg ...*gomail.Message) error
+type mailTransport interface {
+	Send(ctx context.Context, email transport.Email) error
+}
+
+type suppressionList interface {
+	IsSuppressed(ctx context.Context, email string) bool
+	Suppress(ctx context.Context, email string, reason suppression.Reason, detail string) error
+}
+
+type deliveryLog interface {
+	Record(ctx context.Context, attempt delivery.Attempt)
 }
 
 type rabbitMQConsumer interface {
-	Consume(queue string, handler func(body []byte))
+	Consume(queue string, handler func(ctx context.Context, body []byte) error)
 }
 
 type weatherEmailBuilder interface {
-	BuildWeatherUpdateEmail(sub SubscriptionDTO, weather WeatherDTO, time time.Time) string
-	BuildConfirmationEmail(sub SubscriptionDTO) string
-	BuildConfirmSuccessEmail(sub SubscriptionDTO) string
+	BuildWeatherUpdateEmail(sub SubscriptionDTO, weather WeatherDTO,
+		forecast *ForecastDTO, time time.Time) (EmailContent, error)
+	BuildConfirmationEmail(sub SubscriptionDTO) (EmailContent, error)
+	BuildConfirmSuccessEmail(sub SubscriptionDTO) (EmailContent, error)
+	BuildDigestEmail(language string, items []DigestItem, time time.Time) (EmailContent, error)
 }
 
 type MailService struct {
-	mailEmail string
-	dialer    dialer
-	builder   weatherEmailBuilder
-	logger    logger.Logger
+	mailEmail    string
+	transport    mailTransport
+	builder      weatherEmailBuilder
+	suppressions suppressionList
+	deliveries   deliveryLog
+	logger       logger.Logger
 }
 
-func NewMailerService(mailEmail string, dialer dialer,
-	builder weatherEmailBuilder, logger logger.Logger) *MailService {
+func NewMailerService(mailEmail string, transport mailTransport, builder weatherEmailBuilder,
+	suppressions suppressionList, deliveries deliveryLog, logger logger.Logger) *MailService {
 	return &MailService{
-		mailEmail: mailEmail,
-		dialer:    dialer,
-		builder:   builder,
-		logger:    logger,
+		mailEmail:    mailEmail,
+		transport:    transport,
+		builder:      builder,
+		suppressions: suppressions,
+		deliveries:   deliveries,
+		logger:       logger,
 	}
 }
 
 func (ms *MailService) StartEmailWorker(consumer rabbitMQConsumer) {
-	consumer.Consume(rabbitmq.SendEmail, func(body []byte) {
+	consumer.Consume(rabbitmq.SendEmail, func(ctx context.Context, body []byte) error {
+		log := ms.logger.WithContext(ctx)
+
 		var job EmailJob
 		if err := json.Unmarshal(body, &job); err != nil {
-			ms.logger.Error("Failed to unmarshal EmailJob", "error", err)
-			return
+			log.Error("Failed to unmarshal EmailJob", "error", err)
+			return nil
 		}
 
-		ms.logger.Info("Processing EmailJob", "jobType", job.EmailType, "jobEmail", job.To)
+		log.Info("Processing EmailJob", "jobType", job.EmailType, "jobEmail", job.To, "messageID", job.MessageID)
 
 		switch job.EmailType {
 		case EmailTypeCreateSubscription:
-			ms.SendConfirmationEmail(job.Subscription)
+			return retryable(ms.SendConfirmationEmail(ctx, job.MessageID, job.Subscription))
 		case EmailTypeConfirmSuccess:
-			ms.SendConfirmSuccessEmail(job.Subscription)
+			return retryable(ms.SendConfirmSuccessEmail(ctx, job.MessageID, job.Subscription))
 		default:
-			ms.logger.Error("Unknown email type", "emailType", job.EmailType)
+			log.Error("Unknown email type", "emailType", job.EmailType)
+			return nil
 		}
 	})
 
-	consumer.Consume(rabbitmq.WeatherUpdate, func(body []byte) {
+	consumer.Consume(rabbitmq.WeatherUpdate, func(ctx context.Context, body []byte) error {
+		log := ms.logger.WithContext(ctx)
+
 		var job WeatherUpdateJob
 		if err := json.Unmarshal(body, &job); err != nil {
-			ms.logger.Error("Failed to unmarshal WeatherUpdateJob", "error", err)
-			return
+			log.Error("Failed to unmarshal WeatherUpdateJob", "error", err)
+			return nil
+		}
+		log.Info("Processing WeatherUpdateJob", "jobEmail", job.To, "jobWeather", job.Weather,
+			"messageID", job.MessageID)
+
+		return retryable(ms.SendWeatherUpdateEmail(ctx, job.MessageID, job.Subscription, job.Weather, job.Forecast))
+	})
+
+	consumer.Consume(rabbitmq.WeatherDigest, func(ctx context.Context, body []byte) error {
+		log := ms.logger.WithContext(ctx)
+
+		var job WeatherDigestJob
+		if err := json.Unmarshal(body, &job); err != nil {
+			log.Error("Failed to unmarshal WeatherDigestJob", "error", err)
+			return nil
 		}
-		ms.logger.Info("Processing WeatherUpdateJob", "jobType", job.EmailType, "jobEmail", job.To, "jobWeather", job.Weather)
+		log.Info("Processing WeatherDigestJob", "jobEmail", job.To, "cities", len(job.Items),
+			"messageID", job.MessageID)
 
-		ms.SendWeatherUpdateEmail(job.Subscription, job.Weather)
+		return retryable(ms.SendDigestEmail(ctx, job.MessageID, job.To, job.Language, job.Items))
 	})
 }
 
-func (ms *MailService) SendConfirmationEmail(sub SubscriptionDTO) {
-	body := ms.builder.BuildConfirmationEmail(sub)
-	ms.send(sub.Email, "Weather updates confirmation link", body)
+// retryable drops the errors a redelivery can't fix, so only the rest make
+// the consumer requeue the job.
+func retryable(err error) error {
+	if errors.Is(err, transport.ErrPermanentFailure) || errors.Is(err, ErrRecipientSuppressed) {
+		return nil
+	}
+	return err
+}
+
+// The Send methods take the message id from the job; an empty id gets a
+// fresh one. They return the build or delivery error.
+func (ms *MailService) SendConfirmationEmail(ctx context.Context, messageID string, sub SubscriptionDTO) error {
+	content, err := ms.builder.BuildConfirmationEmail(sub)
+	if err != nil {
+		ms.logger.WithContext(ctx).Error("Failed to build confirmation email", "to", sub.Email, "error", err)
+		return err
+	}
+	return ms.send(ctx, messageID, sub, EmailTypeCreateSubscription, content)
+}
+
+func (ms *MailService) SendConfirmSuccessEmail(ctx context.Context, messageID string, sub SubscriptionDTO) error {
+	content, err := ms.builder.BuildConfirmSuccessEmail(sub)
+	if err != nil {
+		ms.logger.WithContext(ctx).Error("Failed to build confirm success email", "to", sub.Email, "error", err)
+		return err
+	}
+	return ms.send(ctx, messageID, sub, EmailTypeConfirmSuccess, content)
+}
+
+func (ms *MailService) SendWeatherUpdateEmail(ctx context.Context, messageID string, sub SubscriptionDTO,
+	weather WeatherDTO, forecast *ForecastDTO) error {
+	content, err := ms.builder.BuildWeatherUpdateEmail(sub, weather, forecast, time.Now())
+	if err != nil {
+		ms.logger.WithContext(ctx).Error("Failed to build weather update email", "to", sub.Email, "error", err)
+		return err
+	}
+	return ms.send(ctx, messageID, sub, EmailTypeWeatherUpdate, content)
+}
+
+func (ms *MailService) SendDigestEmail(ctx context.Context, messageID string, to string, language string,
+	items []DigestItem) error {
+	content, err := ms.builder.BuildDigestEmail(language, items, time.Now())
+	if err != nil {
+		ms.logger.WithContext(ctx).Error("Failed to build digest email", "to", to, "error", err)
+		return err
+	}
+
+	// a digest has no single subscription, the delivery log keeps it without a token
+	return ms.send(ctx, messageID, SubscriptionDTO{Email: to, Language: language}, EmailTypeWeatherDigest, content)
 }
 
-func (ms *MailService) SendConfirmSuccessEmail(sub SubscriptionDTO) {
-	body := ms.builder.BuildConfirmSuccessEmail(sub)
-	ms.send(sub.Email, "Weather updates subscription", body)
+// Preview renders emailType without sending it.
+func (ms *MailService) Preview(emailType EmailType, sub SubscriptionDTO, weather WeatherDTO) (EmailContent, error) {
+	switch emailType {
+	case EmailTypeCreateSubscription:
+		return ms.builder.BuildConfirmationEmail(sub)
+	case EmailTypeConfirmSuccess:
+		return ms.builder.BuildConfirmSuccessEmail(sub)
+	case EmailTypeWeatherUpdate:
+		return ms.builder.BuildWeatherUpdateEmail(sub, weather, sampleForecast(sub), time.Now())
+	case EmailTypeWeatherDigest:
+		return ms.builder.BuildDigestEmail(sub.Language, sampleDigest(sub, weather), time.Now())
+	default:
+		return EmailContent{}, ErrUnknownEmailType
+	}
 }
 
-func (ms *MailService) SendWeatherUpdateEmail(sub SubscriptionDTO, weather WeatherDTO) {
-	body := ms.builder.BuildWeatherUpdateEmail(sub, weather, time.Now())
-	ms.send(sub.Email, "Weather Update", body)
+// SendTestEmail renders emailType and sends it to sub.Email through the
+// regular transport, returning the message id of the delivery.
+func (ms *MailService) SendTestEmail(ctx context.Context, emailType EmailType, sub SubscriptionDTO,
+	weather WeatherDTO) (string, error) {
+	content, err := ms.Preview(emailType, sub, weather)
+	if err != nil {
+		return "", err
+	}
+
+	messageID := uuid.New().String()
+	ms.logger.WithContext(ctx).Info("Sending test email", "to", sub.Email, "emailType", emailType, "messageID", messageID)
+
+	err = ms.send(ctx, messageID, sub, emailType, content)
+	if err != nil && !errors.Is(err, ErrRecipientSuppressed) {
+		return messageID, fmt.Errorf("%w: %w", ErrSendFailed, err)
+	}
+	return messageID, err
 }
-func (ms *MailService) send(to, subject, body string) {
-	m := gomail.NewMessage()
-	m.SetHeader("From", ms.mailEmail)
-	m.SetHeader("To", to)
-	m.SetHeader("Subject", subject)
-	m.SetBody("text/html", body)
 
-	if err := ms.dialer.DialAndSend(m); err != nil {
-		ms.logger.Error("Failed to send email", "to", to, "error", err)
-		return
+func (ms *MailService) send(ctx context.Context, messageID string, sub SubscriptionDTO, emailType EmailType,
+	content EmailContent) error {
+	log := ms.logger.WithContext(ctx)
+	to := sub.Email
+
+	if messageID == "" {
+		messageID = uuid.New().String()
+	}
+
+	attempt := delivery.Attempt{
+		MessageID:         messageID,
+		SubscriptionToken: sub.Token,
+		EmailType:         string(emailType),
+		Recipient:         to,
+	}
+
+	if ms.suppressions.IsSuppressed(ctx, to) {
+		log.Info("Skipping suppressed recipient", "to", to, "subject", content.Subject)
+
+		attempt.Status = delivery.StatusSuppressed
+		ms.deliveries.Record(ctx, attempt)
+		return ErrRecipientSuppressed
 	}
-	ms.logger.Info("Email sent successfully", "to", to, "subject", subject)
 
+	email := transport.Email{
+		From:    ms.mailEmail,
+		To:      to,
+		Subject: content.Subject,
+		HTML:    content.HTML,
+		Text:    content.Text,
+		Headers: map[string]string{
+			"Message-ID": ms.messageIDHeader(messageID),
+		},
+	}
+
+	// RFC 8058 one-click unsubscribe: mail clients POST to the URL directly
+	if content.UnsubscribeURL != "" {
+		email.Headers["List-Unsubscribe"] = "<" + content.UnsubscribeURL + ">"
+		email.Headers["List-Unsubscribe-Post"] = "List-Unsubscribe=One-Click"
+	}
+
+	if err := ms.transport.Send(ctx, email); err != nil {
+		log.Error("Failed to send email", "to", to, "messageID", messageID, "error", err)
+
+		attempt.Status = delivery.StatusFailed
+		attempt.ProviderResponse = err.Error()
+		ms.deliveries.Record(ctx, attempt)
+
+		if errors.Is(err, transport.ErrPermanentFailure) {
+			if err := ms.suppressions.Suppress(ctx, to, suppression.ReasonBounce, err.Error()); err != nil {
+				log.Error("Failed to suppress bounced address", "to", to, "error", err)
+			}
+		}
+		return err
+	}
+	log.Info("Email sent successfully", "to", to, "subject", content.Subject, "messageID", messageID)
+
+	attempt.Status = delivery.StatusSent
+	ms.deliveries.Record(ctx, attempt)
+	return nil
+}
+
+// messageIDHeader formats id as an RFC 5322 Message-ID on the sender's domain.
+func (ms *MailService) messageIDHeader(id string) string {
+	domain := "localhost"
+	if at := strings.LastIndex(ms.mailEmail, "@"); at >= 0 {
+		domain = ms.mailEmail[at+1:]
+	}
+	return "<" + id + "@" + domain + ">"
 }
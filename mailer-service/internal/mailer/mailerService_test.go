@@ -4,12 +4,20 @@
 package mailer
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"testing"
 	"time"
 
-	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/mailer-service/logger"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/logger"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/rabbitmq"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/mailer-service/internal/delivery"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/mailer-service/internal/suppression"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/mailer-service/internal/transport"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
-	"gopkg.in/gomail.v2"
+	"github.com/stretchr/testify/require"
 )
 
 // --- Mocks ---
@@ -18,80 +26,345 @@ type mockEmailBuilder struct {
 	mock.Mock
 }
 
-func (m *mockEmailBuilder) BuildWeatherUpdateEmail(sub SubscriptionDTO, weather WeatherDTO, t time.Time) string {
-	args := m.Called(sub, weather, t)
-	return args.String(0)
+func (m *mockEmailBuilder) BuildWeatherUpdateEmail(sub SubscriptionDTO, weather WeatherDTO,
+	forecast *ForecastDTO, t time.Time) (EmailContent, error) {
+	args := m.Called(sub, weather, forecast, t)
+	return args.Get(0).(EmailContent), args.Error(1)
 }
-func (m *mockEmailBuilder) BuildConfirmationEmail(sub SubscriptionDTO) string {
+func (m *mockEmailBuilder) BuildConfirmationEmail(sub SubscriptionDTO) (EmailContent, error) {
 	args := m.Called(sub)
-	return args.String(0)
+	return args.Get(0).(EmailContent), args.Error(1)
 }
-func (m *mockEmailBuilder) BuildConfirmSuccessEmail(sub SubscriptionDTO) string {
+func (m *mockEmailBuilder) BuildConfirmSuccessEmail(sub SubscriptionDTO) (EmailContent, error) {
 	args := m.Called(sub)
-	return args.String(0)
+	return args.Get(0).(EmailContent), args.Error(1)
 }
 
-type mockDialer struct {
+func (m *mockEmailBuilder) BuildDigestEmail(language string, items []DigestItem,
+	t time.Time) (EmailContent, error) {
+	args := m.Called(language, items, t)
+	return args.Get(0).(EmailContent), args.Error(1)
+}
+
+type mockTransport struct {
 	mock.Mock
 }
 
-func (m *mockDialer) DialAndSend(msg ...*gomail.Message) error {
-	args := m.Called(msg)
+func (m *mockTransport) Send(_ context.Context, email transport.Email) error {
+	args := m.Called(email)
 	return args.Error(0)
 }
 
+type mockSuppressionList struct {
+	mock.Mock
+}
+
+func (m *mockSuppressionList) IsSuppressed(_ context.Context, email string) bool {
+	args := m.Called(email)
+	return args.Bool(0)
+}
+
+func (m *mockSuppressionList) Suppress(_ context.Context, email string, reason suppression.Reason, detail string) error {
+	args := m.Called(email, reason, detail)
+	return args.Error(0)
+}
+
+type mockDeliveryLog struct {
+	mock.Mock
+}
+
+func (m *mockDeliveryLog) Record(_ context.Context, attempt delivery.Attempt) {
+	m.Called(attempt)
+}
+
 // --- Tests ---
 
-func setupMailerTest(t *testing.T) (*mockEmailBuilder, *mockDialer, *MailService) {
+func setupMailerTest(t *testing.T) (*mockEmailBuilder, *mockTransport, *MailService) {
+	builder, sender, _, ms := setupMailerTestWithSuppressions(t)
+	return builder, sender, ms
+}
+
+func setupMailerTestWithSuppressions(t *testing.T) (*mockEmailBuilder, *mockTransport,
+	*mockSuppressionList, *MailService) {
 	builder := new(mockEmailBuilder)
-	dialer := new(mockDialer)
+	sender := new(mockTransport)
+	suppressions := new(mockSuppressionList)
+	suppressions.On("IsSuppressed", mock.Anything).Return(false).Maybe()
+	deliveries := new(mockDeliveryLog)
+	deliveries.On("Record", mock.Anything).Maybe()
 	mockLog, _ := logger.NewTestLogger()
-	ms := NewMailerService("test@example.com", dialer, builder, *mockLog)
-	return builder, dialer, ms
+	ms := NewMailerService("test@example.com", sender, builder, suppressions, deliveries, *mockLog)
+	return builder, sender, suppressions, ms
 }
 
 func TestSendConfirmationEmail(t *testing.T) {
-	builder, dialer, ms := setupMailerTest(t)
+	builder, sender, ms := setupMailerTest(t)
 
 	sub := SubscriptionDTO{Email: "user@example.com"}
-	expectedBody := "confirmation"
+	expectedBody := EmailContent{Subject: "Confirm", HTML: "confirmation", Text: "confirmation"}
 
-	builder.On("BuildConfirmationEmail", sub).Return(expectedBody)
-	dialer.On("DialAndSend", mock.Anything).Return(nil)
+	builder.On("BuildConfirmationEmail", sub).Return(expectedBody, nil)
+	sender.On("Send", mock.Anything).Return(nil)
 
-	ms.SendConfirmationEmail(sub)
+	require.NoError(t, ms.SendConfirmationEmail(context.Background(), "msg-1", sub))
 
 	builder.AssertCalled(t, "BuildConfirmationEmail", sub)
-	dialer.AssertCalled(t, "DialAndSend", mock.Anything)
+	sender.AssertCalled(t, "Send", transport.Email{
+		From:    "test@example.com",
+		To:      "user@example.com",
+		Subject: "Confirm",
+		HTML:    "confirmation",
+		Text:    "confirmation",
+		Headers: map[string]string{"Message-ID": "<msg-1@example.com>"},
+	})
 }
 
 func TestSendConfirmSuccessEmail(t *testing.T) {
-	builder, dialer, ms := setupMailerTest(t)
+	builder, sender, ms := setupMailerTest(t)
 
 	sub := SubscriptionDTO{Email: "user@example.com"}
-	expectedBody := "success"
+	expectedBody := EmailContent{
+		Subject:        "Success",
+		HTML:           "success",
+		Text:           "success",
+		UnsubscribeURL: "https://weather.example.com/api/unsubscribe/token123",
+	}
 
-	builder.On("BuildConfirmSuccessEmail", sub).Return(expectedBody)
-	dialer.On("DialAndSend", mock.Anything).Return(nil)
+	builder.On("BuildConfirmSuccessEmail", sub).Return(expectedBody, nil)
+	sender.On("Send", mock.Anything).Return(nil)
 
-	ms.SendConfirmSuccessEmail(sub)
+	require.NoError(t, ms.SendConfirmSuccessEmail(context.Background(), "", sub))
 
 	builder.AssertCalled(t, "BuildConfirmSuccessEmail", sub)
-	dialer.AssertCalled(t, "DialAndSend", mock.Anything)
+	sender.AssertCalled(t, "Send", mock.MatchedBy(func(email transport.Email) bool {
+		return email.Headers["List-Unsubscribe"] == "<https://weather.example.com/api/unsubscribe/token123>" &&
+			email.Headers["List-Unsubscribe-Post"] == "List-Unsubscribe=One-Click"
+	}))
 }
 
 func TestSendWeatherUpdateEmail(t *testing.T) {
-	builder, dialer, ms := setupMailerTest(t)
+	builder, sender, ms := setupMailerTest(t)
 
 	sub := SubscriptionDTO{Email: "user@example.com"}
 	weather := WeatherDTO{Temperature: 20}
-	expectedBody := "weather update"
+	forecast := &ForecastDTO{MaxTemperature: 24, MinTemperature: 13}
+	expectedBody := EmailContent{Subject: "Weather", HTML: "weather update", Text: "weather update"}
+
+	builder.On("BuildWeatherUpdateEmail", sub, weather, forecast, mock.AnythingOfType("time.Time")).Return(expectedBody, nil)
+	sender.On("Send", mock.Anything).Return(nil)
+
+	require.NoError(t, ms.SendWeatherUpdateEmail(context.Background(), "", sub, weather, forecast))
+
+	builder.AssertCalled(t, "BuildWeatherUpdateEmail", sub, weather, forecast, mock.AnythingOfType("time.Time"))
+	sender.AssertCalled(t, "Send", mock.Anything)
+}
+
+func TestSendConfirmationEmail_BuildError(t *testing.T) {
+	builder, sender, ms := setupMailerTest(t)
+
+	sub := SubscriptionDTO{Email: "user@example.com"}
+
+	builder.On("BuildConfirmationEmail", sub).Return(EmailContent{}, errors.New("template error"))
+
+	assert.Error(t, ms.SendConfirmationEmail(context.Background(), "", sub))
+
+	sender.AssertNotCalled(t, "Send", mock.Anything)
+}
+
+func TestSend_SkipsSuppressedRecipient(t *testing.T) {
+	builder := new(mockEmailBuilder)
+	sender := new(mockTransport)
+	suppressions := new(mockSuppressionList)
+	deliveries := new(mockDeliveryLog)
+	mockLog, _ := logger.NewTestLogger()
+	ms := NewMailerService("test@example.com", sender, builder, suppressions, deliveries, *mockLog)
+
+	sub := SubscriptionDTO{Email: "bounced@example.com"}
+
+	builder.On("BuildConfirmationEmail", sub).Return(EmailContent{Subject: "Confirm"}, nil)
+	suppressions.On("IsSuppressed", "bounced@example.com").Return(true)
+	deliveries.On("Record", mock.Anything)
+
+	assert.ErrorIs(t, ms.SendConfirmationEmail(context.Background(), "msg-1", sub), ErrRecipientSuppressed)
+
+	sender.AssertNotCalled(t, "Send", mock.Anything)
+	deliveries.AssertCalled(t, "Record", delivery.Attempt{
+		MessageID: "msg-1",
+		EmailType: string(EmailTypeCreateSubscription),
+		Recipient: "bounced@example.com",
+		Status:    delivery.StatusSuppressed,
+	})
+}
+
+func TestSend_PermanentFailureSuppressesRecipient(t *testing.T) {
+	builder, sender, suppressions, ms := setupMailerTestWithSuppressions(t)
+
+	sub := SubscriptionDTO{Email: "unknown@example.com"}
+	sendErr := fmt.Errorf("%w: 550 user unknown", transport.ErrPermanentFailure)
+
+	builder.On("BuildConfirmationEmail", sub).Return(EmailContent{Subject: "Confirm"}, nil)
+	sender.On("Send", mock.Anything).Return(sendErr)
+	suppressions.On("Suppress", "unknown@example.com", suppression.ReasonBounce, sendErr.Error()).Return(nil)
+
+	assert.ErrorIs(t, ms.SendConfirmationEmail(context.Background(), "", sub), transport.ErrPermanentFailure)
+
+	suppressions.AssertCalled(t, "Suppress", "unknown@example.com", suppression.ReasonBounce, sendErr.Error())
+}
+
+func TestSend_TransientFailureDoesNotSuppress(t *testing.T) {
+	builder, sender, suppressions, ms := setupMailerTestWithSuppressions(t)
+
+	sub := SubscriptionDTO{Email: "user@example.com"}
+
+	builder.On("BuildConfirmationEmail", sub).Return(EmailContent{Subject: "Confirm"}, nil)
+	sender.On("Send", mock.Anything).Return(errors.New("connection reset"))
+
+	assert.EqualError(t, ms.SendConfirmationEmail(context.Background(), "", sub), "connection reset")
+
+	suppressions.AssertNotCalled(t, "Suppress", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSend_RecordsDelivery(t *testing.T) {
+	builder := new(mockEmailBuilder)
+	sender := new(mockTransport)
+	suppressions := new(mockSuppressionList)
+	deliveries := new(mockDeliveryLog)
+	mockLog, _ := logger.NewTestLogger()
+	ms := NewMailerService("test@example.com", sender, builder, suppressions, deliveries, *mockLog)
+
+	sub := SubscriptionDTO{Email: "user@example.com", Token: "token123"}
+
+	builder.On("BuildConfirmSuccessEmail", sub).Return(EmailContent{Subject: "Success"}, nil)
+	suppressions.On("IsSuppressed", "user@example.com").Return(false)
+	sender.On("Send", mock.Anything).Return(errors.New("connection reset")).Once()
+	sender.On("Send", mock.Anything).Return(nil).Once()
+	deliveries.On("Record", mock.Anything)
+
+	ms.SendConfirmSuccessEmail(context.Background(), "msg-1", sub)
+	ms.SendConfirmSuccessEmail(context.Background(), "msg-1", sub)
+
+	expected := delivery.Attempt{
+		MessageID:         "msg-1",
+		SubscriptionToken: "token123",
+		EmailType:         string(EmailTypeConfirmSuccess),
+		Recipient:         "user@example.com",
+	}
+
+	failed := expected
+	failed.Status = delivery.StatusFailed
+	failed.ProviderResponse = "connection reset"
+
+	sent := expected
+	sent.Status = delivery.StatusSent
+
+	deliveries.AssertCalled(t, "Record", failed)
+	deliveries.AssertCalled(t, "Record", sent)
+}
+
+func TestPreview_RendersEveryType(t *testing.T) {
+	builder, _, ms := setupMailerTest(t)
+
+	req := previewRequest{City: "Lviv", Language: "uk"}
+	sub := req.subscription()
+	weather := req.weather()
+
+	builder.On("BuildConfirmationEmail", sub).Return(EmailContent{Subject: "confirm"}, nil)
+	builder.On("BuildConfirmSuccessEmail", sub).Return(EmailContent{Subject: "success"}, nil)
+	builder.On("BuildWeatherUpdateEmail", sub, weather, sampleForecast(sub), mock.AnythingOfType("time.Time")).
+		Return(EmailContent{Subject: "weather"}, nil)
+
+	expected := map[EmailType]string{
+		EmailTypeCreateSubscription: "confirm",
+		EmailTypeConfirmSuccess:     "success",
+		EmailTypeWeatherUpdate:      "weather",
+	}
+
+	for emailType, subject := range expected {
+		content, err := ms.Preview(emailType, sub, weather)
+		require.NoError(t, err)
+		assert.Equal(t, subject, content.Subject)
+	}
+
+	assert.Equal(t, "Lviv", sub.City)
+	assert.Equal(t, "uk", sub.Language)
+	assert.Equal(t, 21.5, weather.Temperature)
+}
+
+func TestPreview_UnknownType(t *testing.T) {
+	_, _, ms := setupMailerTest(t)
+
+	_, err := ms.Preview(EmailType("Digest"), SubscriptionDTO{}, WeatherDTO{})
+	assert.ErrorIs(t, err, ErrUnknownEmailType)
+}
+
+func TestSendTestEmail(t *testing.T) {
+	builder, sender, ms := setupMailerTest(t)
+
+	sub := previewRequest{To: "qa@example.com"}.subscription()
+
+	builder.On("BuildConfirmSuccessEmail", sub).Return(EmailContent{Subject: "success"}, nil)
+	sender.On("Send", mock.MatchedBy(func(email transport.Email) bool {
+		return email.To == "qa@example.com"
+	})).Return(nil).Once()
+	sender.On("Send", mock.Anything).Return(errors.New("connection refused")).Once()
+
+	messageID, err := ms.SendTestEmail(context.Background(), EmailTypeConfirmSuccess, sub, WeatherDTO{})
+	require.NoError(t, err)
+	assert.NotEmpty(t, messageID)
+
+	_, err = ms.SendTestEmail(context.Background(), EmailTypeConfirmSuccess, sub, WeatherDTO{})
+	assert.ErrorIs(t, err, ErrSendFailed)
+}
+
+// capturingConsumer keeps the handlers registered per queue.
+type capturingConsumer struct {
+	handlers map[string]func(ctx context.Context, body []byte) error
+}
+
+func (c *capturingConsumer) Consume(queue string, handler func(ctx context.Context, body []byte) error) {
+	if c.handlers == nil {
+		c.handlers = map[string]func(ctx context.Context, body []byte) error{}
+	}
+	c.handlers[queue] = handler
+}
+
+func TestEmailWorker_RequeuesOnlyTransientFailures(t *testing.T) {
+	builder, sender, suppressions, ms := setupMailerTestWithSuppressions(t)
+
+	builder.On("BuildWeatherUpdateEmail", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(EmailContent{Subject: "Weather"}, nil)
+	permanent := fmt.Errorf("%w: 550 user unknown", transport.ErrPermanentFailure)
+	sender.On("Send", mock.Anything).Return(errors.New("connection reset")).Once()
+	sender.On("Send", mock.Anything).Return(permanent).Once()
+	sender.On("Send", mock.Anything).Return(nil).Once()
+	suppressions.On("Suppress", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	consumer := &capturingConsumer{}
+	ms.StartEmailWorker(consumer)
+	handle := consumer.handlers[rabbitmq.WeatherUpdate]
+
+	body := []byte(`{"To":"user@example.com","Subscription":{"Email":"user@example.com"}}`)
+	assert.EqualError(t, handle(context.Background(), body), "connection reset")
+	assert.NoError(t, handle(context.Background(), body), "a permanent failure isn't retried")
+	assert.NoError(t, handle(context.Background(), body))
+}
+
+func TestSendDigestEmail(t *testing.T) {
+	builder, sender, ms := setupMailerTest(t)
+
+	items := []DigestItem{
+		{Subscription: SubscriptionDTO{Email: "user@example.com", City: "Kyiv", Token: "a"}},
+		{Subscription: SubscriptionDTO{Email: "user@example.com", City: "Lviv", Token: "b"}},
+	}
 
-	builder.On("BuildWeatherUpdateEmail", sub, weather, mock.AnythingOfType("time.Time")).Return(expectedBody)
-	dialer.On("DialAndSend", mock.Anything).Return(nil)
+	builder.On("BuildDigestEmail", "uk", items, mock.AnythingOfType("time.Time")).
+		Return(EmailContent{Subject: "Digest", HTML: "digest", Text: "digest"}, nil)
+	sender.On("Send", mock.Anything).Return(nil)
 
-	ms.SendWeatherUpdateEmail(sub, weather)
+	require.NoError(t, ms.SendDigestEmail(context.Background(), "msg-1", "user@example.com", "uk", items))
 
-	builder.AssertCalled(t, "BuildWeatherUpdateEmail", sub, weather, mock.AnythingOfType("time.Time"))
-	dialer.AssertCalled(t, "DialAndSend", mock.Anything)
+	sender.AssertCalled(t, "Send", mock.MatchedBy(func(email transport.Email) bool {
+		_, oneClick := email.Headers["List-Unsubscribe"]
+		return email.To == "user@example.com" && email.Subject == "Digest" && !oneClick
+	}))
 }
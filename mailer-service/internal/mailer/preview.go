@@ -0,0 +1,101 @@
+package mailer
+
+// previewRequest overrides the sample data used to render an email. Preview
+// reads it from the query string, test sends from the JSON body.
+type previewRequest struct {
+	To          string   `form:"to" json:"to"`
+	City        string   `form:"city" json:"city"`
+	Frequency   string   `form:"frequency" json:"frequency"`
+	Language    string   `form:"language" json:"language"`
+	Temperature *float64 `form:"temperature" json:"temperature"`
+	Humidity    *float64 `form:"humidity" json:"humidity"`
+	Description string   `form:"description" json:"description"`
+}
+
+const previewToken = "preview-token"
+
+func (r previewRequest) subscription() SubscriptionDTO {
+	sub := SubscriptionDTO{
+		Email:     "subscriber@example.com",
+		City:      "Kyiv",
+		Frequency: FrequencyDaily,
+		Token:     previewToken,
+		Confirmed: true,
+		Language:  "en",
+	}
+
+	if r.To != "" {
+		sub.Email = r.To
+	}
+	if r.City != "" {
+		sub.City = r.City
+	}
+	if r.Frequency == string(FrequencyHourly) {
+		sub.Frequency = FrequencyHourly
+	}
+	if r.Language != "" {
+		sub.Language = r.Language
+	}
+
+	return sub
+}
+
+func (r previewRequest) weather() WeatherDTO {
+	weather := WeatherDTO{
+		Temperature: 21.5,
+		Humidity:    55,
+		Description: "Partly cloudy",
+	}
+
+	if r.Temperature != nil {
+		weather.Temperature = *r.Temperature
+	}
+	if r.Humidity != nil {
+		weather.Humidity = *r.Humidity
+	}
+	if r.Description != "" {
+		weather.Description = r.Description
+	}
+
+	return weather
+}
+
+// sampleForecast returns a forecast for daily subscriptions only, like the
+// weather-api does.
+func sampleForecast(sub SubscriptionDTO) *ForecastDTO {
+	if sub.Frequency != FrequencyDaily {
+		return nil
+	}
+
+	return &ForecastDTO{
+		MaxTemperature:      24,
+		MinTemperature:      13,
+		PrecipitationChance: 40,
+		Sunrise:             "04:47",
+		Sunset:              "21:02",
+		Hourly: []HourlyForecastDTO{
+			{Time: "09:00", Temperature: 16, PrecipitationChance: 0, Description: "Sunny"},
+			{Time: "12:00", Temperature: 21, PrecipitationChance: 10, Description: "Partly cloudy"},
+			{Time: "15:00", Temperature: 24, PrecipitationChance: 40, Description: "Patchy rain"},
+			{Time: "18:00", Temperature: 20, PrecipitationChance: 20, Description: "Cloudy"},
+			{Time: "21:00", Temperature: 15, PrecipitationChance: 0, Description: "Clear"},
+		},
+	}
+}
+
+// sampleDigest adds a second city to sub, so the preview shows the list.
+func sampleDigest(sub SubscriptionDTO, weather WeatherDTO) []DigestItem {
+	second := sub
+	second.City = "Lviv"
+	second.Token = previewToken + "-2"
+
+	if sub.City == second.City {
+		second.City = "Kyiv"
+	}
+
+	return []DigestItem{
+		{Subscription: sub, Weather: weather, Forecast: sampleForecast(sub)},
+		{Subscription: second, Weather: WeatherDTO{Temperature: 17, Humidity: 70, Description: "Light rain"},
+			Forecast: sampleForecast(second)},
+	}
+}
@@ -0,0 +1,88 @@
+package mailer
+
+import (
+	"context"
+	"net/http"
+	"net/mail"
+
+	"github.com/gin-gonic/gin"
+)
+
+type previewService interface {
+	Preview(emailType EmailType, sub SubscriptionDTO, weather WeatherDTO) (EmailContent, error)
+	SendTestEmail(ctx context.Context, emailType EmailType, sub SubscriptionDTO,
+		weather WeatherDTO) (string, error)
+}
+
+type PreviewController struct {
+	service previewService
+}
+
+func NewPreviewController(service previewService) *PreviewController {
+	return &PreviewController{service: service}
+}
+
+// Preview renders an email with sample data. The format query parameter
+// picks the html (default) or text part, or json for both and the subject.
+func (pc *PreviewController) Preview(c *gin.Context) {
+	emailType, err := ParseEmailType(c.Param("type"))
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	var req previewRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.String(http.StatusBadRequest, "invalid input")
+		return
+	}
+
+	content, err := pc.service.Preview(emailType, req.subscription(), req.weather())
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	switch c.DefaultQuery("format", "html") {
+	case "html":
+		c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(content.HTML))
+	case "text":
+		c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(content.Text))
+	case "json":
+		c.JSON(http.StatusOK, gin.H{
+			"subject": content.Subject,
+			"html":    content.HTML,
+			"text":    content.Text,
+		})
+	default:
+		c.String(http.StatusBadRequest, "format must be one of html, text, json")
+	}
+}
+
+// SendTest sends an email rendered with sample data to the "to" address.
+func (pc *PreviewController) SendTest(c *gin.Context) {
+	emailType, err := ParseEmailType(c.Param("type"))
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	var req previewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.String(http.StatusBadRequest, "invalid input")
+		return
+	}
+
+	if _, err := mail.ParseAddress(req.To); err != nil {
+		HandleError(c, ErrInvalidRecipient)
+		return
+	}
+
+	messageID, err := pc.service.SendTestEmail(c.Request.Context(), emailType, req.subscription(), req.weather())
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message_id": messageID})
+}
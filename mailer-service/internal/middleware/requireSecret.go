@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireSecret rejects requests whose header does not carry secret.
+func RequireSecret(header string, secret string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		provided := c.GetHeader(header)
+
+		if subtle.ConstantTimeCompare([]byte(provided), []byte(secret)) != 1 {
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -1,5 +0,0 @@
-package rabbitmq
-
-const SendEmail = "send_email"
-
-const WeatherUpdate = "weather_update"
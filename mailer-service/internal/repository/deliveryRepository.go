@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/mailer-service/internal/delivery"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type DeliveryRepository struct {
+	db *gorm.DB
+}
+
+func NewDeliveryRepository(database *gorm.DB) *DeliveryRepository {
+	return &DeliveryRepository{db: database}
+}
+
+// RecordAttempt inserts d as the first attempt of its message id, or counts
+// another attempt on the existing row, in a single statement so concurrent
+// redeliveries can't race on the unique message id.
+func (r *DeliveryRepository) RecordAttempt(ctx context.Context, d delivery.Delivery) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "message_id"}},
+		DoUpdates: clause.Assignments(map[string]any{
+			"status":            d.Status,
+			"attempts":          gorm.Expr("deliveries.attempts + 1"),
+			"provider_response": d.ProviderResponse,
+			"sent_at":           gorm.Expr("COALESCE(EXCLUDED.sent_at, deliveries.sent_at)"),
+			"updated_at":        time.Now(),
+		}),
+	}).Create(&d).Error
+}
+
+func (r *DeliveryRepository) FindByMessageID(ctx context.Context, messageID string) (*delivery.Delivery, error) {
+	var d delivery.Delivery
+	err := r.db.WithContext(ctx).Where("message_id = ?", messageID).First(&d).Error
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+func (r *DeliveryRepository) FindByRecipient(ctx context.Context, recipient string,
+	limit int) ([]delivery.Delivery, error) {
+	var entries []delivery.Delivery
+	err := r.db.WithContext(ctx).Where("recipient = ?", recipient).
+		Order("created_at desc").Limit(limit).Find(&entries).Error
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
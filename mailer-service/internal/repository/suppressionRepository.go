@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/mailer-service/internal/suppression"
+	"gorm.io/gorm"
+)
+
+type SuppressionRepository struct {
+	db *gorm.DB
+}
+
+func NewSuppressionRepository(database *gorm.DB) *SuppressionRepository {
+	return &SuppressionRepository{db: database}
+}
+
+func (r *SuppressionRepository) Create(ctx context.Context, s suppression.Suppression) error {
+	return r.db.WithContext(ctx).Create(&s).Error
+}
+
+func (r *SuppressionRepository) FindByEmail(ctx context.Context, email string) (*suppression.Suppression, error) {
+	var s suppression.Suppression
+	err := r.db.WithContext(ctx).Where("email = ?", email).First(&s).Error
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *SuppressionRepository) FindAll(ctx context.Context) ([]suppression.Suppression, error) {
+	var entries []suppression.Suppression
+	err := r.db.WithContext(ctx).Order("created_at desc").Find(&entries).Error
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (r *SuppressionRepository) Delete(ctx context.Context, email string) error {
+	return r.db.WithContext(ctx).Where("email = ?", email).Delete(&suppression.Suppression{}).Error
+}
@@ -0,0 +1,44 @@
+package routes
+
+import (
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/health"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/mailer-service/internal/delivery"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/mailer-service/internal/mailer"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/mailer-service/internal/middleware"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/mailer-service/internal/suppression"
+	"github.com/gin-gonic/gin"
+)
+
+func WebhookRoute(router *gin.RouterGroup, secret string, suppressionController *suppression.SuppressionController) {
+
+	router.POST("/bounces", middleware.RequireSecret("X-Webhook-Secret", secret), suppressionController.HandleWebhook)
+
+}
+
+func SuppressionRoute(router *gin.RouterGroup, suppressionController *suppression.SuppressionController) {
+
+	router.GET("/suppressions", suppressionController.List)
+	router.DELETE("/suppressions/:email", suppressionController.Remove)
+
+}
+
+func DeliveryRoute(router *gin.RouterGroup, deliveryController *delivery.DeliveryController) {
+
+	router.GET("/deliveries", deliveryController.ListByRecipient)
+	router.GET("/deliveries/:messageId", deliveryController.GetByMessageID)
+
+}
+
+func PreviewRoute(router *gin.RouterGroup, previewController *mailer.PreviewController) {
+
+	router.GET("/emails/:type/preview", previewController.Preview)
+	router.POST("/emails/:type/test", previewController.SendTest)
+
+}
+
+func HealthRoute(router *gin.Engine, healthController *health.HealthController) {
+
+	router.GET("/healthz", healthController.Liveness)
+	router.GET("/readyz", healthController.Readiness)
+
+}
@@ -0,0 +1,37 @@
+package suppression
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidEmail         = errors.New("invalid email")
+	ErrInvalidPayload       = errors.New("invalid payload")
+	ErrSuppressionNotFound  = errors.New("suppression not found")
+	ErrFailedToSave         = errors.New("failed to save suppression")
+	ErrFailedToLoad         = errors.New("failed to load suppressions")
+	ErrFailedToPublishEvent = errors.New("failed to publish suppression event")
+)
+
+// WebhookEvent is the provider-neutral bounce/complaint notification
+// accepted by the webhook endpoint.
+type WebhookEvent struct {
+	Type        string `json:"type"`
+	Email       string `json:"email"`
+	Permanent   bool   `json:"permanent"`
+	Description string `json:"description"`
+}
+
+const (
+	WebhookEventBounce    = "bounce"
+	WebhookEventComplaint = "complaint"
+)
+
+// SuppressionEvent is published to weather-api so the subscription of a
+// suppressed address gets deactivated.
+type SuppressionEvent struct {
+	Email        string
+	Reason       Reason
+	SuppressedAt time.Time
+}
@@ -0,0 +1,22 @@
+package suppression
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func HandleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrInvalidEmail),
+		errors.Is(err, ErrInvalidPayload):
+		c.String(http.StatusBadRequest, err.Error())
+
+	case errors.Is(err, ErrSuppressionNotFound):
+		c.String(http.StatusNotFound, err.Error())
+
+	default:
+		c.String(http.StatusInternalServerError, err.Error())
+	}
+}
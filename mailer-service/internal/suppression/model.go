@@ -0,0 +1,25 @@
+package suppression
+
+import (
+	"strings"
+	"time"
+)
+
+type Reason string
+
+const (
+	ReasonBounce    Reason = "bounce"
+	ReasonComplaint Reason = "complaint"
+)
+
+// Suppression is an address the mailer must not send to any more.
+type Suppression struct {
+	Email     string    `gorm:"primaryKey" json:"email"`
+	Reason    Reason    `gorm:"type:varchar(16);not null" json:"reason"`
+	Detail    string    `json:"detail,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
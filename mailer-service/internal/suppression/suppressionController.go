@@ -0,0 +1,66 @@
+package suppression
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type suppressionService interface {
+	ProcessWebhookEvents(ctx context.Context, events []WebhookEvent) (int, error)
+	List(ctx context.Context) ([]Suppression, error)
+	Remove(ctx context.Context, email string) error
+}
+
+type SuppressionController struct {
+	service suppressionService
+}
+
+func NewSuppressionController(service suppressionService) *SuppressionController {
+	return &SuppressionController{service: service}
+}
+
+// HandleWebhook accepts a JSON array of bounce and complaint notifications.
+func (sc *SuppressionController) HandleWebhook(c *gin.Context) {
+	var events []WebhookEvent
+
+	if err := c.ShouldBindJSON(&events); err != nil {
+		c.String(http.StatusBadRequest, ErrInvalidPayload.Error())
+		return
+	}
+
+	suppressed, err := sc.service.ProcessWebhookEvents(c.Request.Context(), events)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"received": len(events), "suppressed": suppressed})
+}
+
+func (sc *SuppressionController) List(c *gin.Context) {
+	entries, err := sc.service.List(c.Request.Context())
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
+
+func (sc *SuppressionController) Remove(c *gin.Context) {
+	email := c.Param("email")
+
+	if email == "" {
+		c.String(http.StatusBadRequest, ErrInvalidEmail.Error())
+		return
+	}
+
+	if err := sc.service.Remove(c.Request.Context(), email); err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.String(http.StatusOK, "Suppression removed.")
+}
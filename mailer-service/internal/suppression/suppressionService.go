@@ -0,0 +1,159 @@
+package suppression
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/logger"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/rabbitmq"
+	"gorm.io/gorm"
+)
+
+type suppressionRepository interface {
+	Create(ctx context.Context, s Suppression) error
+	FindByEmail(ctx context.Context, email string) (*Suppression, error)
+	FindAll(ctx context.Context) ([]Suppression, error)
+	Delete(ctx context.Context, email string) error
+}
+
+type eventPublisher interface {
+	Publish(ctx context.Context, queue string, payload any) error
+}
+
+type SuppressionService struct {
+	repository suppressionRepository
+	publisher  eventPublisher
+	logger     logger.Logger
+}
+
+func NewSuppressionService(repository suppressionRepository,
+	publisher eventPublisher, logger logger.Logger) *SuppressionService {
+	return &SuppressionService{
+		repository: repository,
+		publisher:  publisher,
+		logger:     logger,
+	}
+}
+
+// IsSuppressed reports whether email is on the suppression list. Lookup
+// errors are logged and treated as not suppressed, so a database outage
+// does not stop confirmation emails.
+func (s *SuppressionService) IsSuppressed(ctx context.Context, email string) bool {
+	_, err := s.repository.FindByEmail(ctx, NormalizeEmail(email))
+	if err == nil {
+		return true
+	}
+
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		s.logger.WithContext(ctx).Error("Failed to check suppression list", "email", email, "error", err)
+	}
+	return false
+}
+
+// Suppress adds email to the suppression list and tells weather-api to
+// deactivate its subscription. Suppressing an address again keeps the
+// first entry but publishes the event again, so a publish that failed is
+// retried when the provider redelivers the webhook.
+func (s *SuppressionService) Suppress(ctx context.Context, email string, reason Reason, detail string) error {
+	email = NormalizeEmail(email)
+	if email == "" {
+		return ErrInvalidEmail
+	}
+
+	entry, err := s.repository.FindByEmail(ctx, email)
+	switch {
+	case err == nil:
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		entry = &Suppression{
+			Email:     email,
+			Reason:    reason,
+			Detail:    detail,
+			CreatedAt: time.Now(),
+		}
+
+		if err := s.repository.Create(ctx, *entry); err != nil {
+			s.logger.WithContext(ctx).Error("Failed to save suppression", "email", email, "error", err)
+			return ErrFailedToSave
+		}
+
+		s.logger.WithContext(ctx).Info("Address suppressed", "email", email, "reason", reason)
+	default:
+		s.logger.WithContext(ctx).Error("Failed to check suppression list", "email", email, "error", err)
+		return ErrFailedToLoad
+	}
+
+	event := SuppressionEvent{
+		Email:        entry.Email,
+		Reason:       entry.Reason,
+		SuppressedAt: entry.CreatedAt,
+	}
+
+	if err := s.publisher.Publish(ctx, rabbitmq.SubscriptionSuppressed, event); err != nil {
+		s.logger.WithContext(ctx).Error("Failed to publish suppression event", "email", email, "error", err)
+		return ErrFailedToPublishEvent
+	}
+
+	return nil
+}
+
+// ProcessWebhookEvents suppresses the addresses of complaints and permanent
+// bounces. Transient bounces are ignored, the provider retries them itself.
+func (s *SuppressionService) ProcessWebhookEvents(ctx context.Context, events []WebhookEvent) (int, error) {
+	suppressed := 0
+
+	for _, event := range events {
+		var reason Reason
+
+		switch event.Type {
+		case WebhookEventComplaint:
+			reason = ReasonComplaint
+		case WebhookEventBounce:
+			if !event.Permanent {
+				s.logger.WithContext(ctx).Info("Ignoring transient bounce", "email", event.Email)
+				continue
+			}
+			reason = ReasonBounce
+		default:
+			s.logger.WithContext(ctx).Info("Ignoring unknown webhook event", "type", event.Type)
+			continue
+		}
+
+		if err := s.Suppress(ctx, event.Email, reason, event.Description); err != nil {
+			return suppressed, err
+		}
+		suppressed++
+	}
+
+	return suppressed, nil
+}
+
+func (s *SuppressionService) List(ctx context.Context) ([]Suppression, error) {
+	entries, err := s.repository.FindAll(ctx)
+	if err != nil {
+		s.logger.WithContext(ctx).Error("Failed to load suppressions", "error", err)
+		return nil, ErrFailedToLoad
+	}
+	return entries, nil
+}
+
+// Remove clears email from the suppression list. The subscription stays
+// deactivated in weather-api until the user confirms it again.
+func (s *SuppressionService) Remove(ctx context.Context, email string) error {
+	email = NormalizeEmail(email)
+
+	if _, err := s.repository.FindByEmail(ctx, email); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrSuppressionNotFound
+		}
+		return ErrFailedToLoad
+	}
+
+	if err := s.repository.Delete(ctx, email); err != nil {
+		s.logger.WithContext(ctx).Error("Failed to delete suppression", "email", email, "error", err)
+		return ErrFailedToSave
+	}
+
+	s.logger.WithContext(ctx).Info("Suppression removed", "email", email)
+	return nil
+}
@@ -0,0 +1,179 @@
+//go:build unit
+// +build unit
+
+package suppression
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/logger"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/rabbitmq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// --- Mocks ---
+
+type mockRepository struct {
+	mock.Mock
+}
+
+func (m *mockRepository) Create(_ context.Context, s Suppression) error {
+	args := m.Called(s)
+	return args.Error(0)
+}
+
+func (m *mockRepository) FindByEmail(_ context.Context, email string) (*Suppression, error) {
+	args := m.Called(email)
+	if s, ok := args.Get(0).(*Suppression); ok {
+		return s, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockRepository) FindAll(context.Context) ([]Suppression, error) {
+	args := m.Called()
+	return args.Get(0).([]Suppression), args.Error(1)
+}
+
+func (m *mockRepository) Delete(_ context.Context, email string) error {
+	args := m.Called(email)
+	return args.Error(0)
+}
+
+type mockPublisher struct {
+	mock.Mock
+}
+
+func (m *mockPublisher) Publish(_ context.Context, queue string, payload any) error {
+	args := m.Called(queue, payload)
+	return args.Error(0)
+}
+
+// --- Tests ---
+
+func setupSuppressionTest(t *testing.T) (*mockRepository, *mockPublisher, *SuppressionService) {
+	repo := new(mockRepository)
+	publisher := new(mockPublisher)
+	mockLog, _ := logger.NewTestLogger()
+	return repo, publisher, NewSuppressionService(repo, publisher, *mockLog)
+}
+
+func TestSuppress_SavesAndPublishes(t *testing.T) {
+	repo, publisher, service := setupSuppressionTest(t)
+
+	repo.On("FindByEmail", "user@example.com").Return(nil, gorm.ErrRecordNotFound)
+	repo.On("Create", mock.MatchedBy(func(s Suppression) bool {
+		return s.Email == "user@example.com" && s.Reason == ReasonBounce && s.Detail == "550 user unknown"
+	})).Return(nil)
+	publisher.On("Publish", rabbitmq.SubscriptionSuppressed, mock.MatchedBy(func(e SuppressionEvent) bool {
+		return e.Email == "user@example.com" && e.Reason == ReasonBounce
+	})).Return(nil)
+
+	err := service.Suppress(context.Background(), " User@Example.com ", ReasonBounce, "550 user unknown")
+
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+	publisher.AssertExpectations(t)
+}
+
+func TestSuppress_AlreadySuppressedPublishesAgain(t *testing.T) {
+	repo, publisher, service := setupSuppressionTest(t)
+
+	repo.On("FindByEmail", "user@example.com").
+		Return(&Suppression{Email: "user@example.com", Reason: ReasonBounce}, nil)
+	publisher.On("Publish", rabbitmq.SubscriptionSuppressed, mock.MatchedBy(func(e SuppressionEvent) bool {
+		return e.Email == "user@example.com" && e.Reason == ReasonBounce
+	})).Return(nil).Once()
+
+	err := service.Suppress(context.Background(), "user@example.com", ReasonComplaint, "")
+
+	require.NoError(t, err)
+	repo.AssertNotCalled(t, "Create", mock.Anything)
+	publisher.AssertExpectations(t)
+}
+
+func TestSuppress_RetriesFailedPublish(t *testing.T) {
+	repo, publisher, service := setupSuppressionTest(t)
+
+	repo.On("FindByEmail", "user@example.com").Return(nil, gorm.ErrRecordNotFound).Once()
+	repo.On("Create", mock.Anything).Return(nil).Once()
+	publisher.On("Publish", rabbitmq.SubscriptionSuppressed, mock.Anything).
+		Return(errors.New("channel closed")).Once()
+
+	err := service.Suppress(context.Background(), "user@example.com", ReasonBounce, "")
+	assert.ErrorIs(t, err, ErrFailedToPublishEvent)
+
+	repo.On("FindByEmail", "user@example.com").Return(&Suppression{Email: "user@example.com"}, nil)
+	publisher.On("Publish", rabbitmq.SubscriptionSuppressed, mock.Anything).Return(nil).Once()
+
+	require.NoError(t, service.Suppress(context.Background(), "user@example.com", ReasonBounce, ""))
+	publisher.AssertExpectations(t)
+}
+
+func TestSuppress_LookupErrorIsReturned(t *testing.T) {
+	repo, publisher, service := setupSuppressionTest(t)
+
+	repo.On("FindByEmail", "user@example.com").Return(nil, errors.New("connection refused"))
+
+	err := service.Suppress(context.Background(), "user@example.com", ReasonBounce, "")
+
+	assert.ErrorIs(t, err, ErrFailedToLoad)
+	repo.AssertNotCalled(t, "Create", mock.Anything)
+	publisher.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything)
+}
+
+func TestIsSuppressed_RepositoryErrorFailsOpen(t *testing.T) {
+	repo, _, service := setupSuppressionTest(t)
+
+	repo.On("FindByEmail", "user@example.com").Return(nil, errors.New("connection refused"))
+
+	assert.False(t, service.IsSuppressed(context.Background(), "user@example.com"))
+}
+
+func TestProcessWebhookEvents(t *testing.T) {
+	repo, publisher, service := setupSuppressionTest(t)
+
+	repo.On("FindByEmail", mock.Anything).Return(nil, gorm.ErrRecordNotFound)
+	repo.On("Create", mock.Anything).Return(nil)
+	publisher.On("Publish", rabbitmq.SubscriptionSuppressed, mock.Anything).Return(nil)
+
+	suppressed, err := service.ProcessWebhookEvents(context.Background(), []WebhookEvent{
+		{Type: WebhookEventBounce, Email: "hard@example.com", Permanent: true},
+		{Type: WebhookEventBounce, Email: "soft@example.com", Permanent: false},
+		{Type: WebhookEventComplaint, Email: "spam@example.com"},
+		{Type: "delivered", Email: "ok@example.com"},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, suppressed)
+	repo.AssertCalled(t, "Create", mock.MatchedBy(func(s Suppression) bool {
+		return s.Email == "spam@example.com" && s.Reason == ReasonComplaint
+	}))
+	repo.AssertNotCalled(t, "FindByEmail", "soft@example.com")
+}
+
+func TestRemove_NotFound(t *testing.T) {
+	repo, _, service := setupSuppressionTest(t)
+
+	repo.On("FindByEmail", "user@example.com").Return(nil, gorm.ErrRecordNotFound)
+
+	err := service.Remove(context.Background(), "user@example.com")
+
+	assert.ErrorIs(t, err, ErrSuppressionNotFound)
+	repo.AssertNotCalled(t, "Delete", mock.Anything)
+}
+
+func TestRemove_Success(t *testing.T) {
+	repo, _, service := setupSuppressionTest(t)
+
+	repo.On("FindByEmail", "user@example.com").Return(&Suppression{Email: "user@example.com"}, nil)
+	repo.On("Delete", "user@example.com").Return(nil)
+
+	require.NoError(t, service.Remove(context.Background(), "User@example.com"))
+	repo.AssertExpectations(t)
+}
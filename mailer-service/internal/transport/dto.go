@@ -0,0 +1,33 @@
+package transport
+
+import "errors"
+
+var (
+	ErrUnknownTransport = errors.New("unknown mail transport")
+	ErrUnknownTLSMode   = errors.New("unknown SMTP TLS mode")
+
+	// ErrPermanentFailure wraps errors after which the recipient address
+	// should not be mailed again, e.g. an unknown mailbox.
+	ErrPermanentFailure = errors.New("permanent delivery failure")
+)
+
+const (
+	TransportSMTP = "smtp"
+	TransportFile = "file"
+	TransportHTTP = "http"
+)
+
+const (
+	TLSModeStartTLS = "starttls"
+	TLSModeImplicit = "implicit"
+	TLSModeInsecure = "insecure"
+)
+
+type Email struct {
+	From    string
+	To      string
+	Subject string
+	HTML    string
+	Text    string
+	Headers map[string]string
+}
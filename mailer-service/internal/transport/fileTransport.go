@@ -0,0 +1,68 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/logger"
+)
+
+// FileTransport writes every message as an .eml file using the maildir layout:
+// the file is written to tmp/ first and then moved to new/, so readers never
+// see a partially written message.
+type FileTransport struct {
+	dir     string
+	counter atomic.Uint64
+	logger  logger.Logger
+}
+
+func NewFileTransport(dir string, logger logger.Logger) (*FileTransport, error) {
+	for _, sub := range []string{"tmp", "new", "cur"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create maildir %s: %w", dir, err)
+		}
+	}
+
+	return &FileTransport{
+		dir:    dir,
+		logger: logger,
+	}, nil
+}
+
+func (t *FileTransport) Send(ctx context.Context, email Email) error {
+	name := fmt.Sprintf("%d.%d.%d.eml", time.Now().UnixNano(), os.Getpid(), t.counter.Add(1))
+	tmpPath := filepath.Join(t.dir, "tmp", name)
+	newPath := filepath.Join(t.dir, "new", name)
+
+	file, err := os.Create(tmpPath)
+	if err != nil {
+		return fmt.Errorf("failed to create mail file: %w", err)
+	}
+
+	if _, err := buildMessage(email).WriteTo(file); err != nil {
+		_ = file.Close()
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to write mail file: %w", err)
+	}
+
+	if err := file.Close(); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to write mail file: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, newPath); err != nil {
+		return fmt.Errorf("failed to deliver mail file: %w", err)
+	}
+
+	t.logger.WithContext(ctx).Info("Email written to file", "to", email.To, "path", newPath)
+
+	return nil
+}
+
+func (t *FileTransport) Close() error {
+	return nil
+}
@@ -0,0 +1,61 @@
+//go:build unit
+// +build unit
+
+package transport
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileTransport_WritesEmlToMaildir(t *testing.T) {
+	dir := t.TempDir()
+	mockLog, _ := logger.NewTestLogger()
+
+	fileTransport, err := NewFileTransport(dir, *mockLog)
+	require.NoError(t, err)
+
+	err = fileTransport.Send(context.Background(), Email{
+		From:    "from@example.com",
+		To:      "to@example.com",
+		Subject: "Weather Update",
+		HTML:    "<p>Sunny</p>",
+	})
+	require.NoError(t, err)
+
+	files, err := filepath.Glob(filepath.Join(dir, "new", "*.eml"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	tmpFiles, err := os.ReadDir(filepath.Join(dir, "tmp"))
+	require.NoError(t, err)
+	assert.Empty(t, tmpFiles)
+
+	content, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "To: to@example.com")
+	assert.Contains(t, string(content), "Subject: Weather Update")
+	assert.Contains(t, string(content), "<p>Sunny</p>")
+}
+
+func TestFileTransport_UniqueFileNames(t *testing.T) {
+	dir := t.TempDir()
+	mockLog, _ := logger.NewTestLogger()
+
+	fileTransport, err := NewFileTransport(dir, *mockLog)
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, fileTransport.Send(context.Background(), Email{To: "to@example.com", HTML: "body"}))
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "new", "*.eml"))
+	require.NoError(t, err)
+	assert.Len(t, files, 3)
+}
@@ -0,0 +1,89 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/logger"
+)
+
+type httpRequestBody struct {
+	From    string            `json:"from"`
+	To      []string          `json:"to"`
+	Subject string            `json:"subject"`
+	HTML    string            `json:"html,omitempty"`
+	Text    string            `json:"text,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// HTTPTransport posts messages as JSON to a transactional email provider
+// (SendGrid/Mailgun-style API) authenticated with a bearer API key.
+type HTTPTransport struct {
+	apiUrl string
+	apiKey string
+	client *http.Client
+	logger logger.Logger
+}
+
+func NewHTTPTransport(apiUrl string, apiKey string, client *http.Client, logger logger.Logger) *HTTPTransport {
+	return &HTTPTransport{
+		apiUrl: strings.TrimRight(apiUrl, "/"),
+		apiKey: apiKey,
+		client: client,
+		logger: logger,
+	}
+}
+
+func (t *HTTPTransport) Send(ctx context.Context, email Email) error {
+	payload, err := json.Marshal(httpRequestBody{
+		From:    email.From,
+		To:      []string{email.To},
+		Subject: email.Subject,
+		HTML:    email.HTML,
+		Text:    email.Text,
+		Headers: email.Headers,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal email: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.apiUrl, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to build provider request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+t.apiKey)
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		t.logger.WithContext(ctx).Error("HTTP request to email provider failed", "error", err)
+		return err
+	}
+
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			t.logger.WithContext(ctx).Error("Failed to close response body", "error", err)
+		}
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read provider response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("email provider returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	return nil
+}
+
+func (t *HTTPTransport) Close() error {
+	t.client.CloseIdleConnections()
+	return nil
+}
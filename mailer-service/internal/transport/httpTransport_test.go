@@ -0,0 +1,61 @@
+//go:build unit
+// +build unit
+
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPTransport_Success(t *testing.T) {
+	var received httpRequestBody
+	var authHeader string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authHeader = r.Header.Get("Authorization")
+		_ = json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	mockLog, _ := logger.NewTestLogger()
+	httpTransport := NewHTTPTransport(server.URL, "secret", server.Client(), *mockLog)
+
+	err := httpTransport.Send(context.Background(), Email{
+		From:    "from@example.com",
+		To:      "to@example.com",
+		Subject: "Weather Update",
+		HTML:    "<p>Sunny</p>",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer secret", authHeader)
+	assert.Equal(t, "from@example.com", received.From)
+	assert.Equal(t, []string{"to@example.com"}, received.To)
+	assert.Equal(t, "Weather Update", received.Subject)
+	assert.Equal(t, "<p>Sunny</p>", received.HTML)
+}
+
+func TestHTTPTransport_ProviderError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"message":"invalid api key"}`))
+	}))
+	defer server.Close()
+
+	mockLog, _ := logger.NewTestLogger()
+	httpTransport := NewHTTPTransport(server.URL, "wrong", server.Client(), *mockLog)
+
+	err := httpTransport.Send(context.Background(), Email{To: "to@example.com", HTML: "body"})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "401")
+}
@@ -0,0 +1,26 @@
+package transport
+
+import "gopkg.in/gomail.v2"
+
+func buildMessage(email Email) *gomail.Message {
+	m := gomail.NewMessage()
+	m.SetHeader("From", email.From)
+	m.SetHeader("To", email.To)
+	m.SetHeader("Subject", email.Subject)
+
+	for key, value := range email.Headers {
+		m.SetHeader(key, value)
+	}
+
+	switch {
+	case email.Text != "" && email.HTML != "":
+		m.SetBody("text/plain", email.Text)
+		m.AddAlternative("text/html", email.HTML)
+	case email.Text != "":
+		m.SetBody("text/plain", email.Text)
+	default:
+		m.SetBody("text/html", email.HTML)
+	}
+
+	return m
+}
@@ -0,0 +1,118 @@
+package transport
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/logger"
+	"golang.org/x/time/rate"
+)
+
+// Per-domain limiters are pruned once there are this many of them.
+const maxDomainLimiters = 1000
+
+// Waits longer than this are logged, they mean the limit is saturated.
+const slowWaitThreshold = time.Second
+
+type Limit struct {
+	PerSecond float64
+	Burst     int
+}
+
+// NewLimit returns a token bucket limit. A negative rate disables limiting.
+func NewLimit(perSecond float64, burst int) Limit {
+	return Limit{PerSecond: perSecond, Burst: burst}
+}
+
+func (l Limit) newLimiter() *rate.Limiter {
+	if l.PerSecond < 0 {
+		return rate.NewLimiter(rate.Inf, 0)
+	}
+	return rate.NewLimiter(rate.Limit(l.PerSecond), l.Burst)
+}
+
+// RateLimitedTransport holds every send until both the global and the
+// recipient domain token buckets allow it. Send blocks instead of failing,
+// so the RabbitMQ consumer stops acking and the broker stops delivering
+// once the prefetch window is full.
+type RateLimitedTransport struct {
+	next        Transport
+	global      *rate.Limiter
+	domainLimit Limit
+	domains     map[string]*rate.Limiter
+	mux         sync.Mutex
+	logger      logger.Logger
+}
+
+func NewRateLimitedTransport(next Transport, global Limit, perDomain Limit,
+	logger logger.Logger) *RateLimitedTransport {
+	return &RateLimitedTransport{
+		next:        next,
+		global:      global.newLimiter(),
+		domainLimit: perDomain,
+		domains:     make(map[string]*rate.Limiter),
+		logger:      logger,
+	}
+}
+
+func (t *RateLimitedTransport) Send(ctx context.Context, email Email) error {
+	started := time.Now()
+
+	if err := t.global.Wait(ctx); err != nil {
+		return err
+	}
+
+	domain := recipientDomain(email.To)
+	if err := t.domainLimiter(domain).Wait(ctx); err != nil {
+		return err
+	}
+
+	if waited := time.Since(started); waited > slowWaitThreshold {
+		t.logger.WithContext(ctx).Info("Send delayed by rate limit", "domain", domain, "waited", waited)
+	}
+
+	return t.next.Send(ctx, email)
+}
+
+func (t *RateLimitedTransport) Close() error {
+	return t.next.Close()
+}
+
+func (t *RateLimitedTransport) domainLimiter(domain string) *rate.Limiter {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	if limiter, ok := t.domains[domain]; ok {
+		return limiter
+	}
+
+	if len(t.domains) >= maxDomainLimiters {
+		t.pruneDomains()
+	}
+
+	limiter := t.domainLimit.newLimiter()
+	t.domains[domain] = limiter
+	return limiter
+}
+
+// pruneDomains drops limiters with a full bucket, a fresh limiter behaves
+// exactly the same.
+func (t *RateLimitedTransport) pruneDomains() {
+	now := time.Now()
+
+	for domain, limiter := range t.domains {
+		if limiter.TokensAt(now) >= float64(limiter.Burst()) {
+			delete(t.domains, domain)
+		}
+	}
+}
+
+func recipientDomain(address string) string {
+	at := strings.LastIndex(address, "@")
+	if at < 0 {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSuffix(address[at+1:], ">"))
+}
@@ -0,0 +1,83 @@
+//go:build unit
+// +build unit
+
+package transport
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingTransport struct {
+	sent   []Email
+	closed bool
+}
+
+func (r *recordingTransport) Send(_ context.Context, email Email) error {
+	r.sent = append(r.sent, email)
+	return nil
+}
+
+func (r *recordingTransport) Close() error {
+	r.closed = true
+	return nil
+}
+
+func TestRateLimitedTransport_GlobalLimitDelaysSends(t *testing.T) {
+	next := &recordingTransport{}
+	mockLog, _ := logger.NewTestLogger()
+
+	limited := NewRateLimitedTransport(next, NewLimit(50, 1), NewLimit(-1, 0), *mockLog)
+
+	started := time.Now()
+	for _, to := range []string{"a@one.com", "b@two.com", "c@three.com"} {
+		require.NoError(t, limited.Send(context.Background(), Email{To: to}))
+	}
+
+	// burst of 1, then one token every 20ms
+	assert.GreaterOrEqual(t, time.Since(started), 35*time.Millisecond)
+	assert.Len(t, next.sent, 3)
+}
+
+func TestRateLimitedTransport_DomainLimitIsPerDomain(t *testing.T) {
+	next := &recordingTransport{}
+	mockLog, _ := logger.NewTestLogger()
+
+	limited := NewRateLimitedTransport(next, NewLimit(-1, 0), NewLimit(0.001, 1), *mockLog)
+
+	started := time.Now()
+	require.NoError(t, limited.Send(context.Background(), Email{To: "a@gmail.com"}))
+	require.NoError(t, limited.Send(context.Background(), Email{To: "b@Outlook.com"}))
+	assert.Less(t, time.Since(started), 100*time.Millisecond)
+
+	assert.Contains(t, limited.domains, "gmail.com")
+	assert.Contains(t, limited.domains, "outlook.com")
+	assert.Less(t, limited.domainLimiter("gmail.com").Tokens(), 1.0)
+}
+
+func TestRateLimitedTransport_PrunesFullBuckets(t *testing.T) {
+	mockLog, _ := logger.NewTestLogger()
+	limited := NewRateLimitedTransport(&recordingTransport{}, NewLimit(-1, 0), NewLimit(1, 1), *mockLog)
+
+	busy := limited.domainLimiter("busy.com")
+	require.True(t, busy.Allow())
+	limited.domainLimiter("idle.com")
+
+	limited.pruneDomains()
+
+	assert.Contains(t, limited.domains, "busy.com")
+	assert.NotContains(t, limited.domains, "idle.com")
+}
+
+func TestRateLimitedTransport_Close(t *testing.T) {
+	next := &recordingTransport{}
+	mockLog, _ := logger.NewTestLogger()
+
+	require.NoError(t, NewRateLimitedTransport(next, NewLimit(1, 1), NewLimit(1, 1), *mockLog).Close())
+	assert.True(t, next.closed)
+}
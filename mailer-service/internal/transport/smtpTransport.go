@@ -0,0 +1,245 @@
+package transport
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/logger"
+	"gopkg.in/gomail.v2"
+)
+
+var errSendAbandoned = errors.New("SMTP send abandoned")
+
+type smtpDialer interface {
+	Dial() (gomail.SendCloser, error)
+}
+
+type pooledConn struct {
+	sender   gomail.SendCloser
+	lastUsed time.Time
+}
+
+type SMTPTransport struct {
+	dialer      smtpDialer
+	idle        chan *pooledConn
+	idleTimeout time.Duration
+	mux         sync.Mutex
+	closed      bool
+	logger      logger.Logger
+}
+
+func NewSMTPDialer(host string, port int, username string, password string, tlsMode string) (*gomail.Dialer, error) {
+	dialer := gomail.NewDialer(host, port, username, password)
+
+	switch tlsMode {
+	case "":
+		// keep gomail's default: implicit TLS on port 465, STARTTLS otherwise
+	case TLSModeStartTLS:
+		dialer.SSL = false
+	case TLSModeImplicit:
+		dialer.SSL = true
+	case TLSModeInsecure:
+		dialer.SSL = false
+		// meant for local relays with self-signed certificates only
+		dialer.TLSConfig = &tls.Config{ServerName: host, InsecureSkipVerify: true}
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownTLSMode, tlsMode)
+	}
+
+	return dialer, nil
+}
+
+func NewSMTPTransport(dialer smtpDialer, poolSize int, idleTimeout time.Duration,
+	logger logger.Logger) *SMTPTransport {
+	if poolSize < 1 {
+		poolSize = 1
+	}
+
+	return &SMTPTransport{
+		dialer:      dialer,
+		idle:        make(chan *pooledConn, poolSize),
+		idleTimeout: idleTimeout,
+		logger:      logger,
+	}
+}
+
+func (t *SMTPTransport) Send(ctx context.Context, email Email) error {
+	msg := buildMessage(email)
+
+	from, to, err := envelope(email)
+	if err != nil {
+		return err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	conn, reused, err := t.acquire()
+	if err != nil {
+		return fmt.Errorf("failed to dial SMTP server: %w", err)
+	}
+
+	err = t.send(ctx, conn, from, to, msg)
+
+	// A pooled connection may have been dropped by the server while idle,
+	// so retry once on a fresh one before giving up.
+	if err != nil && reused && !errors.Is(err, ErrPermanentFailure) && !errors.Is(err, errSendAbandoned) {
+		t.logger.WithContext(ctx).Info("Pooled SMTP connection failed, redialing", "error", err)
+		t.discard(conn)
+
+		conn, err = t.dial()
+		if err != nil {
+			return fmt.Errorf("failed to dial SMTP server: %w", err)
+		}
+		err = t.send(ctx, conn, from, to, msg)
+	}
+
+	if errors.Is(err, errSendAbandoned) {
+		return err
+	}
+	if err != nil {
+		t.discard(conn)
+		return err
+	}
+
+	t.release(conn)
+	return nil
+}
+
+// send runs the SMTP transaction on conn and gives up on it once ctx is
+// done. gomail can't interrupt a transaction, so the abandoned connection
+// is closed when it finishes instead of going back to the pool; the server
+// may still deliver a message it had already accepted.
+func (t *SMTPTransport) send(ctx context.Context, conn *pooledConn, from string, to []string,
+	msg io.WriterTo) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- classifySMTPError(conn.sender.Send(from, to, msg))
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		go func() {
+			<-done
+			t.discard(conn)
+		}()
+		return fmt.Errorf("%w: %w", errSendAbandoned, ctx.Err())
+	}
+}
+
+func (t *SMTPTransport) Close() error {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	if t.closed {
+		return nil
+	}
+	t.closed = true
+	close(t.idle)
+
+	var firstErr error
+	for conn := range t.idle {
+		if err := conn.sender.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (t *SMTPTransport) acquire() (*pooledConn, bool, error) {
+	for {
+		select {
+		case conn, ok := <-t.idle:
+			if !ok {
+				conn, err := t.dial()
+				return conn, false, err
+			}
+			if t.idleTimeout > 0 && time.Since(conn.lastUsed) > t.idleTimeout {
+				t.discard(conn)
+				continue
+			}
+			return conn, true, nil
+		default:
+			conn, err := t.dial()
+			return conn, false, err
+		}
+	}
+}
+
+func (t *SMTPTransport) dial() (*pooledConn, error) {
+	sender, err := t.dialer.Dial()
+	if err != nil {
+		return nil, err
+	}
+	return &pooledConn{sender: sender, lastUsed: time.Now()}, nil
+}
+
+func (t *SMTPTransport) release(conn *pooledConn) {
+	conn.lastUsed = time.Now()
+
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	if t.closed {
+		t.discard(conn)
+		return
+	}
+
+	select {
+	case t.idle <- conn:
+	default:
+		// pool is full
+		t.discard(conn)
+	}
+}
+
+func (t *SMTPTransport) discard(conn *pooledConn) {
+	if err := conn.sender.Close(); err != nil {
+		t.logger.Debug("Failed to close SMTP connection", "error", err)
+	}
+}
+
+func envelope(email Email) (string, []string, error) {
+	from, err := mail.ParseAddress(email.From)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid sender address %q: %w", email.From, err)
+	}
+
+	to, err := mail.ParseAddress(email.To)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid recipient address %q: %w", email.To, err)
+	}
+
+	return from.Address, []string{to.Address}, nil
+}
+
+// Mailbox replies that mean the recipient does not exist or cannot receive
+// mail: 550 mailbox unavailable, 551 user not local, 553 mailbox name invalid.
+var permanentSMTPCodes = map[int]bool{550: true, 551: true, 553: true}
+
+// classifySMTPError marks recipient rejections as permanent. Replies with a
+// 5.7.x enhanced status are policy rejections of our own mail (relaying,
+// SPF, spam filters) and say nothing about the recipient, so they are not.
+func classifySMTPError(err error) error {
+	var smtpErr *textproto.Error
+	if !errors.As(err, &smtpErr) {
+		return err
+	}
+
+	if permanentSMTPCodes[smtpErr.Code] && !strings.HasPrefix(smtpErr.Msg, "5.7.") {
+		return fmt.Errorf("%w: %w", ErrPermanentFailure, err)
+	}
+
+	return err
+}
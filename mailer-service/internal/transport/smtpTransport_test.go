@@ -0,0 +1,215 @@
+//go:build unit
+// +build unit
+
+package transport
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/textproto"
+	"testing"
+	"time"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/gomail.v2"
+)
+
+// --- Fakes ---
+
+type fakeSendCloser struct {
+	sendErr error
+	sent    int
+	closed  bool
+
+	// when set, Send blocks until it is closed and Close signals onClose
+	release chan struct{}
+	onClose chan struct{}
+}
+
+func (f *fakeSendCloser) Send(from string, to []string, msg io.WriterTo) error {
+	if f.release != nil {
+		<-f.release
+	}
+	if f.sendErr != nil {
+		return f.sendErr
+	}
+	f.sent++
+	return nil
+}
+
+func (f *fakeSendCloser) Close() error {
+	f.closed = true
+	if f.onClose != nil {
+		close(f.onClose)
+	}
+	return nil
+}
+
+type fakeDialer struct {
+	conns []*fakeSendCloser
+	dials int
+}
+
+func (f *fakeDialer) Dial() (gomail.SendCloser, error) {
+	if f.dials >= len(f.conns) {
+		return nil, errors.New("dial failed")
+	}
+	conn := f.conns[f.dials]
+	f.dials++
+	return conn, nil
+}
+
+// --- Tests ---
+
+func TestSMTPTransport_ReusesConnection(t *testing.T) {
+	conn := &fakeSendCloser{}
+	dialer := &fakeDialer{conns: []*fakeSendCloser{conn}}
+	mockLog, _ := logger.NewTestLogger()
+
+	smtpTransport := NewSMTPTransport(dialer, 1, time.Minute, *mockLog)
+
+	require.NoError(t, smtpTransport.Send(context.Background(), Email{From: "a@example.com", To: "b@example.com"}))
+	require.NoError(t, smtpTransport.Send(context.Background(), Email{From: "a@example.com", To: "c@example.com"}))
+
+	assert.Equal(t, 1, dialer.dials)
+	assert.Equal(t, 2, conn.sent)
+
+	require.NoError(t, smtpTransport.Close())
+	assert.True(t, conn.closed)
+}
+
+func TestSMTPTransport_RedialsStaleConnection(t *testing.T) {
+	stale := &fakeSendCloser{}
+	fresh := &fakeSendCloser{}
+	dialer := &fakeDialer{conns: []*fakeSendCloser{stale, fresh}}
+	mockLog, _ := logger.NewTestLogger()
+
+	smtpTransport := NewSMTPTransport(dialer, 1, time.Minute, *mockLog)
+
+	require.NoError(t, smtpTransport.Send(context.Background(), Email{From: "a@example.com", To: "b@example.com"}))
+
+	stale.sendErr = errors.New("connection reset")
+	require.NoError(t, smtpTransport.Send(context.Background(), Email{From: "a@example.com", To: "b@example.com"}))
+
+	assert.Equal(t, 2, dialer.dials)
+	assert.True(t, stale.closed)
+	assert.Equal(t, 1, fresh.sent)
+}
+
+func TestSMTPTransport_DropsIdleConnection(t *testing.T) {
+	first := &fakeSendCloser{}
+	second := &fakeSendCloser{}
+	dialer := &fakeDialer{conns: []*fakeSendCloser{first, second}}
+	mockLog, _ := logger.NewTestLogger()
+
+	smtpTransport := NewSMTPTransport(dialer, 1, time.Nanosecond, *mockLog)
+
+	require.NoError(t, smtpTransport.Send(context.Background(), Email{From: "a@example.com", To: "b@example.com"}))
+	time.Sleep(time.Millisecond)
+	require.NoError(t, smtpTransport.Send(context.Background(), Email{From: "a@example.com", To: "b@example.com"}))
+
+	assert.Equal(t, 2, dialer.dials)
+	assert.True(t, first.closed)
+	assert.Equal(t, 1, second.sent)
+}
+
+func TestSMTPTransport_DialError(t *testing.T) {
+	dialer := &fakeDialer{}
+	mockLog, _ := logger.NewTestLogger()
+
+	smtpTransport := NewSMTPTransport(dialer, 1, time.Minute, *mockLog)
+
+	err := smtpTransport.Send(context.Background(), Email{From: "a@example.com", To: "b@example.com"})
+	assert.Error(t, err)
+}
+
+func TestSMTPTransport_PermanentFailure(t *testing.T) {
+	conn := &fakeSendCloser{sendErr: &textproto.Error{Code: 550, Msg: "5.1.1 user unknown"}}
+	dialer := &fakeDialer{conns: []*fakeSendCloser{conn}}
+	mockLog, _ := logger.NewTestLogger()
+
+	smtpTransport := NewSMTPTransport(dialer, 1, time.Minute, *mockLog)
+
+	err := smtpTransport.Send(context.Background(), Email{From: "a@example.com", To: "b@example.com"})
+	assert.ErrorIs(t, err, ErrPermanentFailure)
+}
+
+func TestSMTPTransport_CancelledSendIsAbandoned(t *testing.T) {
+	conn := &fakeSendCloser{release: make(chan struct{}), onClose: make(chan struct{})}
+	dialer := &fakeDialer{conns: []*fakeSendCloser{conn}}
+	mockLog, _ := logger.NewTestLogger()
+
+	smtpTransport := NewSMTPTransport(dialer, 2, time.Minute, *mockLog)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := smtpTransport.Send(ctx, Email{From: "a@example.com", To: "b@example.com"})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// the connection is closed once the transaction ends, not pooled
+	close(conn.release)
+	select {
+	case <-conn.onClose:
+	case <-time.After(time.Second):
+		t.Fatal("abandoned connection was not closed")
+	}
+	assert.Empty(t, smtpTransport.idle)
+}
+
+func TestSMTPTransport_CancelledBeforeDial(t *testing.T) {
+	dialer := &fakeDialer{}
+	mockLog, _ := logger.NewTestLogger()
+
+	smtpTransport := NewSMTPTransport(dialer, 1, time.Minute, *mockLog)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := smtpTransport.Send(ctx, Email{From: "a@example.com", To: "b@example.com"})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 0, dialer.dials)
+}
+
+func TestClassifySMTPError(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		permanent bool
+	}{
+		{"unknown mailbox", &textproto.Error{Code: 550, Msg: "5.1.1 user unknown"}, true},
+		{"invalid mailbox name", &textproto.Error{Code: 553, Msg: "mailbox name not allowed"}, true},
+		{"policy rejection", &textproto.Error{Code: 550, Msg: "5.7.1 relaying denied"}, false},
+		{"mailbox busy", &textproto.Error{Code: 450, Msg: "4.2.1 try again later"}, false},
+		{"auth failure", &textproto.Error{Code: 535, Msg: "5.7.8 bad credentials"}, false},
+		{"network error", errors.New("connection reset"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := classifySMTPError(tt.err)
+			assert.Equal(t, tt.permanent, errors.Is(err, ErrPermanentFailure))
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestNewSMTPDialer_TLSModes(t *testing.T) {
+	dialer, err := NewSMTPDialer("smtp.example.com", 587, "user", "pass", TLSModeImplicit)
+	require.NoError(t, err)
+	assert.True(t, dialer.SSL)
+
+	dialer, err = NewSMTPDialer("smtp.example.com", 465, "user", "pass", TLSModeStartTLS)
+	require.NoError(t, err)
+	assert.False(t, dialer.SSL)
+
+	dialer, err = NewSMTPDialer("smtp.example.com", 587, "user", "pass", TLSModeInsecure)
+	require.NoError(t, err)
+	assert.True(t, dialer.TLSConfig.InsecureSkipVerify)
+
+	_, err = NewSMTPDialer("smtp.example.com", 587, "user", "pass", "bogus")
+	assert.ErrorIs(t, err, ErrUnknownTLSMode)
+}
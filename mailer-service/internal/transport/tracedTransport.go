@@ -0,0 +1,48 @@
+package transport
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "transport"
+
+// TracedTransport records every send as a client span named after the
+// transport, e.g. "smtp send", as a child of the message being handled.
+type TracedTransport struct {
+	next Transport
+	name string
+}
+
+func NewTracedTransport(next Transport, name string) *TracedTransport {
+	return &TracedTransport{next: next, name: name}
+}
+
+func (t *TracedTransport) Send(ctx context.Context, email Email) error {
+	ctx, span := otel.Tracer(tracerName).Start(ctx, t.name+" send",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("mail.transport", t.name),
+			attribute.String("mail.recipient_domain", recipientDomain(email.To)),
+		),
+	)
+	defer span.End()
+
+	err := t.next.Send(ctx, email)
+	if err != nil {
+		span.SetAttributes(attribute.Bool("mail.permanent_failure", errors.Is(err, ErrPermanentFailure)))
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return err
+}
+
+func (t *TracedTransport) Close() error {
+	return t.next.Close()
+}
@@ -0,0 +1,43 @@
+//go:build unit
+// +build unit
+
+package transport
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+type failingTransport struct {
+	err error
+}
+
+func (f *failingTransport) Send(context.Context, Email) error { return f.err }
+func (f *failingTransport) Close() error                      { return nil }
+
+func TestTracedTransport_RecordsSendSpan(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	bounce := fmt.Errorf("%w: 550 no such user", ErrPermanentFailure)
+	traced := NewTracedTransport(&failingTransport{err: bounce}, TransportSMTP)
+
+	err := traced.Send(context.Background(), Email{To: "user@Example.com"})
+	assert.ErrorIs(t, err, ErrPermanentFailure)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "smtp send", spans[0].Name())
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Contains(t, spans[0].Attributes(), attribute.String("mail.recipient_domain", "example.com"))
+	assert.Contains(t, spans[0].Attributes(), attribute.Bool("mail.permanent_failure", true))
+}
@@ -0,0 +1,68 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/logger"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/mailer-service/config"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+type Transport interface {
+	Send(ctx context.Context, email Email) error
+	Close() error
+}
+
+// NewTransport builds the configured transport, traced, behind the send
+// rate limiter.
+func NewTransport(config config.Config, logger logger.Logger) (Transport, error) {
+	base, err := newBaseTransport(config, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	// the span covers the send only, not the wait for the rate limiter
+	base = NewTracedTransport(base, config.MailTransport)
+
+	global := NewLimit(config.MailRatePerSecond, config.MailRateBurst)
+	perDomain := NewLimit(config.MailDomainRatePerSecond, config.MailDomainRateBurst)
+
+	logger.Info("Mail send rate limits",
+		"perSecond", config.MailRatePerSecond, "burst", config.MailRateBurst,
+		"domainPerSecond", config.MailDomainRatePerSecond, "domainBurst", config.MailDomainRateBurst)
+
+	return NewRateLimitedTransport(base, global, perDomain, logger), nil
+}
+
+func newBaseTransport(config config.Config, logger logger.Logger) (Transport, error) {
+	switch config.MailTransport {
+	case TransportSMTP:
+		dialer, err := NewSMTPDialer(config.MailDialerHost, config.MailDialerPort,
+			config.MailUsername, config.MailPassword, config.MailSMTPTLSMode)
+		if err != nil {
+			return nil, err
+		}
+
+		logger.Info("Using SMTP mail transport", "host", config.MailDialerHost, "port", config.MailDialerPort)
+		return NewSMTPTransport(dialer, config.MailSMTPPoolSize, config.MailSMTPIdleTimeout, logger), nil
+
+	case TransportFile:
+		logger.Info("Using file mail transport", "dir", config.MailFileDir)
+		return NewFileTransport(config.MailFileDir, logger)
+
+	case TransportHTTP:
+		client := &http.Client{
+			Timeout:   10 * time.Second,
+			Transport: otelhttp.NewTransport(http.DefaultTransport),
+		}
+
+		logger.Info("Using HTTP mail transport", "url", config.MailHTTPUrl)
+		return NewHTTPTransport(config.MailHTTPUrl, config.MailHTTPApiKey, client, logger), nil
+
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownTransport, config.MailTransport)
+	}
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/kelseyhightower/envconfig"
@@ -25,9 +26,97 @@ type Config struct {
 	RedisHost     string `envconfig:"REDIS_HOST"`
 	RedisPassword string `envconfig:"REDIS_PASSWORD"`
 
+	// Without Redis an in-process LRU of this many entries is used, and
+	// Redis is pinged every interval to switch back.
+	RedisRetryInterval time.Duration `envconfig:"REDIS_RETRY_INTERVAL"`
+	CacheMemoryEntries int           `envconfig:"CACHE_MEMORY_ENTRIES"`
+
+	// Weather is also cached in process for at most the L1 TTL in front of
+	// Redis; a negative size disables it.
+	L1CacheEntries int           `envconfig:"L1_CACHE_ENTRIES"`
+	L1CacheTTL     time.Duration `envconfig:"L1_CACHE_TTL"`
+
+	// The cities of due subscriptions are refreshed this long before each
+	// scheduled dispatch, this many at a time; a negative lead disables it.
+	CacheWarmUpLead        time.Duration `envconfig:"CACHE_WARMUP_LEAD"`
+	CacheWarmUpConcurrency int           `envconfig:"CACHE_WARMUP_CONCURRENCY"`
+
 	RabbitMQUrl string `envconfig:"RABBITMQ_URL" required:"true"`
 	MQUsername  string `envconfig:"MQ_USERNAME" required:"true"`
 	MQPassword  string `envconfig:"MQ_PASSWORD" required:"true"`
+
+	// Change-only subscriptions are emailed when temperature or humidity
+	// move by at least these amounts, or the description changes; a zero
+	// threshold treats any move as a change.
+	ChangeTemperatureThreshold float64 `envconfig:"CHANGE_TEMPERATURE_THRESHOLD" default:"2"`
+	ChangeHumidityThreshold    float64 `envconfig:"CHANGE_HUMIDITY_THRESHOLD" default:"15"`
+	ChangeIgnoreCondition      bool    `envconfig:"CHANGE_IGNORE_CONDITION"`
+
+	// The /admin routes are only registered when an API key or basic auth
+	// credentials are set.
+	AdminAPIKey   string `envconfig:"ADMIN_API_KEY"`
+	AdminUsername string `envconfig:"ADMIN_USERNAME"`
+	AdminPassword string `envconfig:"ADMIN_PASSWORD"`
+
+	// Quotas of GET /api/weather per UTC day and month; a negative quota
+	// is unlimited. Anonymous callers are counted per IP.
+	APIKeyRequired     bool  `envconfig:"API_KEY_REQUIRED"`
+	AnonDailyQuota     int64 `envconfig:"ANON_DAILY_QUOTA"`
+	AnonMonthlyQuota   int64 `envconfig:"ANON_MONTHLY_QUOTA"`
+	APIKeyDailyQuota   int64 `envconfig:"API_KEY_DAILY_QUOTA"`
+	APIKeyMonthlyQuota int64 `envconfig:"API_KEY_MONTHLY_QUOTA"`
+
+	// Keys are looked up in the database at most once per TTL per instance,
+	// so a revoked key may be served that long by other instances; a
+	// negative TTL disables the cache.
+	APIKeyCacheTTL time.Duration `envconfig:"API_KEY_CACHE_TTL"`
+
+	// POST /api/subscribe accepts this many requests per client IP and per
+	// target email in a sliding window; a negative limit disables the check.
+	SubscribeIPLimit     int64         `envconfig:"SUBSCRIBE_IP_LIMIT"`
+	SubscribeIPWindow    time.Duration `envconfig:"SUBSCRIBE_IP_WINDOW"`
+	SubscribeEmailLimit  int64         `envconfig:"SUBSCRIBE_EMAIL_LIMIT"`
+	SubscribeEmailWindow time.Duration `envconfig:"SUBSCRIBE_EMAIL_WINDOW"`
+
+	// When a secret is set, subscribing requires a captcha token checked
+	// against the provider's siteverify URL.
+	CaptchaSecret    string `envconfig:"CAPTCHA_SECRET"`
+	CaptchaVerifyURL string `envconfig:"CAPTCHA_VERIFY_URL"`
+
+	// Subscriber emails are checked against a disposable domains list,
+	// the built-in one unless a file is given, and optionally their MX.
+	DisposableDomainsFile string        `envconfig:"DISPOSABLE_DOMAINS_FILE"`
+	EmailMXCheck          bool          `envconfig:"EMAIL_MX_CHECK"`
+	EmailMXCacheTTL       time.Duration `envconfig:"EMAIL_MX_CACHE_TTL"`
+
+	// Announced on /api/v1 responses in the Deprecation and Sunset headers,
+	// as RFC 3339 timestamps.
+	APIV1DeprecatedAt time.Time `envconfig:"API_V1_DEPRECATED_AT"`
+	APIV1Sunset       time.Time `envconfig:"API_V1_SUNSET"`
+
+	// The client IP is taken from X-Forwarded-For only for requests coming
+	// from these addresses or CIDRs; by default no proxy is trusted.
+	TrustedProxies []string `envconfig:"TRUSTED_PROXIES"`
+
+	// On SIGINT or SIGTERM in-flight requests, jobs and messages get this
+	// long to finish before connections are closed.
+	ShutdownTimeout time.Duration `envconfig:"SHUTDOWN_TIMEOUT"`
+
+	// Requests are cancelled after the timeout of their route, or the
+	// default one; a negative timeout lets the request run without one.
+	RequestTimeout time.Duration `envconfig:"REQUEST_TIMEOUT"`
+	RouteTimeouts  RouteTimeouts `envconfig:"ROUTE_TIMEOUTS"`
+
+	// Spans are exported to OTLP (see OTEL_EXPORTER_OTLP_ENDPOINT), to stdout
+	// or nowhere; this share of new traces is sampled, 0 sampling none.
+	TracingExporter    string  `envconfig:"TRACING_EXPORTER"`
+	TracingSampleRatio float64 `envconfig:"TRACING_SAMPLE_RATIO" default:"1"`
+
+	// /readyz gives each dependency check this long. When a city is set it
+	// also looks up its weather from the providers, at most once per TTL.
+	ReadinessCheckTimeout time.Duration `envconfig:"READINESS_CHECK_TIMEOUT"`
+	ReadinessUpstreamCity string        `envconfig:"READINESS_UPSTREAM_CITY"`
+	ReadinessUpstreamTTL  time.Duration `envconfig:"READINESS_UPSTREAM_TTL"`
 }
 
 func LoadEnvVariables() (*Config, error) {
@@ -111,6 +200,105 @@ func (c *Config) validate() error {
 		errors = append(errors, "MQ_PASSWORD is required")
 	}
 
+	if c.ChangeTemperatureThreshold < 0 {
+		errors = append(errors, "CHANGE_TEMPERATURE_THRESHOLD must not be negative")
+	}
+	if c.ChangeHumidityThreshold < 0 {
+		errors = append(errors, "CHANGE_HUMIDITY_THRESHOLD must not be negative")
+	}
+
+	if c.AnonDailyQuota == 0 {
+		c.AnonDailyQuota = 100
+	}
+	if c.AnonMonthlyQuota == 0 {
+		c.AnonMonthlyQuota = 1000
+	}
+	if c.APIKeyDailyQuota == 0 {
+		c.APIKeyDailyQuota = 10000
+	}
+	if c.APIKeyMonthlyQuota == 0 {
+		c.APIKeyMonthlyQuota = 200000
+	}
+	if c.APIKeyCacheTTL == 0 {
+		c.APIKeyCacheTTL = 30 * time.Second
+	}
+
+	if c.SubscribeIPLimit == 0 {
+		c.SubscribeIPLimit = 10
+	}
+	if c.SubscribeIPWindow <= 0 {
+		c.SubscribeIPWindow = time.Hour
+	}
+	if c.SubscribeEmailLimit == 0 {
+		c.SubscribeEmailLimit = 3
+	}
+	if c.SubscribeEmailWindow <= 0 {
+		c.SubscribeEmailWindow = 24 * time.Hour
+	}
+
+	if c.EmailMXCacheTTL <= 0 {
+		c.EmailMXCacheTTL = time.Hour
+	}
+
+	// the announced v1 deprecation date, see README
+	if c.APIV1DeprecatedAt.IsZero() {
+		c.APIV1DeprecatedAt = time.Date(2026, time.November, 1, 0, 0, 0, 0, time.UTC)
+	}
+	if c.APIV1Sunset.IsZero() {
+		c.APIV1Sunset = c.APIV1DeprecatedAt.AddDate(0, 6, 0)
+	}
+
+	if c.RedisRetryInterval <= 0 {
+		c.RedisRetryInterval = 5 * time.Second
+	}
+	if c.CacheMemoryEntries <= 0 {
+		c.CacheMemoryEntries = 10000
+	}
+
+	if c.L1CacheEntries == 0 {
+		c.L1CacheEntries = 1000
+	}
+	if c.L1CacheTTL <= 0 {
+		c.L1CacheTTL = 30 * time.Second
+	}
+
+	if c.CacheWarmUpLead == 0 {
+		c.CacheWarmUpLead = 5 * time.Minute
+	}
+	if c.CacheWarmUpConcurrency <= 0 {
+		c.CacheWarmUpConcurrency = 5
+	}
+
+	if c.ShutdownTimeout <= 0 {
+		c.ShutdownTimeout = 30 * time.Second
+	}
+
+	if c.RequestTimeout == 0 {
+		c.RequestTimeout = 10 * time.Second
+	}
+
+	if c.TracingExporter == "" {
+		c.TracingExporter = "none"
+	}
+	if c.TracingSampleRatio < 0 || c.TracingSampleRatio > 1 {
+		errors = append(errors, "TRACING_SAMPLE_RATIO must be between 0 and 1")
+	}
+
+	if c.ReadinessCheckTimeout <= 0 {
+		c.ReadinessCheckTimeout = 2 * time.Second
+	}
+	if c.ReadinessUpstreamTTL <= 0 {
+		c.ReadinessUpstreamTTL = time.Minute
+	}
+
+	if c.CaptchaSecret != "" && c.CaptchaVerifyURL == "" {
+		errors = append(errors, "CAPTCHA_VERIFY_URL is required when CAPTCHA_SECRET is set")
+	}
+
+	if c.AdminUsername != "" && c.AdminPassword == "" {
+		errors = append(errors, "ADMIN_PASSWORD is required when ADMIN_USERNAME is set")
+	}
+
 	if len(errors) > 0 {
 		return fmt.Errorf("missing required environment variables: %v", errors)
 	}
@@ -128,3 +316,8 @@ func (c *Config) GetDSNString() string {
 	dsn := fmt.Sprintf("host=%v user=%v password=%v dbname=%v port=%v sslmode=disable", host, user, password, dbName, port)
 	return dsn
 }
+
+// AdminEnabled reports whether any admin authentication is configured.
+func (c *Config) AdminEnabled() bool {
+	return c.AdminAPIKey != "" || c.AdminUsername != ""
+}
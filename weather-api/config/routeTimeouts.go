@@ -0,0 +1,39 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// RouteTimeouts maps gin route paths to their request deadline. It is read
+// from a comma separated list such as "/api/weather=5s,/api/subscribe=15s".
+type RouteTimeouts map[string]time.Duration
+
+// Decode implements envconfig.Decoder.
+func (r *RouteTimeouts) Decode(value string) error {
+	timeouts := RouteTimeouts{}
+
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		path, duration, ok := strings.Cut(pair, "=")
+		if !ok {
+			return fmt.Errorf("route timeout %q is not path=duration", pair)
+		}
+
+		timeout, err := time.ParseDuration(strings.TrimSpace(duration))
+		if err != nil {
+			return fmt.Errorf("route timeout %q: %w", pair, err)
+		}
+
+		timeouts[strings.TrimSpace(path)] = timeout
+	}
+
+	*r = timeouts
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package apiversion
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Version int
+
+const (
+	V1 Version = 1
+	V2 Version = 2
+)
+
+const contextKey = "api_version"
+
+// Middleware tags requests of a route group with the API version, so
+// controllers can pick the response shape.
+func Middleware(version Version) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(contextKey, version)
+		c.Next()
+	}
+}
+
+// From returns the version of the request, V1 when it wasn't tagged.
+func From(c *gin.Context) Version {
+	if version, ok := c.Get(contextKey); ok {
+		return version.(Version)
+	}
+	return V1
+}
+
+// Deprecate announces that a version is deprecated since deprecatedAt
+// (RFC 9745) and goes away at sunset (RFC 8594), pointing at its successor.
+func Deprecate(deprecatedAt time.Time, sunset time.Time, successor string) gin.HandlerFunc {
+	deprecation := "@" + strconv.FormatInt(deprecatedAt.Unix(), 10)
+	sunsetDate := sunset.UTC().Format(http.TimeFormat)
+	link := "<" + successor + `>; rel="successor-version"`
+
+	return func(c *gin.Context) {
+		c.Header("Deprecation", deprecation)
+		c.Header("Sunset", sunsetDate)
+		c.Header("Link", link)
+		c.Next()
+	}
+}
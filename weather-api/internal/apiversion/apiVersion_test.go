@@ -0,0 +1,49 @@
+//go:build unit
+// +build unit
+
+package apiversion
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFrom(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var seen []Version
+	record := func(c *gin.Context) { seen = append(seen, From(c)) }
+
+	router := gin.New()
+	router.GET("/untagged", record)
+	router.GET("/v2", Middleware(V2), record)
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/untagged", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/v2", nil))
+
+	assert.Equal(t, []Version{V1, V2}, seen)
+}
+
+func TestDeprecate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	deprecatedAt := time.Date(2026, time.November, 1, 0, 0, 0, 0, time.UTC)
+	sunset := deprecatedAt.AddDate(0, 6, 0)
+
+	router := gin.New()
+	router.GET("/api/v1/weather", Deprecate(deprecatedAt, sunset, "/api/v2"), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/weather", nil))
+
+	assert.Equal(t, "@1793491200", w.Header().Get("Deprecation"))
+	assert.Equal(t, "Sat, 01 May 2027 00:00:00 GMT", w.Header().Get("Sunset"))
+	assert.Equal(t, `</api/v2>; rel="successor-version"`, w.Header().Get("Link"))
+}
@@ -4,23 +4,44 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
 	"strconv"
-
+	"syscall"
+	"time"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/health"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/lifecycle"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/logger"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/rabbitmq"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/tracing"
 	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/config"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/apiversion"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/cache"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/captcha"
 	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/client"
 	openweather "github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/client/openWeather"
 	weatherapi "github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/client/weatherApi"
 	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/db"
+	emailvalidation "github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/emailValidation"
 	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/httpclient"
 	metricP "github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/metrics"
-	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/rabbitmq"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/middleware"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/openapi"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/problem"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/ratelimit"
 	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/repository"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/requestid"
 	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/routes"
 	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/scheduler"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/service/admin"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/service/apikey"
 	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/service/subscription"
 	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/service/weather"
-	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/logger"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 
 	redisProvider "github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/redis"
 
@@ -28,8 +49,15 @@ import (
 	"gorm.io/gorm"
 )
 
+const (
+	serviceName = "weather-api"
+
+	apiKeyCacheEntries = 1000
+)
+
 func Run() error {
-	var ctx = context.Background()
+	ctx, stopSignals := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stopSignals()
 
 	logger, err := logger.NewLogger()
 	if err != nil {
@@ -50,66 +78,132 @@ func Run() error {
 		return err
 	}
 
-	db, err := db.ConnectToDatabase(*config, *logger)
+	// a warmed entry has to outlive the lead to still be cached at dispatch
+	if config.CacheWarmUpLead >= redisProvider.WeatherTTL {
+		return fmt.Errorf("CACHE_WARMUP_LEAD %s must be shorter than the weather cache TTL %s",
+			config.CacheWarmUpLead, redisProvider.WeatherTTL)
+	}
 
+	// Steps are stopped in reverse: the HTTP server, the scheduler and the
+	// consumers first, then Redis, the database, RabbitMQ and tracing.
+	shutdown := lifecycle.New(*logger)
+	defer func() {
+		if err := shutdown.Stop(config.ShutdownTimeout); err != nil {
+			logger.Error("Shutdown finished with errors", "error", err)
+			return
+		}
+		logger.Info("Shutdown complete")
+	}()
+
+	shutdownTracing, err := tracing.Setup(ctx, serviceName, config.TracingExporter, config.TracingSampleRatio)
 	if err != nil {
 		return err
 	}
+	shutdown.OnStop("tracing", shutdownTracing)
 
-	sqlDB, err := db.DB()
+	rabbit, err := rabbitmq.ConnectToRabbitMQ(config.RabbitMQUrl, *logger)
 	if err != nil {
-		logger.Error("Failed to get sql.DB from gorm.DB", "error", err)
 		return err
 	}
+	shutdown.OnStop("rabbitmq", lifecycle.Closer(rabbit.Close))
 
-	defer func() {
-		if err := sqlDB.Close(); err != nil {
-			logger.Error("Failed to close database connection", "error", err)
+	if err := declareQueues(rabbit); err != nil {
+		return err
+	}
 
-		}
-	}()
+	db, err := db.ConnectToDatabase(*config, *logger)
 
-	redis, err := redisProvider.ConnectToRedis(ctx, *config, *logger)
+	if err != nil {
+		return err
+	}
 
+	sqlDB, err := db.DB()
 	if err != nil {
+		logger.Error("Failed to get sql.DB from gorm.DB", "error", err)
 		return err
 	}
+	shutdown.OnStop("database", lifecycle.Closer(sqlDB.Close))
 
-	defer func() {
-		if err := redis.Close(); err != nil {
-			logger.Error("Failed to close Redis connection", "error", err)
-		}
-	}()
+	// Redis is only a cache, so a missing Redis degrades to an in-memory
+	// cache instead of stopping startup.
+	redis := redisProvider.ConnectWithFallback(ctx, *config, *logger)
+	go redis.Monitor()
+	shutdown.OnStop("redis", lifecycle.Closer(redis.Close))
 
-	rabbit, err := rabbitmq.ConnectToRabbitMQ(config.RabbitMQUrl, *logger)
+	emailPublisher := rabbitmq.NewRabbitMQPublisher(rabbit.Channel)
+
+	spec, err := openapi.Load()
 	if err != nil {
 		return err
 	}
-	defer rabbit.Conn.Close()
-	defer rabbit.Channel.Close()
 
-	if err := declareQueues(rabbit); err != nil {
+	router, err := setupRouter(*config, spec, *logger)
+	if err != nil {
 		return err
 	}
 
-	emailPublisher := rabbitmq.NewRabbitMQPublisher(rabbit.Channel)
+	redisPrv := redisProvider.NewRedisProvider(redis, *logger)
+
+	// weather is cached in process too; replicas drop keys another one rewrote
+	l1 := cache.NewLocal(config.L1CacheEntries, config.L1CacheTTL)
+	invalidator := cache.NewInvalidator(redis, l1, *logger)
+	go invalidator.Listen()
+	shutdown.OnStop("cache invalidation", lifecycle.Closer(invalidator.Close))
+
+	weatherCache := cache.NewTiered(l1, &redisPrv, invalidator, *logger)
+
+	services := initServices(*config, db, redisPrv, weatherCache, emailPublisher, *logger)
 
-	router := setupRouter()
+	disposableList, err := emailvalidation.NewDisposableList(config.DisposableDomainsFile)
+	if err != nil {
+		return fmt.Errorf("failed to load disposable domains: %w", err)
+	}
+	go reloadOnHangup(disposableList, *logger)
+
+	services.emailValidator = buildEmailValidator(*config, disposableList, *logger)
 
-	redisPrv := redisProvider.NewRedisProvider(redis, ctx, *logger)
+	initRoutes(router, *config, services, spec, subscribeProtection(*config, &redisPrv, *logger), *logger)
 
-	services := initServices(*config, db, redisPrv, emailPublisher, *logger)
+	rabbitmqConsumer := rabbitmq.NewRabbitMQConsumer(rabbit.Channel, *logger)
+	services.subscribeService.StartSuppressionWorker(rabbitmqConsumer)
+	shutdown.OnStop("rabbitmq consumers", rabbitmqConsumer.Shutdown)
+
+	checker := health.NewChecker(config.ReadinessCheckTimeout, *logger)
+	checker.Add("postgres", sqlDB.PingContext)
+	checker.AddOptional("redis", redisPrv.Ping)
+	checker.AddInfo("cache_backend", redis.Backend)
+	checker.Add("rabbitmq", rabbit.Check)
+	checker.Add("rabbitmq_consumers", rabbitmqConsumer.Check)
+	if config.ReadinessUpstreamCity != "" {
+		checker.Add("weather_provider", health.Cached(
+			upstreamCheck(services.weatherChain, config.ReadinessUpstreamCity), config.ReadinessUpstreamTTL))
+	}
+	routes.HealthRoute(router, health.NewHealthController(checker))
 
-	initRoutes(router, services)
-	startBackgroundJobs(*services.subscribeService, *logger)
+	scheduler := startBackgroundJobs(services)
+	shutdown.OnStop("scheduler", scheduler.Stop)
 
-	return startServer(*config, router)
+	server := &http.Server{
+		Addr:    ":" + strconv.Itoa(config.AppPort),
+		Handler: router,
+	}
+	shutdown.OnStop("http server", server.Shutdown)
+
+	return lifecycle.Serve(ctx, server, *logger)
 }
 
-func setupRouter() *gin.Engine {
+func setupRouter(config config.Config, spec *openapi.Spec, logger logger.Logger) (*gin.Engine, error) {
 	router := gin.Default()
 
+	// rate limits and quotas are per client IP, which mustn't be spoofable
+	if err := router.SetTrustedProxies(config.TrustedProxies); err != nil {
+		return nil, fmt.Errorf("invalid TRUSTED_PROXIES: %w", err)
+	}
+
+	router.Use(otelgin.Middleware(serviceName))
 	router.Use(metricP.MetricsMiddleware())
+	router.Use(requestid.Middleware(), problem.Handler(logger))
+	router.Use(middleware.Timeout(config.RequestTimeout, config.RouteTimeouts))
 
 	router.Static("/static", "./static")
 	router.GET("/", func(c *gin.Context) {
@@ -117,49 +211,161 @@ func setupRouter() *gin.Engine {
 	})
 
 	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
+	router.GET("/openapi.json", spec.Serve)
 
-	return router
+	return router, nil
 }
 
-func initRoutes(router *gin.Engine, services *Services) {
-	api := router.Group("/api")
+// initRoutes registers the API routes, which are validated against spec.
+// /api is kept as an alias of the deprecated /api/v1.
+func initRoutes(router *gin.Engine, config config.Config, services *Services, spec *openapi.Spec,
+	subscribeMiddleware []gin.HandlerFunc, logger logger.Logger) {
 
 	weatherController := weather.NewWeatherController(services.weatherService)
-	routes.WeatherRoute(api, weatherController)
+	apiKeyController := apikey.NewAPIKeyController(services.apiKeyService)
+	subscribeController := subscription.NewSubscribeController(services.subscribeService, services.emailValidator)
+	quota := middleware.RequireQuota(services.apiKeyService)
+
+	deprecateV1 := apiversion.Deprecate(config.APIV1DeprecatedAt, config.APIV1Sunset, "/api/v2")
+
+	versions := []*gin.RouterGroup{
+		router.Group("/api", apiversion.Middleware(apiversion.V1), deprecateV1, spec.Validate()),
+		router.Group("/api/v1", apiversion.Middleware(apiversion.V1), deprecateV1, spec.Validate()),
+		router.Group("/api/v2", apiversion.Middleware(apiversion.V2), spec.Validate()),
+	}
+
+	for _, api := range versions {
+		routes.WeatherRoute(api, weatherController, quota)
+		routes.UsageRoute(api, apiKeyController)
+		routes.SubscribeRoute(api, subscribeController, subscribeMiddleware...)
+	}
+
+	if !config.AdminEnabled() {
+		logger.Info("ADMIN_API_KEY and ADMIN_USERNAME are not set, admin API disabled")
+		return
+	}
 
-	subscribeController := subscription.NewSubscribeController(services.subscribeService)
-	routes.SubscribeRoute(api, subscribeController)
+	adminGroup := router.Group("/admin",
+		middleware.RequireAdmin(config.AdminAPIKey, config.AdminUsername, config.AdminPassword),
+		spec.Validate())
+	routes.AdminRoute(adminGroup, admin.NewAdminController(services.adminService))
+	routes.APIKeyRoute(adminGroup, apiKeyController)
 }
 
-func startBackgroundJobs(subscribeService subscription.SubscribeService, logger logger.Logger) {
-	schedulerService := scheduler.NewScheduler(&subscribeService, logger)
-	schedulerService.StartCronJobs()
+// subscribeProtection limits POST /api/subscribe per client IP and per
+// target email, with a captcha check in between when one is configured.
+func subscribeProtection(config config.Config, redisPrv *redisProvider.RedisProvider,
+	logger logger.Logger) []gin.HandlerFunc {
+
+	ipLimiter := ratelimit.NewSlidingWindow("subscribe:ip", config.SubscribeIPLimit,
+		config.SubscribeIPWindow, redisPrv, logger)
+	emailLimiter := ratelimit.NewSlidingWindow("subscribe:email", config.SubscribeEmailLimit,
+		config.SubscribeEmailWindow, redisPrv, logger)
+
+	handlers := []gin.HandlerFunc{middleware.RateLimit(ipLimiter, middleware.ByClientIP)}
+
+	if config.CaptchaSecret != "" {
+		http := httpclient.InitHttpClient()
+		verifier := captcha.NewSiteVerifier(config.CaptchaVerifyURL, config.CaptchaSecret, &http, logger)
+		handlers = append(handlers, middleware.RequireChallenge(verifier))
+	}
+
+	return append(handlers, middleware.RateLimit(emailLimiter, middleware.ByJSONField("email")))
 }
 
-func startServer(config config.Config, router *gin.Engine) error {
-	port := strconv.Itoa(config.AppPort)
+// upstreamCheck looks up a city from the providers, skipping the cache, to
+// tell whether any of them answers.
+func upstreamCheck(chain *client.WeatherChain, city string) health.CheckFunc {
+	return func(ctx context.Context) error {
+		_, err := chain.GetWeather(ctx, city)
+		return err
+	}
+}
 
-	return router.Run(":" + port)
+func buildEmailValidator(config config.Config, disposableList *emailvalidation.DisposableList,
+	logger logger.Logger) *emailvalidation.Validator {
+
+	if !config.EmailMXCheck {
+		return emailvalidation.NewValidator(disposableList)
+	}
+
+	mxChecker := emailvalidation.NewMXChecker(net.DefaultResolver, config.EmailMXCacheTTL, logger)
+
+	return emailvalidation.NewValidator(disposableList, mxChecker)
+}
+
+// reloadOnHangup re-reads the disposable domains file on SIGHUP.
+func reloadOnHangup(list *emailvalidation.DisposableList, logger logger.Logger) {
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+
+	for range hangup {
+		if err := list.Reload(); err != nil {
+			logger.Error("Failed to reload disposable domains", "error", err)
+			continue
+		}
+		logger.Info("Reloaded disposable domains", "count", list.Len())
+	}
+}
+
+func startBackgroundJobs(services *Services) *scheduler.Scheduler {
+	services.scheduler.StartCronJobs()
+
+	return services.scheduler
 }
 
 func initServices(config config.Config, database *gorm.DB,
-	redisPrv redisProvider.RedisProvider, emailPublisher *rabbitmq.RabbitMQPublisher,
+	redisPrv redisProvider.RedisProvider, weatherCache *cache.Tiered, emailPublisher *rabbitmq.RabbitMQPublisher,
 	logger logger.Logger) *Services {
 
 	weatherApiChain := buildWeatherResponsibilityChain(config, logger)
 
-	weatherService := weather.NewWeatherAPIService(weatherApiChain, &redisPrv, logger)
+	weatherService := weather.NewWeatherAPIService(weatherApiChain, weatherCache, logger)
 
 	subscribeRepo := repository.NewSubscriptionRepository(database)
 
-	subscribeService := subscription.NewSubscribeService(weatherService, subscribeRepo, emailPublisher, logger)
+	thresholds := subscription.ChangeThresholds{
+		Temperature:     config.ChangeTemperatureThreshold,
+		Humidity:        config.ChangeHumidityThreshold,
+		IgnoreCondition: config.ChangeIgnoreCondition,
+	}
+
+	subscribeService := subscription.NewSubscribeService(weatherService, subscribeRepo, emailPublisher,
+		thresholds, logger)
+
+	cacheWarmer := subscription.NewCacheWarmer(subscribeService, weatherService,
+		config.CacheWarmUpConcurrency, logger)
+
+	schedulerService := scheduler.NewScheduler(subscribeService, cacheWarmer, config.CacheWarmUpLead, logger)
+
+	adminService := admin.NewAdminService(subscribeRepo, subscribeService, schedulerService, logger)
+
+	apiKeyService := apikey.NewAPIKeyService(repository.NewAPIKeyRepository(database), &redisPrv,
+		newAPIKeyCache(config.APIKeyCacheTTL),
+		apikey.Quota{Daily: config.AnonDailyQuota, Monthly: config.AnonMonthlyQuota},
+		apikey.Quota{Daily: config.APIKeyDailyQuota, Monthly: config.APIKeyMonthlyQuota},
+		config.APIKeyRequired, logger)
 
 	return &Services{
+		weatherChain:     weatherApiChain,
 		weatherService:   weatherService,
 		subscribeService: subscribeService,
+		adminService:     adminService,
+		scheduler:        schedulerService,
+		apiKeyService:    apiKeyService,
 	}
 }
 
+// newAPIKeyCache keeps up to apiKeyCacheEntries keys for ttl; a negative
+// ttl disables it.
+func newAPIKeyCache(ttl time.Duration) *cache.Local {
+	if ttl < 0 {
+		return cache.NewLocal(-1, 0)
+	}
+
+	return cache.NewLocal(apiKeyCacheEntries, ttl)
+}
+
 func buildWeatherResponsibilityChain(config config.Config, logger logger.Logger) *client.WeatherChain {
 	http := httpclient.InitHttpClient()
 
@@ -179,14 +385,21 @@ func buildWeatherResponsibilityChain(config config.Config, logger logger.Logger)
 }
 
 type Services struct {
+	weatherChain     *client.WeatherChain
 	weatherService   *weather.WeatherService
 	subscribeService *subscription.SubscribeService
+	adminService     *admin.AdminService
+	scheduler        *scheduler.Scheduler
+	apiKeyService    *apikey.APIKeyService
+	emailValidator   *emailvalidation.Validator
 }
 
 func declareQueues(r *rabbitmq.RabbitMQ) error {
 	queues := []string{
 		rabbitmq.SendEmail,
 		rabbitmq.WeatherUpdate,
+		rabbitmq.WeatherDigest,
+		rabbitmq.SubscriptionSuppressed,
 	}
 
 	for _, q := range queues {
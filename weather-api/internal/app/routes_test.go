@@ -0,0 +1,69 @@
+//go:build unit
+// +build unit
+
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/logger"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/config"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/openapi"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRoutesMatchOpenAPISpec fails when a route is registered without being
+// documented in openapi.yaml, or the spec documents a route that is gone.
+func TestRoutesMatchOpenAPISpec(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockLog, _ := logger.NewTestLogger()
+
+	spec, err := openapi.Load()
+	require.NoError(t, err)
+
+	router := gin.New()
+	initRoutes(router, config.Config{AdminAPIKey: "admin-key"}, &Services{}, spec, nil, *mockLog)
+
+	registered := map[string]bool{}
+	for _, route := range router.Routes() {
+		registered[route.Method+" "+route.Path] = true
+		assert.Truef(t, spec.Documents(route.Method, route.Path),
+			"%s %s is not documented in openapi.yaml", route.Method, route.Path)
+	}
+
+	for _, operation := range spec.Operations() {
+		assert.Truef(t, registered[operation], "%s is documented but not registered", operation)
+	}
+}
+
+func TestSetupRouter_TrustsNoProxyByDefault(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockLog, _ := logger.NewTestLogger()
+
+	spec, err := openapi.Load()
+	require.NoError(t, err)
+
+	clientIP := func(trusted []string) string {
+		router, err := setupRouter(config.Config{TrustedProxies: trusted}, spec, *mockLog)
+		require.NoError(t, err)
+		router.GET("/ip", func(c *gin.Context) { c.String(http.StatusOK, c.ClientIP()) })
+
+		req := httptest.NewRequest(http.MethodGet, "/ip", nil)
+		req.RemoteAddr = "10.0.0.2:40000"
+		req.Header.Set("X-Forwarded-For", "203.0.113.7")
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Body.String()
+	}
+
+	assert.Equal(t, "10.0.0.2", clientIP(nil))
+	assert.Equal(t, "203.0.113.7", clientIP([]string{"10.0.0.0/8"}))
+
+	_, err = setupRouter(config.Config{TrustedProxies: []string{"not-an-ip"}}, spec, *mockLog)
+	assert.Error(t, err)
+}
@@ -0,0 +1,100 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/logger"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const InvalidationChannel = "cache:invalidate"
+
+type pubSubClient interface {
+	Publish(ctx context.Context, channel string, message interface{}) *redis.IntCmd
+	Subscribe(ctx context.Context, channels ...string) *redis.PubSub
+}
+
+type invalidation struct {
+	Key    string `json:"key"`
+	Origin string `json:"origin"`
+}
+
+// Invalidator tells the other replicas over Redis pub/sub which keys were
+// rewritten, so they drop them from their local cache. Messages missed
+// while disconnected are bounded by the local TTL.
+type Invalidator struct {
+	client pubSubClient
+	local  *Local
+	origin string
+	logger logger.Logger
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func NewInvalidator(client pubSubClient, local *Local, logger logger.Logger) *Invalidator {
+	return &Invalidator{
+		client: client,
+		local:  local,
+		origin: uuid.NewString(),
+		logger: logger,
+		done:   make(chan struct{}),
+	}
+}
+
+func (i *Invalidator) Publish(ctx context.Context, key string) {
+	payload, err := json.Marshal(invalidation{Key: key, Origin: i.origin})
+	if err != nil {
+		i.logger.WithContext(ctx).Error("Failed to marshal cache invalidation", "key", key, "error", err)
+		return
+	}
+
+	if err := i.client.Publish(ctx, InvalidationChannel, payload).Err(); err != nil {
+		i.logger.WithContext(ctx).Error("Failed to publish cache invalidation", "key", key, "error", err)
+	}
+}
+
+// Listen drops the keys other replicas invalidate until Close. The
+// subscription reconnects by itself when Redis comes back.
+func (i *Invalidator) Listen() {
+	sub := i.client.Subscribe(context.Background(), InvalidationChannel)
+	defer sub.Close()
+
+	messages := sub.Channel()
+	for {
+		select {
+		case <-i.done:
+			return
+		case msg, ok := <-messages:
+			if !ok {
+				return
+			}
+			i.handle(msg.Payload)
+		}
+	}
+}
+
+func (i *Invalidator) Close() error {
+	i.closeOnce.Do(func() {
+		close(i.done)
+	})
+	return nil
+}
+
+func (i *Invalidator) handle(payload string) {
+	var message invalidation
+	if err := json.Unmarshal([]byte(payload), &message); err != nil {
+		i.logger.Error("Failed to unmarshal cache invalidation", "error", err)
+		return
+	}
+
+	// our own writes already updated the local cache
+	if message.Origin == i.origin {
+		return
+	}
+
+	i.local.Delete(message.Key)
+}
@@ -0,0 +1,104 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+type localEntry struct {
+	key       string
+	value     any
+	expiresAt time.Time
+}
+
+// Local is a bounded in-process LRU whose entries live at most ttl. A
+// negative capacity disables it.
+type Local struct {
+	mu       sync.Mutex
+	capacity int
+	ttl      time.Duration
+	items    map[string]*list.Element
+	// most recently used first
+	order *list.List
+	now   func() time.Time
+}
+
+func NewLocal(capacity int, ttl time.Duration) *Local {
+	return &Local{
+		capacity: capacity,
+		ttl:      ttl,
+		items:    make(map[string]*list.Element),
+		order:    list.New(),
+		now:      time.Now,
+	}
+}
+
+func (l *Local) Get(key string) (any, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	element, ok := l.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := element.Value.(*localEntry)
+	if !l.now().Before(entry.expiresAt) {
+		l.remove(element)
+		return nil, false
+	}
+
+	l.order.MoveToFront(element)
+
+	return entry.value, true
+}
+
+// Set stores value for the local TTL, or for ttl when that is shorter.
+func (l *Local) Set(key string, value any, ttl time.Duration) {
+	if l.capacity < 0 {
+		return
+	}
+
+	if ttl <= 0 || ttl > l.ttl {
+		ttl = l.ttl
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entry := &localEntry{key: key, value: value, expiresAt: l.now().Add(ttl)}
+
+	if element, ok := l.items[key]; ok {
+		element.Value = entry
+		l.order.MoveToFront(element)
+		return
+	}
+
+	l.items[key] = l.order.PushFront(entry)
+
+	for l.order.Len() > l.capacity {
+		l.remove(l.order.Back())
+	}
+}
+
+func (l *Local) Delete(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if element, ok := l.items[key]; ok {
+		l.remove(element)
+	}
+}
+
+func (l *Local) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.order.Len()
+}
+
+func (l *Local) remove(element *list.Element) {
+	l.order.Remove(element)
+	delete(l.items, element.Value.(*localEntry).key)
+}
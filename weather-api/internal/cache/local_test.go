@@ -0,0 +1,65 @@
+//go:build unit
+// +build unit
+
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time { return c.now }
+
+func newTestLocal(capacity int, ttl time.Duration) (*Local, *clock) {
+	c := &clock{now: time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)}
+	l := NewLocal(capacity, ttl)
+	l.now = c.Now
+	return l, c
+}
+
+func TestLocal_ExpiresAfterShorterTTL(t *testing.T) {
+	l, c := newTestLocal(10, 30*time.Second)
+
+	l.Set("weather:Kyiv", 1, time.Hour)
+	l.Set("weather:Lviv", 2, 10*time.Second)
+
+	c.now = c.now.Add(10 * time.Second)
+	_, ok := l.Get("weather:Lviv")
+	assert.False(t, ok, "entries don't outlive the TTL they were set with")
+
+	value, ok := l.Get("weather:Kyiv")
+	assert.True(t, ok)
+	assert.Equal(t, 1, value)
+
+	c.now = c.now.Add(20 * time.Second)
+	_, ok = l.Get("weather:Kyiv")
+	assert.False(t, ok, "entries don't outlive the local TTL")
+}
+
+func TestLocal_EvictsLeastRecentlyUsed(t *testing.T) {
+	l, _ := newTestLocal(2, time.Minute)
+
+	l.Set("a", 1, 0)
+	l.Set("b", 2, 0)
+	l.Get("a")
+	l.Set("c", 3, 0)
+
+	_, ok := l.Get("b")
+	assert.False(t, ok)
+	assert.Equal(t, 2, l.Len())
+}
+
+func TestLocal_NegativeCapacityDisables(t *testing.T) {
+	l, _ := newTestLocal(-1, time.Minute)
+
+	l.Set("a", 1, 0)
+
+	_, ok := l.Get("a")
+	assert.False(t, ok)
+}
@@ -0,0 +1,87 @@
+package cache
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/logger"
+	metricP "github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/metrics"
+)
+
+const (
+	TierLocal = "l1"
+	TierRedis = "l2"
+)
+
+type remoteCache interface {
+	SetWithTTL(ctx context.Context, key string, value interface{}, ttl time.Duration) error
+	Get(ctx context.Context, key string, dest interface{}) error
+}
+
+type invalidator interface {
+	Publish(ctx context.Context, key string)
+}
+
+// Tiered serves reads from the local cache and falls back to Redis, so
+// popular keys skip the round trip and the JSON decoding. It has the same
+// Get and SetWithTTL as RedisProvider.
+type Tiered struct {
+	local       *Local
+	remote      remoteCache
+	invalidator invalidator
+	logger      logger.Logger
+}
+
+func NewTiered(local *Local, remote remoteCache, invalidator invalidator, logger logger.Logger) *Tiered {
+	return &Tiered{local: local, remote: remote, invalidator: invalidator, logger: logger}
+}
+
+// Get fills dest, a pointer, from the first tier holding key. Misses return
+// the Redis error, "redis: nil" when no tier has it.
+func (t *Tiered) Get(ctx context.Context, key string, dest interface{}) error {
+	if value, ok := t.local.Get(key); ok && assign(dest, value) {
+		metricP.CacheLookup(TierLocal, true)
+		return nil
+	}
+	metricP.CacheLookup(TierLocal, false)
+
+	if err := t.remote.Get(ctx, key, dest); err != nil {
+		metricP.CacheLookup(TierRedis, false)
+		return err
+	}
+	metricP.CacheLookup(TierRedis, true)
+
+	t.local.Set(key, reflect.ValueOf(dest).Elem().Interface(), 0)
+
+	return nil
+}
+
+// SetWithTTL writes both tiers and then has the other replicas drop their
+// local copy of key, so they reload the new value from Redis.
+func (t *Tiered) SetWithTTL(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	err := t.remote.SetWithTTL(ctx, key, value, ttl)
+
+	t.local.Set(key, reflect.Indirect(reflect.ValueOf(value)).Interface(), ttl)
+	t.invalidator.Publish(ctx, key)
+
+	return err
+}
+
+// assign copies value into the pointer dest when their types match. The
+// copy is shallow, so slices in cached values must not be modified.
+func assign(dest interface{}, value any) bool {
+	target := reflect.ValueOf(dest)
+	if target.Kind() != reflect.Pointer || target.IsNil() {
+		return false
+	}
+
+	source := reflect.ValueOf(value)
+	if source.Type() != target.Elem().Type() {
+		return false
+	}
+
+	target.Elem().Set(source)
+
+	return true
+}
@@ -0,0 +1,104 @@
+//go:build unit
+// +build unit
+
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/logger"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/client"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeRemote stores JSON like RedisProvider and counts reads.
+type fakeRemote struct {
+	data  map[string][]byte
+	reads int
+}
+
+func (f *fakeRemote) SetWithTTL(_ context.Context, key string, value interface{}, ttl time.Duration) error {
+	data, err := json.Marshal(value)
+	f.data[key] = data
+	return err
+}
+
+func (f *fakeRemote) Get(_ context.Context, key string, dest interface{}) error {
+	f.reads++
+	data, ok := f.data[key]
+	if !ok {
+		return errors.New("redis: nil")
+	}
+	return json.Unmarshal(data, dest)
+}
+
+type recordingInvalidator struct {
+	keys []string
+}
+
+func (r *recordingInvalidator) Publish(_ context.Context, key string) {
+	r.keys = append(r.keys, key)
+}
+
+func TestTiered_ReadsThroughAndServesFromLocal(t *testing.T) {
+	mockLog, _ := logger.NewTestLogger()
+	remote := &fakeRemote{data: map[string][]byte{
+		"weather:Kyiv": []byte(`{"temperature":21.5,"humidity":55,"description":"Sunny"}`),
+	}}
+	tiered := NewTiered(NewLocal(10, time.Minute), remote, &recordingInvalidator{}, *mockLog)
+
+	for i := 0; i < 3; i++ {
+		var weather client.WeatherDTO
+		assert.NoError(t, tiered.Get(context.Background(), "weather:Kyiv", &weather))
+		assert.Equal(t, client.WeatherDTO{Temperature: 21.5, Humidity: 55, Description: "Sunny"}, weather)
+	}
+
+	assert.Equal(t, 1, remote.reads)
+}
+
+func TestTiered_MissReturnsRedisNil(t *testing.T) {
+	mockLog, _ := logger.NewTestLogger()
+	tiered := NewTiered(NewLocal(10, time.Minute), &fakeRemote{data: map[string][]byte{}},
+		&recordingInvalidator{}, *mockLog)
+
+	var weather client.WeatherDTO
+	assert.EqualError(t, tiered.Get(context.Background(), "weather:Kyiv", &weather), "redis: nil")
+}
+
+func TestTiered_SetWritesBothTiersAndInvalidates(t *testing.T) {
+	mockLog, _ := logger.NewTestLogger()
+	remote := &fakeRemote{data: map[string][]byte{}}
+	invalidator := &recordingInvalidator{}
+	tiered := NewTiered(NewLocal(10, time.Minute), remote, invalidator, *mockLog)
+
+	err := tiered.SetWithTTL(context.Background(), "weather:Kyiv", &client.WeatherDTO{Temperature: 18}, time.Minute)
+	assert.NoError(t, err)
+
+	var weather client.WeatherDTO
+	assert.NoError(t, tiered.Get(context.Background(), "weather:Kyiv", &weather))
+	assert.Equal(t, 18.0, weather.Temperature)
+	assert.Equal(t, 0, remote.reads)
+	assert.Contains(t, string(remote.data["weather:Kyiv"]), `"temperature":18`)
+	assert.Equal(t, []string{"weather:Kyiv"}, invalidator.keys)
+}
+
+func TestInvalidator_DropsKeysFromOtherReplicas(t *testing.T) {
+	mockLog, _ := logger.NewTestLogger()
+	local := NewLocal(10, time.Minute)
+	invalidator := NewInvalidator(nil, local, *mockLog)
+
+	local.Set("weather:Kyiv", 1, 0)
+	local.Set("weather:Lviv", 2, 0)
+
+	invalidator.handle(`{"key":"weather:Kyiv","origin":"` + invalidator.origin + `"}`)
+	invalidator.handle(`{"key":"weather:Lviv","origin":"other-replica"}`)
+
+	_, ok := local.Get("weather:Kyiv")
+	assert.True(t, ok, "own writes are not dropped")
+	_, ok = local.Get("weather:Lviv")
+	assert.False(t, ok)
+}
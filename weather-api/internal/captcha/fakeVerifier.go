@@ -0,0 +1,13 @@
+package captcha
+
+import "context"
+
+// FakeVerifier accepts a single fixed token. It stands in for a real
+// provider in tests and local setups.
+type FakeVerifier struct {
+	Token string
+}
+
+func (v FakeVerifier) Verify(_ context.Context, token string, remoteIP string) (bool, error) {
+	return token != "" && token == v.Token, nil
+}
@@ -0,0 +1,76 @@
+package captcha
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/logger"
+)
+
+// SiteVerifier checks tokens against a siteverify endpoint. reCAPTCHA,
+// hCaptcha and Turnstile all share this protocol.
+type SiteVerifier struct {
+	verifyURL string
+	secret    string
+	client    *http.Client
+	logger    logger.Logger
+}
+
+func NewSiteVerifier(verifyURL string, secret string, client *http.Client, logger logger.Logger) *SiteVerifier {
+	return &SiteVerifier{
+		verifyURL: verifyURL,
+		secret:    secret,
+		client:    client,
+		logger:    logger,
+	}
+}
+
+type siteVerifyResponse struct {
+	Success    bool     `json:"success"`
+	ErrorCodes []string `json:"error-codes"`
+}
+
+func (v *SiteVerifier) Verify(ctx context.Context, token string, remoteIP string) (bool, error) {
+	if token == "" {
+		return false, nil
+	}
+
+	form := url.Values{
+		"secret":   {v.secret},
+		"response": {token},
+		"remoteip": {remoteIP},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.verifyURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		v.logger.WithContext(ctx).Error("Captcha verification request failed", "error", err)
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("captcha verification returned status %d", resp.StatusCode)
+	}
+
+	var result siteVerifyResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		v.logger.WithContext(ctx).Error("Failed to parse captcha verification response", "error", err)
+		return false, err
+	}
+
+	if !result.Success {
+		v.logger.WithContext(ctx).Info("Captcha token rejected", "errors", result.ErrorCodes)
+	}
+
+	return result.Success, nil
+}
@@ -0,0 +1,97 @@
+//go:build unit
+// +build unit
+
+package captcha
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+type MockRoundTripper struct {
+	resp    *http.Response
+	err     error
+	request *http.Request
+}
+
+func (m *MockRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	m.request = req
+	return m.resp, m.err
+}
+
+func newVerifier(respBody string, statusCode int, err error) (*SiteVerifier, *MockRoundTripper) {
+	transport := &MockRoundTripper{
+		resp: &http.Response{
+			StatusCode: statusCode,
+			Body:       io.NopCloser(bytes.NewBufferString(respBody)),
+			Header:     make(http.Header),
+		},
+		err: err,
+	}
+	mockLog, _ := logger.NewTestLogger()
+
+	return NewSiteVerifier("https://captcha.test/siteverify", "secret",
+		&http.Client{Transport: transport}, *mockLog), transport
+}
+
+func TestVerify_Success(t *testing.T) {
+	verifier, transport := newVerifier(`{"success": true}`, http.StatusOK, nil)
+
+	ok, err := verifier.Verify(context.Background(), "token", "1.2.3.4")
+
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.NoError(t, transport.request.ParseForm())
+	assert.Equal(t, "secret", transport.request.PostForm.Get("secret"))
+	assert.Equal(t, "token", transport.request.PostForm.Get("response"))
+	assert.Equal(t, "1.2.3.4", transport.request.PostForm.Get("remoteip"))
+}
+
+func TestVerify_Rejected(t *testing.T) {
+	verifier, _ := newVerifier(`{"success": false, "error-codes": ["invalid-input-response"]}`,
+		http.StatusOK, nil)
+
+	ok, err := verifier.Verify(context.Background(), "token", "1.2.3.4")
+
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestVerify_EmptyTokenIsNotSent(t *testing.T) {
+	verifier, transport := newVerifier(`{"success": true}`, http.StatusOK, nil)
+
+	ok, err := verifier.Verify(context.Background(), "", "1.2.3.4")
+
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.Nil(t, transport.request)
+}
+
+func TestVerify_ProviderError(t *testing.T) {
+	verifier, _ := newVerifier("", 0, errors.New("timeout"))
+
+	_, err := verifier.Verify(context.Background(), "token", "1.2.3.4")
+	assert.Error(t, err)
+
+	verifier, _ = newVerifier("", http.StatusInternalServerError, nil)
+
+	_, err = verifier.Verify(context.Background(), "token", "1.2.3.4")
+	assert.Error(t, err)
+}
+
+func TestFakeVerifier(t *testing.T) {
+	verifier := FakeVerifier{Token: "pass"}
+
+	ok, _ := verifier.Verify(context.Background(), "pass", "1.2.3.4")
+	assert.True(t, ok)
+
+	ok, _ = verifier.Verify(context.Background(), "", "1.2.3.4")
+	assert.False(t, ok)
+}
@@ -1,13 +1,19 @@
 package client
 
-import "github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/logger"
+import (
+	"context"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/logger"
+)
 
 type weatherProvider interface {
-	FetchWeather(city string) (*WeatherDTO, error)
+	FetchWeather(ctx context.Context, city string) (*WeatherDTO, error)
+	FetchForecast(ctx context.Context, city string) (*ForecastDTO, error)
 }
 
 type weatherChainProvider interface {
-	GetWeather(city string) (*WeatherDTO, error)
+	GetWeather(ctx context.Context, city string) (*WeatherDTO, error)
+	GetForecast(ctx context.Context, city string) (*ForecastDTO, error)
 	SetNext(next weatherChainProvider)
 }
 
@@ -28,19 +34,45 @@ func NewWeatherChain(provider weatherProvider, logger logger.Logger) *WeatherCha
 	}
 }
 
-func (c *WeatherChain) GetWeather(city string) (*WeatherDTO, error) {
-	weather, err := c.provider.FetchWeather(city)
+func (c *WeatherChain) GetWeather(ctx context.Context, city string) (*WeatherDTO, error) {
+	weather, err := c.provider.FetchWeather(ctx, city)
 	if err == nil {
 		return weather, nil
 	}
 
-	c.logger.Error("Weather provider error. Trying next provider... ", "city", city, "error", err)
+	// a cancelled request or shutdown would fail on the next provider too
+	if ctx.Err() != nil {
+		return nil, err
+	}
+
+	c.logger.WithContext(ctx).Error("Weather provider error. Trying next provider... ", "city", city, "error", err)
+
+	if c.next != nil {
+		return c.next.GetWeather(ctx, city)
+	}
+
+	c.logger.WithContext(ctx).Error("All weather providers failed", "city", city, "error", err)
+
+	return nil, err
+}
+
+func (c *WeatherChain) GetForecast(ctx context.Context, city string) (*ForecastDTO, error) {
+	forecast, err := c.provider.FetchForecast(ctx, city)
+	if err == nil {
+		return forecast, nil
+	}
+
+	if ctx.Err() != nil {
+		return nil, err
+	}
+
+	c.logger.WithContext(ctx).Error("Forecast provider error. Trying next provider... ", "city", city, "error", err)
 
 	if c.next != nil {
-		return c.next.GetWeather(city)
+		return c.next.GetForecast(ctx, city)
 	}
 
-	c.logger.Error("All weather providers failed", "city", city, "error", err)
+	c.logger.WithContext(ctx).Error("All forecast providers failed", "city", city, "error", err)
 
 	return nil, err
 }
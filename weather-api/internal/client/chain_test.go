@@ -4,10 +4,11 @@
 package client
 
 import (
+	"context"
 	"errors"
 	"testing"
 
-	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/logger"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/logger"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -16,8 +17,14 @@ type mockWeatherProvider struct {
 	mock.Mock
 }
 
-func (m *mockWeatherProvider) FetchWeather(city string) (*WeatherDTO, error) {
-	args := m.Called(city)
+func (m *mockWeatherProvider) FetchForecast(ctx context.Context, city string) (*ForecastDTO, error) {
+	args := m.Called(ctx, city)
+	dto, _ := args.Get(0).(*ForecastDTO)
+	return dto, args.Error(1)
+}
+
+func (m *mockWeatherProvider) FetchWeather(ctx context.Context, city string) (*WeatherDTO, error) {
+	args := m.Called(ctx, city)
 	dto, _ := args.Get(0).(*WeatherDTO)
 	return dto, args.Error(1)
 }
@@ -26,12 +33,12 @@ func TestWeatherChain_SuccessFirstProvider_Mock(t *testing.T) {
 
 	want := &WeatherDTO{Temperature: 25}
 	provider := new(mockWeatherProvider)
-	provider.On("FetchWeather", "Kyiv").Return(want, nil)
+	provider.On("FetchWeather", mock.Anything, "Kyiv").Return(want, nil)
 
 	mockLog, _ := logger.NewTestLogger()
 	chain := NewWeatherChain(provider, *mockLog)
 
-	got, err := chain.GetWeather("Kyiv")
+	got, err := chain.GetWeather(context.Background(), "Kyiv")
 	assert.NoError(t, err)
 	assert.Equal(t, want, got)
 	provider.AssertExpectations(t)
@@ -43,14 +50,14 @@ func TestWeatherChain_SecondProviderSuccess_Mock(t *testing.T) {
 	provider2 := new(mockWeatherProvider)
 	want := &WeatherDTO{Temperature: 18}
 
-	provider1.On("FetchWeather", "Lviv").Return(nil, errors.New("fail1"))
-	provider2.On("FetchWeather", "Lviv").Return(want, nil)
+	provider1.On("FetchWeather", mock.Anything, "Lviv").Return(nil, errors.New("fail1"))
+	provider2.On("FetchWeather", mock.Anything, "Lviv").Return(want, nil)
 
 	mockLog, _ := logger.NewTestLogger()
 	chain := NewWeatherChain(provider1, *mockLog)
 	chain.SetNext(NewWeatherChain(provider2, *mockLog))
 
-	got, err := chain.GetWeather("Lviv")
+	got, err := chain.GetWeather(context.Background(), "Lviv")
 	assert.NoError(t, err)
 	assert.Equal(t, want, got)
 	provider1.AssertExpectations(t)
@@ -62,14 +69,14 @@ func TestWeatherChain_AllProvidersFail_Mock(t *testing.T) {
 	provider1 := new(mockWeatherProvider)
 	provider2 := new(mockWeatherProvider)
 
-	provider1.On("FetchWeather", "Odesa").Return(nil, errors.New("fail1"))
-	provider2.On("FetchWeather", "Odesa").Return(nil, errors.New("fail2"))
+	provider1.On("FetchWeather", mock.Anything, "Odesa").Return(nil, errors.New("fail1"))
+	provider2.On("FetchWeather", mock.Anything, "Odesa").Return(nil, errors.New("fail2"))
 
 	mockLog, _ := logger.NewTestLogger()
 	chain := NewWeatherChain(provider1, *mockLog)
 	chain.SetNext(NewWeatherChain(provider2, *mockLog))
 
-	got, err := chain.GetWeather("Odesa")
+	got, err := chain.GetWeather(context.Background(), "Odesa")
 	assert.Nil(t, got)
 	assert.Error(t, err)
 
@@ -77,3 +84,40 @@ func TestWeatherChain_AllProvidersFail_Mock(t *testing.T) {
 	provider1.AssertExpectations(t)
 	provider2.AssertExpectations(t)
 }
+
+func TestWeatherChain_GetForecast_FallsThrough(t *testing.T) {
+
+	provider1 := new(mockWeatherProvider)
+	provider2 := new(mockWeatherProvider)
+	want := &ForecastDTO{MaxTemperature: 24, MinTemperature: 13}
+
+	provider1.On("FetchForecast", mock.Anything, "Lviv").Return(nil, errors.New("fail1"))
+	provider2.On("FetchForecast", mock.Anything, "Lviv").Return(want, nil)
+
+	mockLog, _ := logger.NewTestLogger()
+	chain := NewWeatherChain(provider1, *mockLog)
+	chain.SetNext(NewWeatherChain(provider2, *mockLog))
+
+	got, err := chain.GetForecast(context.Background(), "Lviv")
+	assert.NoError(t, err)
+	assert.Equal(t, want, got)
+	provider1.AssertExpectations(t)
+	provider2.AssertExpectations(t)
+}
+
+func TestWeatherChain_CancelledContextSkipsNextProvider(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	provider1 := new(mockWeatherProvider)
+	provider2 := new(mockWeatherProvider)
+	provider1.On("FetchWeather", ctx, "Kyiv").Return(nil, context.Canceled)
+
+	mockLog, _ := logger.NewTestLogger()
+	chain := NewWeatherChain(provider1, *mockLog)
+	chain.SetNext(NewWeatherChain(provider2, *mockLog))
+
+	_, err := chain.GetWeather(ctx, "Kyiv")
+	assert.ErrorIs(t, err, context.Canceled)
+	provider2.AssertNotCalled(t, "FetchWeather", mock.Anything, mock.Anything)
+}
@@ -12,3 +12,28 @@ type WeatherDTO struct {
 	Humidity    float64 `json:"humidity"`
 	Description string  `json:"description"`
 }
+
+// ForecastDTO is today's forecast for a city. Times are local to the city
+// and formatted as "15:04", so the mailer can show them as is.
+type ForecastDTO struct {
+	MaxTemperature      float64             `json:"max_temperature"`
+	MinTemperature      float64             `json:"min_temperature"`
+	PrecipitationChance int                 `json:"precipitation_chance"`
+	Sunrise             string              `json:"sunrise"`
+	Sunset              string              `json:"sunset"`
+	Hourly              []HourlyForecastDTO `json:"hourly"`
+}
+
+type HourlyForecastDTO struct {
+	Time                string  `json:"time"`
+	Temperature         float64 `json:"temperature"`
+	PrecipitationChance int     `json:"precipitation_chance"`
+	Description         string  `json:"description"`
+}
+
+// The hourly strip of a forecast holds up to HourlyForecastLength entries,
+// HourlyForecastStep hours apart, starting from the current hour.
+const (
+	HourlyForecastLength = 6
+	HourlyForecastStep   = 3
+)
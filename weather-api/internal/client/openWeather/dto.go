@@ -14,3 +14,25 @@ type OpenWeatherResponse struct {
 		Description string `json:"description"`
 	} `json:"weather"`
 }
+
+// OpenWeatherForecastResponse is the 5 day forecast in 3 hour steps.
+// Timestamps are UTC, City.Timezone is the city's offset in seconds.
+type OpenWeatherForecastResponse struct {
+	List []struct {
+		Dt   int64 `json:"dt"`
+		Main struct {
+			Temp    float64 `json:"temp"`
+			TempMin float64 `json:"temp_min"`
+			TempMax float64 `json:"temp_max"`
+		} `json:"main"`
+		Weather []struct {
+			Description string `json:"description"`
+		} `json:"weather"`
+		Pop float64 `json:"pop"`
+	} `json:"list"`
+	City struct {
+		Timezone int   `json:"timezone"`
+		Sunrise  int64 `json:"sunrise"`
+		Sunset   int64 `json:"sunset"`
+	} `json:"city"`
+}
@@ -1,6 +1,7 @@
 package openweather
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -10,8 +11,8 @@ import (
 	"net/url"
 	"strings"
 
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/logger"
 	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/client"
-	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/logger"
 )
 
 type GeocodingClient struct {
@@ -30,35 +31,40 @@ func NewGeocodingClient(apiKey string, apiUrl string, http *http.Client, logger
 	}
 }
 
-func (c *GeocodingClient) GetCityCoordinates(city string) (*Coordinates, error) {
+func (c *GeocodingClient) GetCityCoordinates(ctx context.Context, city string) (*Coordinates, error) {
 
 	city = url.QueryEscape(city)
 
 	geocodingURL := fmt.Sprintf("%s/geo/1.0/direct?q=%s&limit=1&appid=%s", c.apiUrl, city, c.apiKey)
 
-	c.logger.Info("Sending request to OpenWeather Geocoding API", "city", city, "url", geocodingURL)
-	resp, err := c.client.Get(geocodingURL)
+	c.logger.WithContext(ctx).Info("Sending request to OpenWeather Geocoding API", "city", city, "url", geocodingURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, geocodingURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.client.Do(req)
 
 	if err != nil {
-		c.logger.Error("HTTP request to OpenWeather Geocoding failed", "error", err)
+		c.logger.WithContext(ctx).Error("HTTP request to OpenWeather Geocoding failed", "error", err)
 
 		return nil, err
 	}
 
 	defer func() {
 		if err := resp.Body.Close(); err != nil {
-			c.logger.Error("Failed to close response body", "error", err)
+			c.logger.WithContext(ctx).Error("Failed to close response body", "error", err)
 		}
 	}()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		c.logger.Error("Failed to read geocoding response body", "error", err)
+		c.logger.WithContext(ctx).Error("Failed to read geocoding response body", "error", err)
 		return nil, err
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		c.logger.Error("Geocoding API returned non-200 status code",
+		c.logger.WithContext(ctx).Error("Geocoding API returned non-200 status code",
 			"statusCode", resp.StatusCode, "body", string(body))
 		return nil, errors.New("could not get city coordinates")
 	}
@@ -66,7 +72,7 @@ func (c *GeocodingClient) GetCityCoordinates(city string) (*Coordinates, error)
 	var geocoding []Coordinates
 
 	if err := json.Unmarshal(body, &geocoding); err != nil {
-		c.logger.Error("Failed to parse JSON response from Geocoding API", "error", err)
+		c.logger.WithContext(ctx).Error("Failed to parse JSON response from Geocoding API", "error", err)
 		return nil, err
 	}
 
@@ -5,12 +5,13 @@ package openweather
 
 import (
 	"bytes"
+	"context"
 	"io"
 	"net/http"
 	"testing"
 
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/logger"
 	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/client"
-	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/logger"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -47,7 +48,7 @@ func TestGetCityCoordinates_Success(t *testing.T) {
 
 	client := NewGeocodingClient(apiKey, apiUrl, mockClient, *mockLog)
 
-	coords, err := client.GetCityCoordinates("Kyiv")
+	coords, err := client.GetCityCoordinates(context.Background(), "Kyiv")
 	assert.NoError(t, err)
 	assert.NotNil(t, coords)
 	assert.Equal(t, 50.45, coords.Lat)
@@ -62,7 +63,7 @@ func TestGetCityCoordinates_NoCityFound(t *testing.T) {
 
 	geoClient := NewGeocodingClient(apiKey, apiUrl, mockClient, *mockLog)
 
-	coords, err := geoClient.GetCityCoordinates("Kyiv")
+	coords, err := geoClient.GetCityCoordinates(context.Background(), "Kyiv")
 	assert.Error(t, err)
 	assert.Equal(t, client.ErrCityNotFound.Error(), err.Error())
 	assert.Nil(t, coords)
@@ -76,7 +77,7 @@ func TestGetCityCoordinates_Non200Status(t *testing.T) {
 
 	client := NewGeocodingClient(apiKey, apiUrl, mockClient, *mockLog)
 
-	coords, err := client.GetCityCoordinates("Kyiv")
+	coords, err := client.GetCityCoordinates(context.Background(), "Kyiv")
 	assert.Error(t, err)
 	assert.Nil(t, coords)
 }
@@ -89,7 +90,7 @@ func TestGetCityCoordinates_InvalidJSON(t *testing.T) {
 	mockLog, _ := logger.NewTestLogger()
 	client := NewGeocodingClient(apiKey, apiUrl, mockClient, *mockLog)
 
-	coords, err := client.GetCityCoordinates("Kyiv")
+	coords, err := client.GetCityCoordinates(context.Background(), "Kyiv")
 	assert.Error(t, err)
 	assert.Nil(t, coords)
 }
@@ -1,19 +1,22 @@
 package openweather
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
 	"strings"
+	"time"
 
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/logger"
 	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/client"
-	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/logger"
 )
 
 type geocodingClient interface {
-	GetCityCoordinates(city string) (*Coordinates, error)
+	GetCityCoordinates(ctx context.Context, city string) (*Coordinates, error)
 }
 
 type WeatherAPIClient struct {
@@ -36,9 +39,9 @@ func NewWeatherAPIClient(apiKey string, apiUrl string,
 	}
 }
 
-func (c *WeatherAPIClient) FetchWeather(city string) (*client.WeatherDTO, error) {
+func (c *WeatherAPIClient) FetchWeather(ctx context.Context, city string) (*client.WeatherDTO, error) {
 
-	coord, err := c.geocoding.GetCityCoordinates(city)
+	coord, err := c.geocoding.GetCityCoordinates(ctx, city)
 
 	if err != nil {
 		return nil, err
@@ -46,53 +49,140 @@ func (c *WeatherAPIClient) FetchWeather(city string) (*client.WeatherDTO, error)
 
 	openWeatherUrl := fmt.Sprintf("%s/data/2.5/weather?lat=%f&lon=%f&appid=%s&units=metric",
 		c.apiUrl, coord.Lat, coord.Lon, c.apiKey)
-	sanitizedUrl := strings.Replace(openWeatherUrl, c.apiKey, "[REDACTED]", 1)
 
-	c.logger.Info("Sending request to OpenWeather API", "sanitizedURL", sanitizedUrl)
+	body, err := c.get(ctx, openWeatherUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	var weather OpenWeatherResponse
+
+	if err := json.Unmarshal(body, &weather); err != nil {
+		c.logger.WithContext(ctx).Error("Failed to parse JSON response from OpenWeather API", "error", err)
+		return nil, err
+	}
+
+	if len(weather.Weather) == 0 {
+		return nil, errors.New("weather data not found")
+	}
+
+	weatherDTO := client.WeatherDTO{
+		Temperature: weather.Main.Temp,
+		Humidity:    weather.Main.Humidity,
+		Description: weather.Weather[0].Description,
+	}
+
+	return &weatherDTO, nil
+}
+
+// FetchForecast returns today's forecast. OpenWeather has no daily summary
+// in this API, so the highs, lows and precipitation chance are taken from
+// the 3 hour steps left in the city's current day.
+func (c *WeatherAPIClient) FetchForecast(ctx context.Context, city string) (*client.ForecastDTO, error) {
 
-	resp, err := c.client.Get(openWeatherUrl)
+	coord, err := c.geocoding.GetCityCoordinates(ctx, city)
 
 	if err != nil {
-		c.logger.Error("HTTP request to OpenWeather failed", "error", err)
 		return nil, err
 	}
 
-	defer func() {
-		if err := resp.Body.Close(); err != nil {
-			c.logger.Error("Failed to close response body", "error", err)
-		}
-	}()
+	forecastUrl := fmt.Sprintf("%s/data/2.5/forecast?lat=%f&lon=%f&appid=%s&units=metric",
+		c.apiUrl, coord.Lat, coord.Lon, c.apiKey)
 
-	body, err := io.ReadAll(resp.Body)
+	body, err := c.get(ctx, forecastUrl)
 	if err != nil {
-		c.logger.Error("Failed to read OpenWeather response body", "error", err)
 		return nil, err
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		c.logger.Error("OpenWeather API returned non-200 status code",
-			"statusCode", resp.StatusCode, "body", string(body))
-		return nil, fmt.Errorf("OpenWeather API request failed with status %d: %s", resp.StatusCode, string(body))
+	var forecast OpenWeatherForecastResponse
+
+	if err := json.Unmarshal(body, &forecast); err != nil {
+		c.logger.WithContext(ctx).Error("Failed to parse JSON forecast response from OpenWeather API", "error", err)
+		return nil, err
+	}
+
+	if len(forecast.List) == 0 {
+		return nil, errors.New("forecast data not found")
 	}
 
-	c.logger.Info("OpenWeather response body", "body", string(body))
+	zone := time.FixedZone("", forecast.City.Timezone)
+	today := time.Unix(forecast.List[0].Dt, 0).In(zone).YearDay()
 
-	var weather OpenWeatherResponse
+	forecastDTO := client.ForecastDTO{
+		MaxTemperature: math.Inf(-1),
+		MinTemperature: math.Inf(1),
+		Sunrise:        time.Unix(forecast.City.Sunrise, 0).In(zone).Format("15:04"),
+		Sunset:         time.Unix(forecast.City.Sunset, 0).In(zone).Format("15:04"),
+		Hourly:         []client.HourlyForecastDTO{},
+	}
 
-	if err := json.Unmarshal(body, &weather); err != nil {
-		c.logger.Error("Failed to parse JSON response from OpenWeather API", "error", err)
+	for _, step := range forecast.List {
+		at := time.Unix(step.Dt, 0).In(zone)
+		if at.YearDay() != today {
+			break
+		}
+
+		chance := int(math.Round(step.Pop * 100))
+
+		forecastDTO.MaxTemperature = math.Max(forecastDTO.MaxTemperature, step.Main.TempMax)
+		forecastDTO.MinTemperature = math.Min(forecastDTO.MinTemperature, step.Main.TempMin)
+		forecastDTO.PrecipitationChance = max(forecastDTO.PrecipitationChance, chance)
+
+		if len(forecastDTO.Hourly) == client.HourlyForecastLength {
+			continue
+		}
+
+		hourly := client.HourlyForecastDTO{
+			Time:                at.Format("15:04"),
+			Temperature:         step.Main.Temp,
+			PrecipitationChance: chance,
+		}
+		if len(step.Weather) > 0 {
+			hourly.Description = step.Weather[0].Description
+		}
+
+		forecastDTO.Hourly = append(forecastDTO.Hourly, hourly)
+	}
+
+	return &forecastDTO, nil
+}
+
+func (c *WeatherAPIClient) get(ctx context.Context, requestUrl string) ([]byte, error) {
+	sanitizedUrl := strings.Replace(requestUrl, c.apiKey, "[REDACTED]", 1)
+
+	c.logger.WithContext(ctx).Info("Sending request to OpenWeather API", "sanitizedURL", sanitizedUrl)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestUrl, nil)
+	if err != nil {
 		return nil, err
 	}
 
-	if len(weather.Weather) == 0 {
-		return nil, errors.New("weather data not found")
+	resp, err := c.client.Do(req)
+
+	if err != nil {
+		c.logger.WithContext(ctx).Error("HTTP request to OpenWeather failed", "error", err)
+		return nil, err
 	}
 
-	weatherDTO := client.WeatherDTO{
-		Temperature: weather.Main.Temp,
-		Humidity:    weather.Main.Humidity,
-		Description: weather.Weather[0].Description,
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			c.logger.WithContext(ctx).Error("Failed to close response body", "error", err)
+		}
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		c.logger.WithContext(ctx).Error("Failed to read OpenWeather response body", "error", err)
+		return nil, err
 	}
 
-	return &weatherDTO, nil
+	if resp.StatusCode != http.StatusOK {
+		c.logger.WithContext(ctx).Error("OpenWeather API returned non-200 status code",
+			"statusCode", resp.StatusCode, "body", string(body))
+		return nil, fmt.Errorf("OpenWeather API request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	c.logger.WithContext(ctx).Info("OpenWeather response body", "body", string(body))
+
+	return body, nil
 }
@@ -4,11 +4,12 @@
 package openweather
 
 import (
+	"context"
 	"errors"
 	"net/http"
 	"testing"
 
-	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/logger"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/logger"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -19,7 +20,7 @@ type mockGeocodingClient struct {
 	err   error
 }
 
-func (m *mockGeocodingClient) GetCityCoordinates(city string) (*Coordinates, error) {
+func (m *mockGeocodingClient) GetCityCoordinates(ctx context.Context, city string) (*Coordinates, error) {
 	return m.coord, m.err
 }
 
@@ -35,7 +36,7 @@ func TestFetchWeather_Success(t *testing.T) {
 	mockLog, _ := logger.NewTestLogger()
 	api := NewWeatherAPIClient("testkey", "http://api", geo, client, *mockLog)
 
-	weather, err := api.FetchWeather("Kyiv")
+	weather, err := api.FetchWeather(context.Background(), "Kyiv")
 
 	assert.NoError(t, err)
 	assert.NotNil(t, weather)
@@ -49,7 +50,7 @@ func TestFetchWeather_GeocodingError(t *testing.T) {
 	mockLog, _ := logger.NewTestLogger()
 	api := NewWeatherAPIClient("testkey", "http://api", geo, http.DefaultClient, *mockLog)
 
-	weather, err := api.FetchWeather("Kyiv")
+	weather, err := api.FetchWeather(context.Background(), "Kyiv")
 
 	assert.Nil(t, weather)
 	assert.Error(t, err)
@@ -61,7 +62,7 @@ func TestFetchWeather_Non200Status(t *testing.T) {
 	mockLog, _ := logger.NewTestLogger()
 	api := NewWeatherAPIClient("testkey", "http://api", geo, client, *mockLog)
 
-	result, err := api.FetchWeather("Kyiv")
+	result, err := api.FetchWeather(context.Background(), "Kyiv")
 	assert.Error(t, err)
 	assert.Nil(t, result)
 	assert.Equal(t, "OpenWeather API request failed with status 404: could not get weather", err.Error())
@@ -73,7 +74,7 @@ func TestFetchWeather_BadJSON(t *testing.T) {
 	mockLog, _ := logger.NewTestLogger()
 	api := NewWeatherAPIClient("testkey", "http://api", geo, client, *mockLog)
 
-	result, err := api.FetchWeather("Kyiv")
+	result, err := api.FetchWeather(context.Background(), "Kyiv")
 	assert.Error(t, err)
 	assert.Nil(t, result)
 }
@@ -89,8 +90,46 @@ func TestFetchWeather_EmptyWeatherArray(t *testing.T) {
 	mockLog, _ := logger.NewTestLogger()
 	api := NewWeatherAPIClient("testkey", "http://api", geo, client, *mockLog)
 
-	result, err := api.FetchWeather("Kyiv")
+	result, err := api.FetchWeather(context.Background(), "Kyiv")
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
 }
+
+func TestFetchForecast_Success(t *testing.T) {
+	// timezone +3h: the steps are 09:00, 12:00, 21:00 local, then 00:00 tomorrow
+	forecastJSON := `{
+		"list": [
+			{"dt": 1748757600, "main": {"temp": 16, "temp_min": 15.2, "temp_max": 16.1}, "weather": [{"description": "clear sky"}], "pop": 0},
+			{"dt": 1748768400, "main": {"temp": 22, "temp_min": 21.5, "temp_max": 23.4}, "weather": [{"description": "light rain"}], "pop": 0.45},
+			{"dt": 1748800800, "main": {"temp": 14, "temp_min": 13.1, "temp_max": 14.2}, "weather": [], "pop": 0.1},
+			{"dt": 1748811600, "main": {"temp": 9, "temp_min": 8, "temp_max": 9}, "weather": [{"description": "clear sky"}], "pop": 0.9}
+		],
+		"city": {"timezone": 10800, "sunrise": 1748743620, "sunset": 1748800920}
+	}`
+	geo := &mockGeocodingClient{coord: &Coordinates{Lat: 50.0, Lon: 30.0}}
+	mockLog, _ := logger.NewTestLogger()
+	api := NewWeatherAPIClient("testkey", "http://api", geo, newMockClient(forecastJSON, 200, nil), *mockLog)
+
+	forecast, err := api.FetchForecast(context.Background(), "Kyiv")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 23.4, forecast.MaxTemperature)
+	assert.Equal(t, 13.1, forecast.MinTemperature)
+	assert.Equal(t, 45, forecast.PrecipitationChance)
+	assert.Equal(t, "05:07", forecast.Sunrise)
+	assert.Equal(t, "21:02", forecast.Sunset)
+	assert.Len(t, forecast.Hourly, 3)
+	assert.Equal(t, "09:00", forecast.Hourly[0].Time)
+	assert.Equal(t, "light rain", forecast.Hourly[1].Description)
+	assert.Equal(t, "", forecast.Hourly[2].Description)
+}
+
+func TestFetchForecast_Non200(t *testing.T) {
+	geo := &mockGeocodingClient{coord: &Coordinates{Lat: 50.0, Lon: 30.0}}
+	mockLog, _ := logger.NewTestLogger()
+	api := NewWeatherAPIClient("testkey", "http://api", geo, newMockClient(`{"cod": "401"}`, 401, nil), *mockLog)
+
+	_, err := api.FetchForecast(context.Background(), "Kyiv")
+	assert.Error(t, err)
+}
@@ -16,3 +16,31 @@ type WeatherAPIResponse struct {
 		} `json:"condition"`
 	} `json:"current"`
 }
+
+type WeatherAPIForecastResponse struct {
+	Location struct {
+		LocaltimeEpoch int64 `json:"localtime_epoch"`
+	} `json:"location"`
+	Forecast struct {
+		Forecastday []struct {
+			Day struct {
+				MaxTempC          float64 `json:"maxtemp_c"`
+				MinTempC          float64 `json:"mintemp_c"`
+				DailyChanceOfRain int     `json:"daily_chance_of_rain"`
+			} `json:"day"`
+			Astro struct {
+				Sunrise string `json:"sunrise"`
+				Sunset  string `json:"sunset"`
+			} `json:"astro"`
+			Hour []struct {
+				TimeEpoch    int64   `json:"time_epoch"`
+				Time         string  `json:"time"`
+				TempC        float64 `json:"temp_c"`
+				ChanceOfRain int     `json:"chance_of_rain"`
+				Condition    struct {
+					Text string `json:"text"`
+				} `json:"condition"`
+			} `json:"hour"`
+		} `json:"forecastday"`
+	} `json:"forecast"`
+}
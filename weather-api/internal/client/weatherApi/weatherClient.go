@@ -1,15 +1,18 @@
 package weatherapi
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/logger"
 	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/client"
-	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/logger"
 )
 
 type WeatherAPIClient struct {
@@ -28,61 +31,152 @@ func NewWeatherAPIClient(apiKey string, apiUrl string, http *http.Client, logger
 	}
 }
 
-func (c *WeatherAPIClient) FetchWeather(city string) (*client.WeatherDTO, error) {
+func (c *WeatherAPIClient) FetchWeather(ctx context.Context, city string) (*client.WeatherDTO, error) {
 	city = url.QueryEscape(city)
 
 	weatherURL := fmt.Sprintf("%s/current.json?key=%s&q=%s", c.apiUrl, c.apiKey, city)
 
-	c.logger.Info("Sending request to Weather API", "city", city, "url", weatherURL)
+	body, err := c.get(ctx, weatherURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var weather WeatherAPIResponse
+
+	if err := json.Unmarshal(body, &weather); err != nil {
+		c.logger.WithContext(ctx).Error("Failed to parse JSON response from Weather API", "error", err)
+		return nil, err
+	}
 
-	resp, err := c.client.Get(weatherURL)
+	weatherDTO := client.WeatherDTO{
+		Temperature: weather.Current.TempC,
+		Humidity:    weather.Current.Humidity,
+		Description: weather.Current.Condition.Text,
+	}
 
+	return &weatherDTO, nil
+}
+
+// FetchForecast returns today's forecast. The hourly strip starts at the
+// city's current hour, so a forecast fetched in the evening is shorter.
+func (c *WeatherAPIClient) FetchForecast(ctx context.Context, city string) (*client.ForecastDTO, error) {
+	city = url.QueryEscape(city)
+
+	forecastURL := fmt.Sprintf("%s/forecast.json?key=%s&q=%s&days=1&aqi=no&alerts=no", c.apiUrl, c.apiKey, city)
+
+	body, err := c.get(ctx, forecastURL)
 	if err != nil {
-		c.logger.Error("HTTP request to Weather API failed", "error", err)
+		return nil, err
+	}
+
+	var forecast WeatherAPIForecastResponse
+
+	if err := json.Unmarshal(body, &forecast); err != nil {
+		c.logger.WithContext(ctx).Error("Failed to parse JSON forecast response from Weather API", "error", err)
+		return nil, err
+	}
+
+	if len(forecast.Forecast.Forecastday) == 0 {
+		return nil, errors.New("forecast data not found")
+	}
+
+	today := forecast.Forecast.Forecastday[0]
+
+	forecastDTO := client.ForecastDTO{
+		MaxTemperature:      today.Day.MaxTempC,
+		MinTemperature:      today.Day.MinTempC,
+		PrecipitationChance: today.Day.DailyChanceOfRain,
+		Sunrise:             toClock(today.Astro.Sunrise),
+		Sunset:              toClock(today.Astro.Sunset),
+		Hourly:              []client.HourlyForecastDTO{},
+	}
+
+	// the current hour started up to an hour before localtime
+	from := forecast.Location.LocaltimeEpoch - int64(time.Hour.Seconds())
+	step := 0
+
+	for _, hour := range today.Hour {
+		if hour.TimeEpoch <= from {
+			continue
+		}
+
+		if step%client.HourlyForecastStep == 0 && len(forecastDTO.Hourly) < client.HourlyForecastLength {
+			forecastDTO.Hourly = append(forecastDTO.Hourly, client.HourlyForecastDTO{
+				Time:                toHour(hour.Time),
+				Temperature:         hour.TempC,
+				PrecipitationChance: hour.ChanceOfRain,
+				Description:         hour.Condition.Text,
+			})
+		}
+		step++
+	}
+
+	return &forecastDTO, nil
+}
+
+func (c *WeatherAPIClient) get(ctx context.Context, requestURL string) ([]byte, error) {
+	sanitizedUrl := strings.Replace(requestURL, c.apiKey, "[REDACTED]", 1)
+
+	c.logger.WithContext(ctx).Info("Sending request to Weather API", "url", sanitizedUrl)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.client.Do(req)
+
+	if err != nil {
+		c.logger.WithContext(ctx).Error("HTTP request to Weather API failed", "error", err)
 		return nil, err
 	}
 
 	defer func() {
 		if err := resp.Body.Close(); err != nil {
-			c.logger.Error("Failed to close response body", "error", err)
+			c.logger.WithContext(ctx).Error("Failed to close response body", "error", err)
 		}
 	}()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		c.logger.Error("Failed to read response body", "error", err)
+		c.logger.WithContext(ctx).Error("Failed to read response body", "error", err)
 
 		return nil, err
 	}
 
-	if apiErr := c.parseAPIError(body); apiErr != nil {
+	if apiErr := c.parseAPIError(ctx, body); apiErr != nil {
 		return nil, apiErr
 	}
 
-	var weather WeatherAPIResponse
+	return body, nil
+}
 
-	if err := json.Unmarshal(body, &weather); err != nil {
-		c.logger.Error("Failed to parse JSON response from Weather API", "error", err)
-		return nil, err
+// toClock converts Weather API's "06:12 AM" to "06:12".
+func toClock(value string) string {
+	parsed, err := time.Parse("03:04 PM", value)
+	if err != nil {
+		return value
 	}
+	return parsed.Format("15:04")
+}
 
-	weatherDTO := client.WeatherDTO{
-		Temperature: weather.Current.TempC,
-		Humidity:    weather.Current.Humidity,
-		Description: weather.Current.Condition.Text,
+// toHour converts Weather API's "2025-06-01 09:00" to "09:00".
+func toHour(value string) string {
+	parsed, err := time.Parse("2006-01-02 15:04", value)
+	if err != nil {
+		return value
 	}
-
-	return &weatherDTO, nil
+	return parsed.Format("15:04")
 }
 
-func (c *WeatherAPIClient) parseAPIError(body []byte) error {
+func (c *WeatherAPIClient) parseAPIError(ctx context.Context, body []byte) error {
 	var apiErr WeatherAPIErrorResponse
 	if err := json.Unmarshal(body, &apiErr); err != nil {
 		return nil
 	}
 
 	if apiErr.Error.Message != "" {
-		c.logger.Error("Weather API error", "message", apiErr.Error.Message, "code", apiErr.Error.Code)
+		c.logger.WithContext(ctx).Error("Weather API error", "message", apiErr.Error.Message, "code", apiErr.Error.Code)
 
 		if apiErr.Error.Code == 1006 {
 			return client.ErrCityNotFound
@@ -5,13 +5,14 @@ package weatherapi
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"io"
 	"net/http"
 	"testing"
 
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/logger"
 	packageClient "github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/client"
-	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/logger"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -52,7 +53,7 @@ func TestFetchWeather_Success(t *testing.T) {
 	mockLog, _ := logger.NewTestLogger()
 	apiClient := NewWeatherAPIClient("dummy-key", "api-url", client, *mockLog)
 
-	result, err := apiClient.FetchWeather("London")
+	result, err := apiClient.FetchWeather(context.Background(), "London")
 
 	assert.NoError(t, err)
 	assert.Equal(t, result.Temperature, 21.5)
@@ -65,7 +66,7 @@ func TestFetchWeather_HTTPError(t *testing.T) {
 	mockLog, _ := logger.NewTestLogger()
 	apiClient := NewWeatherAPIClient("dummy-key", "api-url", client, *mockLog)
 
-	_, err := apiClient.FetchWeather("London")
+	_, err := apiClient.FetchWeather(context.Background(), "London")
 
 	assert.Error(t, err)
 }
@@ -76,7 +77,7 @@ func TestFetchWeather_BadJSON(t *testing.T) {
 	mockLog, _ := logger.NewTestLogger()
 	apiClient := NewWeatherAPIClient("dummy-key", "api-url", client, *mockLog)
 
-	_, err := apiClient.FetchWeather("London")
+	_, err := apiClient.FetchWeather(context.Background(), "London")
 	assert.Error(t, err)
 }
 
@@ -91,7 +92,7 @@ func TestFetchWeather_APIError_CityNotFound(t *testing.T) {
 	mockLog, _ := logger.NewTestLogger()
 	apiClient := NewWeatherAPIClient("dummy-key", "api-url", client, *mockLog)
 
-	_, err := apiClient.FetchWeather("UnknownCity")
+	_, err := apiClient.FetchWeather(context.Background(), "UnknownCity")
 	assert.Error(t, err)
 	assert.True(t, errors.Is(err, packageClient.ErrCityNotFound), "expected ErrCityNotFound, got %v", err)
 }
@@ -107,8 +108,61 @@ func TestFetchWeather_APIError_InvalidRequest(t *testing.T) {
 	mockLog, _ := logger.NewTestLogger()
 	apiClient := NewWeatherAPIClient("dummy-key", "api-url", client, *mockLog)
 
-	_, err := apiClient.FetchWeather("London")
+	_, err := apiClient.FetchWeather(context.Background(), "London")
 
 	assert.Error(t, err)
 	assert.True(t, errors.Is(err, packageClient.ErrInvalidRequest), "expected ErrInvalidRequest, got %v", err)
 }
+
+func TestFetchForecast_Success(t *testing.T) {
+	// localtime is 09:05, so the strip starts at 09:00 and takes every third hour
+	mockBody := `{
+		"location": {"localtime_epoch": 1748768700},
+		"forecast": {"forecastday": [{
+			"day": {"maxtemp_c": 24.1, "mintemp_c": 12.9, "daily_chance_of_rain": 40},
+			"astro": {"sunrise": "04:47 AM", "sunset": "09:02 PM"},
+			"hour": [
+				{"time_epoch": 1748764800, "time": "2025-06-01 08:00", "temp_c": 15, "chance_of_rain": 0, "condition": {"text": "Clear"}},
+				{"time_epoch": 1748768400, "time": "2025-06-01 09:00", "temp_c": 16, "chance_of_rain": 0, "condition": {"text": "Sunny"}},
+				{"time_epoch": 1748772000, "time": "2025-06-01 10:00", "temp_c": 18, "chance_of_rain": 0, "condition": {"text": "Sunny"}},
+				{"time_epoch": 1748775600, "time": "2025-06-01 11:00", "temp_c": 20, "chance_of_rain": 10, "condition": {"text": "Sunny"}},
+				{"time_epoch": 1748779200, "time": "2025-06-01 12:00", "temp_c": 22, "chance_of_rain": 40, "condition": {"text": "Patchy rain"}}
+			]
+		}]}
+	}`
+	client := newMockClient(mockBody, 200, nil)
+	mockLog, _ := logger.NewTestLogger()
+	apiClient := NewWeatherAPIClient("dummy-key", "api-url", client, *mockLog)
+
+	result, err := apiClient.FetchForecast(context.Background(), "London")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 24.1, result.MaxTemperature)
+	assert.Equal(t, 12.9, result.MinTemperature)
+	assert.Equal(t, 40, result.PrecipitationChance)
+	assert.Equal(t, "04:47", result.Sunrise)
+	assert.Equal(t, "21:02", result.Sunset)
+	assert.Equal(t, []packageClient.HourlyForecastDTO{
+		{Time: "09:00", Temperature: 16, PrecipitationChance: 0, Description: "Sunny"},
+		{Time: "12:00", Temperature: 22, PrecipitationChance: 40, Description: "Patchy rain"},
+	}, result.Hourly)
+}
+
+func TestFetchForecast_APIError_CityNotFound(t *testing.T) {
+	mockBody := `{"error": {"code": 1006, "message": "No matching location found."}}`
+	client := newMockClient(mockBody, 200, nil)
+	mockLog, _ := logger.NewTestLogger()
+	apiClient := NewWeatherAPIClient("dummy-key", "api-url", client, *mockLog)
+
+	_, err := apiClient.FetchForecast(context.Background(), "UnknownCity")
+	assert.ErrorIs(t, err, packageClient.ErrCityNotFound)
+}
+
+func TestFetchForecast_NoForecastDays(t *testing.T) {
+	client := newMockClient(`{"forecast": {"forecastday": []}}`, 200, nil)
+	mockLog, _ := logger.NewTestLogger()
+	apiClient := NewWeatherAPIClient("dummy-key", "api-url", client, *mockLog)
+
+	_, err := apiClient.FetchForecast(context.Background(), "London")
+	assert.Error(t, err)
+}
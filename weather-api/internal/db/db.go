@@ -1,10 +1,11 @@
 package db
 
 import (
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/logger"
 	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/config"
-	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/logger"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/plugin/opentelemetry/tracing"
 )
 
 func ConnectToDatabase(config config.Config, logger logger.Logger) (*gorm.DB, error) {
@@ -19,6 +20,12 @@ func ConnectToDatabase(config config.Config, logger logger.Logger) (*gorm.DB, er
 
 	logger.Info("Connected to database", "dsn", dsn)
 
+	// queries run with a request context become spans of its trace
+	if err := db.Use(tracing.NewPlugin(tracing.WithoutMetrics())); err != nil {
+		logger.Error("Failed to enable database tracing", "error", err)
+		return nil, err
+	}
+
 	if err := AutomatedMigration(db); err != nil {
 		logger.Error("Failed to run database migrations", "error", err)
 		return nil, err
@@ -1,10 +1,31 @@
 package db
 
 import (
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/service/apikey"
 	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/service/subscription"
 	"gorm.io/gorm"
 )
 
+// Subscriptions used to be unique per email; now an address may follow
+// several cities, so the old constraint is dropped.
+const legacyEmailConstraint = "uni_subscriptions_email"
+
+// The email/city index used to be case-sensitive; it's replaced by one on
+// lower(email).
+const legacyEmailCityIndex = "idx_subscriptions_email_city"
+
 func AutomatedMigration(db *gorm.DB) error {
-	return db.AutoMigrate(&subscription.Subscription{})
+	if db.Migrator().HasConstraint(&subscription.Subscription{}, legacyEmailConstraint) {
+		if err := db.Migrator().DropConstraint(&subscription.Subscription{}, legacyEmailConstraint); err != nil {
+			return err
+		}
+	}
+
+	if db.Migrator().HasIndex(&subscription.Subscription{}, legacyEmailCityIndex) {
+		if err := db.Migrator().DropIndex(&subscription.Subscription{}, legacyEmailCityIndex); err != nil {
+			return err
+		}
+	}
+
+	return db.AutoMigrate(&subscription.Subscription{}, &apikey.APIKey{})
 }
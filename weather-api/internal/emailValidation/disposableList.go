@@ -0,0 +1,108 @@
+package emailvalidation
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	_ "embed"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/idna"
+)
+
+//go:embed disposableDomains.txt
+var builtinDisposableDomains []byte
+
+// DisposableList refuses addresses at throwaway email providers. The list
+// is read from a file, or the built-in one, and can be reloaded at runtime.
+type DisposableList struct {
+	path    string
+	mu      sync.RWMutex
+	domains map[string]struct{}
+}
+
+// NewDisposableList loads the domains in path, one per line with # for
+// comments. An empty path uses the built-in list.
+func NewDisposableList(path string) (*DisposableList, error) {
+	list := &DisposableList{path: path}
+
+	if err := list.Reload(); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// Reload re-reads the file. On error the current list is kept.
+func (l *DisposableList) Reload() error {
+	var source io.Reader = bytes.NewReader(builtinDisposableDomains)
+
+	if l.path != "" {
+		file, err := os.Open(l.path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		source = file
+	}
+
+	domains, err := readDomains(source)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	l.domains = domains
+	l.mu.Unlock()
+
+	return nil
+}
+
+func (l *DisposableList) Len() int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return len(l.domains)
+}
+
+// Check refuses the address if its domain, or a parent domain, is listed.
+func (l *DisposableList) Check(_ context.Context, address Address) error {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	domain := address.ASCIIDomain
+	for {
+		if _, ok := l.domains[domain]; ok {
+			return ErrDisposableDomain
+		}
+
+		dot := strings.Index(domain, ".")
+		if dot < 0 {
+			return nil
+		}
+		domain = domain[dot+1:]
+	}
+}
+
+func readDomains(source io.Reader) (map[string]struct{}, error) {
+	domains := map[string]struct{}{}
+
+	scanner := bufio.NewScanner(source)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		domain, err := idna.Lookup.ToASCII(line)
+		if err != nil {
+			continue
+		}
+		domains[domain] = struct{}{}
+	}
+
+	return domains, scanner.Err()
+}
@@ -0,0 +1,26 @@
+package emailvalidation
+
+// ValidationError tells the subscriber why an address was refused. Code is
+// stable and meant for clients, Message for people.
+type ValidationError struct {
+	Code    string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+var (
+	ErrInvalidSyntax    = &ValidationError{Code: "invalid_email", Message: "invalid email address"}
+	ErrDisposableDomain = &ValidationError{Code: "disposable_email", Message: "disposable email addresses are not allowed"}
+	ErrNoMailServer     = &ValidationError{Code: "email_domain_unreachable", Message: "email domain does not accept mail"}
+)
+
+// Address is a parsed email address. ASCIIDomain is the punycode form of
+// Domain, used for lookups.
+type Address struct {
+	Local       string
+	Domain      string
+	ASCIIDomain string
+}
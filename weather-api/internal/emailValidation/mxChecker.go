@@ -0,0 +1,125 @@
+package emailvalidation
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/logger"
+)
+
+// resolver is satisfied by *net.Resolver.
+type resolver interface {
+	LookupMX(ctx context.Context, name string) ([]*net.MX, error)
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+const (
+	lookupTimeout    = 3 * time.Second
+	maxCachedDomains = 10000
+)
+
+type mxResult struct {
+	accepts   bool
+	expiresAt time.Time
+}
+
+// MXChecker refuses addresses whose domain can't receive mail: it has no
+// MX records and no address to fall back to, or a null MX (RFC 7505).
+// Answers are cached for ttl; DNS failures let the address through.
+type MXChecker struct {
+	resolver resolver
+	ttl      time.Duration
+	now      func() time.Time
+	logger   logger.Logger
+
+	mu    sync.Mutex
+	cache map[string]mxResult
+}
+
+func NewMXChecker(resolver resolver, ttl time.Duration, logger logger.Logger) *MXChecker {
+	return &MXChecker{
+		resolver: resolver,
+		ttl:      ttl,
+		now:      time.Now,
+		logger:   logger,
+		cache:    map[string]mxResult{},
+	}
+}
+
+func (m *MXChecker) Check(ctx context.Context, address Address) error {
+	domain := address.ASCIIDomain
+
+	accepts, ok := m.cached(domain)
+	if !ok {
+		var err error
+		accepts, err = m.lookup(ctx, domain)
+		if err != nil {
+			m.logger.WithContext(ctx).Error("MX lookup failed, accepting address", "domain", domain, "error", err)
+			return nil
+		}
+		m.store(domain, accepts)
+	}
+
+	if !accepts {
+		return ErrNoMailServer
+	}
+
+	return nil
+}
+
+func (m *MXChecker) lookup(ctx context.Context, domain string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, lookupTimeout)
+	defer cancel()
+
+	records, err := m.resolver.LookupMX(ctx, domain)
+	if err != nil && !isNotFound(err) {
+		return false, err
+	}
+
+	if len(records) == 1 && records[0].Host == "." {
+		return false, nil
+	}
+	if len(records) > 0 {
+		return true, nil
+	}
+
+	// Without MX records mail goes to the domain itself (RFC 5321 5.1).
+	hosts, err := m.resolver.LookupHost(ctx, domain)
+	if err != nil && !isNotFound(err) {
+		return false, err
+	}
+
+	return len(hosts) > 0, nil
+}
+
+func (m *MXChecker) cached(domain string) (bool, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	result, ok := m.cache[domain]
+	if !ok || m.now().After(result.expiresAt) {
+		delete(m.cache, domain)
+		return false, false
+	}
+
+	return result.accepts, true
+}
+
+func (m *MXChecker) store(domain string, accepts bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.cache) >= maxCachedDomains {
+		m.cache = map[string]mxResult{}
+	}
+
+	m.cache[domain] = mxResult{accepts: accepts, expiresAt: m.now().Add(m.ttl)}
+}
+
+func isNotFound(err error) bool {
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) && dnsErr.IsNotFound
+}
@@ -0,0 +1,100 @@
+//go:build unit
+// +build unit
+
+package emailvalidation
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeDNS answers from fixed records and counts lookups.
+type fakeDNS struct {
+	mx      map[string][]*net.MX
+	hosts   map[string][]string
+	err     error
+	lookups int
+}
+
+func notFound(name string) error {
+	return &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+}
+
+func (f *fakeDNS) LookupMX(ctx context.Context, name string) ([]*net.MX, error) {
+	f.lookups++
+	if f.err != nil {
+		return nil, f.err
+	}
+	if records, ok := f.mx[name]; ok {
+		return records, nil
+	}
+	return nil, notFound(name)
+}
+
+func (f *fakeDNS) LookupHost(ctx context.Context, host string) ([]string, error) {
+	if hosts, ok := f.hosts[host]; ok {
+		return hosts, nil
+	}
+	return nil, notFound(host)
+}
+
+func setupMX() (*fakeDNS, *MXChecker) {
+	dns := &fakeDNS{
+		mx: map[string][]*net.MX{
+			"example.com":      {{Host: "mx.example.com.", Pref: 10}},
+			"nomail.example":   {{Host: ".", Pref: 0}},
+			"xn--bcher-kva.de": {{Host: "mx.xn--bcher-kva.de.", Pref: 10}},
+		},
+		hosts: map[string][]string{"a-only.example": {"192.0.2.1"}},
+	}
+	mockLog, _ := logger.NewTestLogger()
+
+	return dns, NewMXChecker(dns, time.Hour, *mockLog)
+}
+
+func checkMX(t *testing.T, checker *MXChecker, email string) error {
+	address, err := Parse(email)
+	assert.NoError(t, err)
+	return checker.Check(context.Background(), address)
+}
+
+func TestMXChecker(t *testing.T) {
+	_, checker := setupMX()
+
+	assert.NoError(t, checkMX(t, checker, "user@example.com"))
+	assert.NoError(t, checkMX(t, checker, "user@bücher.de"))
+	assert.NoError(t, checkMX(t, checker, "user@a-only.example"))
+	assert.ErrorIs(t, checkMX(t, checker, "user@nomail.example"), ErrNoMailServer)
+	assert.ErrorIs(t, checkMX(t, checker, "user@missing.example"), ErrNoMailServer)
+}
+
+func TestMXChecker_CachesAnswers(t *testing.T) {
+	dns, checker := setupMX()
+	now := time.Date(2025, time.June, 15, 10, 0, 0, 0, time.UTC)
+	checker.now = func() time.Time { return now }
+
+	assert.ErrorIs(t, checkMX(t, checker, "user@missing.example"), ErrNoMailServer)
+	assert.ErrorIs(t, checkMX(t, checker, "other@missing.example"), ErrNoMailServer)
+	assert.Equal(t, 1, dns.lookups)
+
+	now = now.Add(2 * time.Hour)
+	dns.mx["missing.example"] = []*net.MX{{Host: "mx.missing.example.", Pref: 10}}
+
+	assert.NoError(t, checkMX(t, checker, "user@missing.example"))
+	assert.Equal(t, 2, dns.lookups)
+}
+
+func TestMXChecker_AcceptsOnDNSFailure(t *testing.T) {
+	dns, checker := setupMX()
+	dns.err = errors.New("i/o timeout")
+
+	assert.NoError(t, checkMX(t, checker, "user@missing.example"))
+	assert.NoError(t, checkMX(t, checker, "user@missing.example"))
+	assert.Equal(t, 2, dns.lookups)
+}
@@ -0,0 +1,77 @@
+package emailvalidation
+
+import (
+	"net/mail"
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+const (
+	maxAddressLength = 254
+	maxLocalLength   = 64
+	maxDomainLength  = 253
+	maxLabelLength   = 63
+)
+
+// Parse accepts a bare RFC 5322 address, without a display name, whose
+// domain is a valid (possibly internationalized) host name.
+func Parse(email string) (Address, error) {
+	email = strings.TrimSpace(email)
+	if email == "" || len(email) > maxAddressLength {
+		return Address{}, ErrInvalidSyntax
+	}
+
+	parsed, err := mail.ParseAddress(email)
+	if err != nil || parsed.Name != "" || parsed.Address != email {
+		return Address{}, ErrInvalidSyntax
+	}
+
+	at := strings.LastIndex(email, "@")
+	local, domain := email[:at], email[at+1:]
+
+	if len(local) > maxLocalLength || strings.HasPrefix(domain, "[") {
+		return Address{}, ErrInvalidSyntax
+	}
+
+	asciiDomain, err := idna.Lookup.ToASCII(domain)
+	if err != nil || !validHostName(asciiDomain) {
+		return Address{}, ErrInvalidSyntax
+	}
+
+	unicodeDomain, err := idna.Lookup.ToUnicode(asciiDomain)
+	if err != nil {
+		return Address{}, ErrInvalidSyntax
+	}
+
+	return Address{Local: local, Domain: unicodeDomain, ASCIIDomain: asciiDomain}, nil
+}
+
+// validHostName checks an ASCII domain has at least two labels of letters,
+// digits and inner hyphens, and a top-level domain that isn't numeric.
+func validHostName(domain string) bool {
+	if len(domain) > maxDomainLength {
+		return false
+	}
+
+	labels := strings.Split(domain, ".")
+	if len(labels) < 2 {
+		return false
+	}
+
+	for _, label := range labels {
+		if len(label) == 0 || len(label) > maxLabelLength ||
+			label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+
+		for _, r := range label {
+			if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-') {
+				return false
+			}
+		}
+	}
+
+	tld := labels[len(labels)-1]
+	return len(tld) >= 2 && strings.Trim(tld, "0123456789") != ""
+}
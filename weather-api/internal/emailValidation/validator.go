@@ -0,0 +1,32 @@
+package emailvalidation
+
+import "context"
+
+type check interface {
+	Check(ctx context.Context, address Address) error
+}
+
+// Validator parses an address and runs it through checks in order,
+// stopping at the first failure.
+type Validator struct {
+	checks []check
+}
+
+func NewValidator(checks ...check) *Validator {
+	return &Validator{checks: checks}
+}
+
+func (v *Validator) Validate(ctx context.Context, email string) error {
+	address, err := Parse(email)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range v.checks {
+		if err := c.Check(ctx, address); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
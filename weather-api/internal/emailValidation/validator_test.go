@@ -0,0 +1,114 @@
+//go:build unit
+// +build unit
+
+package emailvalidation
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse_Valid(t *testing.T) {
+	cases := map[string]Address{
+		"user@example.com":         {Local: "user", Domain: "example.com", ASCIIDomain: "example.com"},
+		"first.last+tag@Sub.Ex.UA": {Local: "first.last+tag", Domain: "sub.ex.ua", ASCIIDomain: "sub.ex.ua"},
+		"user@bücher.de":           {Local: "user", Domain: "bücher.de", ASCIIDomain: "xn--bcher-kva.de"},
+		"user@xn--bcher-kva.de":    {Local: "user", Domain: "bücher.de", ASCIIDomain: "xn--bcher-kva.de"},
+		"user@приклад.укр":         {Local: "user", Domain: "приклад.укр", ASCIIDomain: "xn--80aikifvh.xn--j1amh"},
+	}
+
+	for email, expected := range cases {
+		address, err := Parse(email)
+		assert.NoError(t, err, email)
+		assert.Equal(t, expected, address, email)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	cases := []string{
+		"",
+		"plainaddress",
+		"@example.com",
+		"user@",
+		"user@localhost",
+		"user@example.123",
+		"user@-example.com",
+		"user@exa_mple.com",
+		"user@[192.168.0.1]",
+		"User <user@example.com>",
+		"user@example.com, other@example.com",
+		"a..b@example.com",
+		strings.Repeat("a", 65) + "@example.com",
+	}
+
+	for _, email := range cases {
+		_, err := Parse(email)
+		assert.ErrorIs(t, err, ErrInvalidSyntax, email)
+	}
+}
+
+func TestDisposableList_BuiltIn(t *testing.T) {
+	list, err := NewDisposableList("")
+	require.NoError(t, err)
+
+	for _, email := range []string{"x@mailinator.com", "x@inbox.mailinator.com", "x@YOPMAIL.com"} {
+		address, err := Parse(email)
+		require.NoError(t, err)
+		assert.ErrorIs(t, list.Check(context.Background(), address), ErrDisposableDomain, email)
+	}
+
+	address, _ := Parse("x@gmail.com")
+	assert.NoError(t, list.Check(context.Background(), address))
+}
+
+func TestDisposableList_ReloadFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "domains.txt")
+	require.NoError(t, os.WriteFile(path, []byte("# comment\nthrowaway.test\n"), 0o600))
+
+	list, err := NewDisposableList(path)
+	require.NoError(t, err)
+	assert.Equal(t, 1, list.Len())
+
+	first, _ := Parse("x@throwaway.test")
+	second, _ := Parse("x@burner.test")
+	assert.ErrorIs(t, list.Check(context.Background(), first), ErrDisposableDomain)
+	assert.NoError(t, list.Check(context.Background(), second))
+
+	require.NoError(t, os.WriteFile(path, []byte("burner.test\n"), 0o600))
+	require.NoError(t, list.Reload())
+
+	assert.NoError(t, list.Check(context.Background(), first))
+	assert.ErrorIs(t, list.Check(context.Background(), second), ErrDisposableDomain)
+
+	require.NoError(t, os.Remove(path))
+	assert.Error(t, list.Reload())
+	assert.ErrorIs(t, list.Check(context.Background(), second), ErrDisposableDomain)
+}
+
+type checkFunc func(Address) error
+
+func (f checkFunc) Check(_ context.Context, address Address) error {
+	return f(address)
+}
+
+func TestValidator_StopsAtFirstFailure(t *testing.T) {
+	failing := errors.New("first")
+	called := false
+
+	validator := NewValidator(
+		checkFunc(func(Address) error { return failing }),
+		checkFunc(func(Address) error { called = true; return nil }),
+	)
+
+	assert.ErrorIs(t, validator.Validate(context.Background(), "user@example.com"), failing)
+	assert.False(t, called)
+
+	assert.ErrorIs(t, validator.Validate(context.Background(), "not an email"), ErrInvalidSyntax)
+}
@@ -4,13 +4,17 @@ import (
 	"net"
 	"net/http"
 	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 )
 
+// InitHttpClient returns the client for outgoing calls. Each call is traced
+// as a client span and carries the trace context in its headers.
 func InitHttpClient() http.Client {
 	return http.Client{
 		Timeout: 2 * time.Second,
 
-		Transport: &http.Transport{
+		Transport: otelhttp.NewTransport(&http.Transport{
 			// Max idle connections
 			MaxIdleConns:        100,
 			MaxIdleConnsPerHost: 10,
@@ -26,6 +30,6 @@ func InitHttpClient() http.Client {
 				Timeout:   5 * time.Second,
 				KeepAlive: 30 * time.Second,
 			}).DialContext,
-		},
+		}),
 	}
 }
@@ -8,7 +8,7 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/rabbitmq"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/rabbitmq"
 	redisClient "github.com/redis/go-redis/v9"
 
 	amqp "github.com/rabbitmq/amqp091-go"
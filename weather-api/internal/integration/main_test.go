@@ -4,23 +4,25 @@
 package integration
 
 import (
-	"context"
 	"log"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"testing"
 
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/logger"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/rabbitmq"
 	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/client"
 	weatherapi "github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/client/weatherApi"
 	dbPackage "github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/db"
-	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/rabbitmq"
+	emailvalidation "github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/emailValidation"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/problem"
 	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/redis"
 	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/repository"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/requestid"
 	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/routes"
 	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/service/subscription"
 	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/service/weather"
-	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/logger"
 
 	"github.com/gin-gonic/gin"
 )
@@ -34,8 +36,6 @@ var (
 )
 
 func setupRouter() (*gin.Engine, *repository.SubscriptionRepository, func()) {
-	ctx := context.Background()
-
 	logger, _ := logger.NewTestLogger()
 
 	// Setup Postgres container
@@ -77,7 +77,7 @@ func setupRouter() (*gin.Engine, *repository.SubscriptionRepository, func()) {
 		}
 	}))
 
-	redisProvider := redis.NewRedisProvider(redisTest, ctx, *logger)
+	redisProvider := redis.NewRedisProvider(redisTest, *logger)
 	fakeWeatherClient := weatherapi.NewWeatherAPIClient("fake-key", fakeWeatherServer.URL, http.DefaultClient, *logger)
 	weatherChain := client.NewWeatherChain(fakeWeatherClient, *logger)
 	weatherService := weather.NewWeatherAPIService(weatherChain, &redisProvider, *logger)
@@ -85,10 +85,12 @@ func setupRouter() (*gin.Engine, *repository.SubscriptionRepository, func()) {
 
 	repo := repository.NewSubscriptionRepository(db)
 	emailPublisher := rabbitmq.NewRabbitMQPublisher(rabbitMQTest.Channel)
-	subscribeService := subscription.NewSubscribeService(weatherService, repo, emailPublisher, *logger)
-	subscribeController := subscription.NewSubscribeController(subscribeService)
+	subscribeService := subscription.NewSubscribeService(weatherService, repo, emailPublisher,
+		subscription.ChangeThresholds{Temperature: 2, Humidity: 15}, *logger)
+	subscribeController := subscription.NewSubscribeController(subscribeService, emailvalidation.NewValidator())
 
 	r := gin.Default()
+	r.Use(requestid.Middleware(), problem.Handler(*logger))
 	api := r.Group("/api")
 	routes.WeatherRoute(api, weatherController)
 	routes.SubscribeRoute(api, subscribeController)
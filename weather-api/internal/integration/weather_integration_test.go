@@ -4,13 +4,13 @@
 package integration
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
-	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/client"
-	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/service/weather"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/problem"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -20,10 +20,11 @@ func TestWeatherEndpoint_Scenarios(t *testing.T) {
 		cityQuery      string
 		expectedStatus int
 		expectBody     string
+		expectCode     string
 	}{
-		{"valid city", "Kyiv", http.StatusOK, `{"temperature":21.5,"humidity":55,"description":"Sunny"}`},
-		{"missing city", "", http.StatusBadRequest, weather.ErrInvalidCityInput.Error()},
-		{"city not found", "Nowhere", http.StatusNotFound, client.ErrCityNotFound.Error()},
+		{"valid city", "Kyiv", http.StatusOK, `{"temperature":21.5,"humidity":55,"description":"Sunny"}`, ""},
+		{"missing city", "", http.StatusBadRequest, "", "validation_failed"},
+		{"city not found", "Nowhere", http.StatusNotFound, "", "city_not_found"},
 	}
 
 	for _, tt := range tests {
@@ -36,7 +37,18 @@ func TestWeatherEndpoint_Scenarios(t *testing.T) {
 			t.Logf("Response: %d - %s", resp.Code, resp.Body.String())
 
 			assert.Equal(t, tt.expectedStatus, resp.Code)
-			assert.Equal(t, tt.expectBody, resp.Body.String())
+
+			if tt.expectCode == "" {
+				assert.Equal(t, tt.expectBody, resp.Body.String())
+				return
+			}
+
+			var body problem.Problem
+			assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+			assert.Equal(t, problem.ContentType, resp.Header().Get("Content-Type"))
+			assert.Equal(t, tt.expectCode, body.Code)
+			assert.Equal(t, tt.expectedStatus, body.Status)
+			assert.NotEmpty(t, body.RequestID)
 		})
 	}
 }
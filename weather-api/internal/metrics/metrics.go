@@ -44,3 +44,70 @@ func MetricsMiddleware() gin.HandlerFunc {
 
 	}
 }
+
+var cacheBackend = promauto.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "cache_backend",
+		Help: "Cache backend in use, 1 for the active one",
+	},
+	[]string{"backend"},
+)
+
+func SetCacheBackend(backend string, active bool) {
+	value := 0.0
+	if active {
+		value = 1
+	}
+	cacheBackend.WithLabelValues(backend).Set(value)
+}
+
+var cacheLookupsTotal = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "cache_lookups_total",
+		Help: "Cache lookups per tier and result",
+	},
+	[]string{"tier", "result"},
+)
+
+func CacheLookup(tier string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	cacheLookupsTotal.WithLabelValues(tier, result).Inc()
+}
+
+var (
+	cacheWarmUpCities = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "cache_warmup_cities",
+			Help: "Distinct cities of the last cache warm-up",
+		},
+		[]string{"frequency"},
+	)
+	cacheWarmUpCoverage = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "cache_warmup_coverage_ratio",
+			Help: "Share of the cities refreshed by the last cache warm-up",
+		},
+		[]string{"frequency"},
+	)
+	cacheWarmUpFailuresTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "cache_warmup_failures_total",
+			Help: "Cities a cache warm-up failed to refresh",
+		},
+		[]string{"frequency"},
+	)
+)
+
+func CacheWarmUp(frequency string, cities int, failures int) {
+	coverage := 1.0
+	if cities > 0 {
+		coverage = float64(cities-failures) / float64(cities)
+	}
+
+	cacheWarmUpCities.WithLabelValues(frequency).Set(float64(cities))
+	cacheWarmUpCoverage.WithLabelValues(frequency).Set(coverage)
+	cacheWarmUpFailuresTotal.WithLabelValues(frequency).Add(float64(failures))
+}
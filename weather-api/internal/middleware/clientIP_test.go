@@ -0,0 +1,72 @@
+//go:build unit
+// +build unit
+
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/service/apikey"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingConsumer struct {
+	clientIP string
+}
+
+func (r *recordingConsumer) Consume(_ context.Context, _ string, clientIP string) (apikey.Usage, error) {
+	r.clientIP = clientIP
+	return apikey.Usage{}, nil
+}
+
+type recordingVerifier struct {
+	remoteIP string
+}
+
+func (r *recordingVerifier) Verify(_ context.Context, _ string, remoteIP string) (bool, error) {
+	r.remoteIP = remoteIP
+	return true, nil
+}
+
+// spoofedRequest comes straight from a client that claims another IP.
+func spoofedRequest(t *testing.T, router *gin.Engine) {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "198.51.100.4:52000"
+	req.Header.Set("X-Forwarded-For", "203.0.113.7")
+	req.Header.Set("X-Real-IP", "203.0.113.8")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+}
+
+func untrustingRouter(t *testing.T, handler gin.HandlerFunc) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	require.NoError(t, router.SetTrustedProxies(nil))
+	router.GET("/", handler, func(c *gin.Context) { c.Status(http.StatusOK) })
+	return router
+}
+
+func TestRequireQuota_IgnoresForwardedForFromUntrustedPeers(t *testing.T) {
+	consumer := &recordingConsumer{}
+	spoofedRequest(t, untrustingRouter(t, RequireQuota(consumer)))
+
+	assert.Equal(t, "198.51.100.4", consumer.clientIP)
+}
+
+func TestRequireChallenge_IgnoresForwardedForFromUntrustedPeers(t *testing.T) {
+	verifier := &recordingVerifier{}
+	spoofedRequest(t, untrustingRouter(t, RequireChallenge(verifier)))
+
+	assert.Equal(t, "198.51.100.4", verifier.remoteIP)
+}
@@ -0,0 +1,11 @@
+package middleware
+
+import "errors"
+
+var (
+	ErrUnauthorized       = errors.New("authentication required")
+	ErrTooManyRequests    = errors.New("too many requests, try again later")
+	ErrCaptchaFailed      = errors.New("captcha verification failed")
+	ErrCaptchaUnavailable = errors.New("captcha verification unavailable")
+	ErrRequestTimeout     = errors.New("request timed out")
+)
@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/problem"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/ratelimit"
+	"github.com/gin-gonic/gin"
+)
+
+type rateLimiter interface {
+	Allow(ctx context.Context, key string) ratelimit.Decision
+}
+
+// KeyFunc picks what a request is counted against; an empty key skips
+// the limiter.
+type KeyFunc func(c *gin.Context) string
+
+// RateLimit rejects requests over the limiter's limit with 429 and a
+// Retry-After header.
+func RateLimit(limiter rateLimiter, key KeyFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		k := key(c)
+		if k == "" {
+			c.Next()
+			return
+		}
+
+		decision := limiter.Allow(c.Request.Context(), k)
+		if !decision.Allowed {
+			c.Header("Retry-After", strconv.Itoa(int(decision.RetryAfter.Seconds())))
+			problem.Abort(c, http.StatusTooManyRequests, "rate_limited", ErrTooManyRequests)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// ByClientIP counts requests per client IP.
+func ByClientIP(c *gin.Context) string {
+	return c.ClientIP()
+}
+
+// ByJSONField counts requests per value of a string field of the JSON
+// body, case-insensitively. The body is put back for the handler.
+func ByJSONField(field string) KeyFunc {
+	return func(c *gin.Context) string {
+		if c.Request.Body == nil {
+			return ""
+		}
+
+		data, err := io.ReadAll(c.Request.Body)
+		c.Request.Body = io.NopCloser(bytes.NewReader(data))
+		if err != nil {
+			return ""
+		}
+
+		var body map[string]interface{}
+		if err := json.Unmarshal(data, &body); err != nil {
+			return ""
+		}
+
+		value, _ := body[field].(string)
+
+		return strings.ToLower(strings.TrimSpace(value))
+	}
+}
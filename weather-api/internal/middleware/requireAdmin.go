@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/problem"
+	"github.com/gin-gonic/gin"
+)
+
+// RequireAdmin lets a request through when it carries apiKey in the
+// X-API-Key header or, if username is set, matching basic auth credentials.
+// An empty apiKey or username disables that method.
+func RequireAdmin(apiKey string, username string, password string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if apiKey != "" && equal(c.GetHeader("X-API-Key"), apiKey) {
+			c.Next()
+			return
+		}
+
+		if user, pass, ok := c.Request.BasicAuth(); ok && username != "" &&
+			equal(user, username) && equal(pass, password) {
+			c.Next()
+			return
+		}
+
+		if username != "" {
+			c.Header("WWW-Authenticate", `Basic realm="admin"`)
+		}
+		problem.Abort(c, http.StatusUnauthorized, "unauthorized", ErrUnauthorized)
+	}
+}
+
+func equal(provided string, expected string) bool {
+	return subtle.ConstantTimeCompare([]byte(provided), []byte(expected)) == 1
+}
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/problem"
+	"github.com/gin-gonic/gin"
+)
+
+const HeaderCaptchaToken = "X-Captcha-Token"
+
+type challengeVerifier interface {
+	Verify(ctx context.Context, token string, remoteIP string) (bool, error)
+}
+
+// RequireChallenge only lets requests through whose X-Captcha-Token the
+// verifier accepts.
+func RequireChallenge(verifier challengeVerifier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ok, err := verifier.Verify(c.Request.Context(), c.GetHeader(HeaderCaptchaToken), c.ClientIP())
+		if err != nil {
+			problem.Abort(c, http.StatusServiceUnavailable, "captcha_unavailable", ErrCaptchaUnavailable)
+			return
+		}
+
+		if !ok {
+			problem.Abort(c, http.StatusForbidden, "captcha_failed", ErrCaptchaFailed)
+			return
+		}
+
+		c.Next()
+	}
+}
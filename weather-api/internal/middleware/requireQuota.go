@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/service/apikey"
+	"github.com/gin-gonic/gin"
+)
+
+type quotaConsumer interface {
+	Consume(ctx context.Context, rawKey string, clientIP string) (apikey.Usage, error)
+}
+
+// RequireQuota counts the request against the quota of the caller's API
+// key, or of its IP for anonymous callers, and reports the usage in the
+// X-RateLimit headers.
+func RequireQuota(consumer quotaConsumer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		usage, err := consumer.Consume(c.Request.Context(), c.GetHeader(apikey.HeaderAPIKey), c.ClientIP())
+
+		if window, ok := usage.Binding(); ok {
+			c.Header("X-RateLimit-Limit", strconv.FormatInt(window.Limit, 10))
+			c.Header("X-RateLimit-Remaining", strconv.FormatInt(window.Remaining(), 10))
+			c.Header("X-RateLimit-Reset", strconv.FormatInt(window.Reset.Unix(), 10))
+
+			if errors.Is(err, apikey.ErrQuotaExceeded) {
+				retryAfter := int(time.Until(window.Reset).Seconds()) + 1
+				c.Header("Retry-After", strconv.Itoa(retryAfter))
+			}
+		}
+
+		if err != nil {
+			apikey.HandleError(c, err)
+			return
+		}
+
+		c.Next()
+	}
+}
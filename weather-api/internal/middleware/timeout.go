@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/problem"
+	"github.com/gin-gonic/gin"
+)
+
+var versionPrefix = regexp.MustCompile(`^/api/v\d+/`)
+
+// Timeout puts a deadline on the request context, so the providers,
+// database and Redis calls made for it are cancelled once it passes.
+// perRoute is keyed by gin route path; a versioned route such as
+// /api/v2/weather falls back to /api/weather, then to defaultTimeout.
+// A timeout that isn't positive runs the request without a deadline.
+func Timeout(defaultTimeout time.Duration, perRoute map[string]time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		timeout := routeTimeout(c.FullPath(), defaultTimeout, perRoute)
+		if timeout <= 0 {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+
+		// whatever error the handler reported, the deadline is the cause
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) && !c.Writer.Written() {
+			problem.Abort(c, http.StatusGatewayTimeout, "request_timeout", ErrRequestTimeout)
+		}
+	}
+}
+
+func routeTimeout(path string, defaultTimeout time.Duration, perRoute map[string]time.Duration) time.Duration {
+	if timeout, ok := perRoute[path]; ok {
+		return timeout
+	}
+
+	if versionPrefix.MatchString(path) {
+		if timeout, ok := perRoute[versionPrefix.ReplaceAllString(path, "/api/")]; ok {
+			return timeout
+		}
+	}
+
+	return defaultTimeout
+}
@@ -0,0 +1,55 @@
+//go:build unit
+// +build unit
+
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/logger"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/problem"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeout_CancelsSlowRequests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockLog, _ := logger.NewTestLogger()
+
+	router := gin.New()
+	router.Use(problem.Handler(*mockLog), Timeout(time.Minute, map[string]time.Duration{
+		"/api/weather": 20 * time.Millisecond,
+	}))
+	router.GET("/api/v2/weather", func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+			problem.Abort(c, http.StatusBadGateway, "weather_unavailable", errors.New("provider cancelled"))
+		case <-time.After(time.Second):
+			c.Status(http.StatusOK)
+		}
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v2/weather", nil))
+
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"request_timeout"`)
+}
+
+func TestRouteTimeout(t *testing.T) {
+	perRoute := map[string]time.Duration{
+		"/api/weather":    5 * time.Second,
+		"/api/v2/weather": 3 * time.Second,
+		"/admin/dispatch": -1,
+	}
+
+	assert.Equal(t, 3*time.Second, routeTimeout("/api/v2/weather", time.Minute, perRoute))
+	assert.Equal(t, 5*time.Second, routeTimeout("/api/v1/weather", time.Minute, perRoute))
+	assert.Equal(t, 5*time.Second, routeTimeout("/api/weather", time.Minute, perRoute))
+	assert.Equal(t, time.Duration(-1), routeTimeout("/admin/dispatch", time.Minute, perRoute))
+	assert.Equal(t, time.Minute, routeTimeout("/api/subscribe", time.Minute, perRoute))
+}
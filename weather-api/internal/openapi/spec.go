@@ -0,0 +1,111 @@
+package openapi
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gin-gonic/gin"
+)
+
+//go:embed openapi.yaml
+var document []byte
+
+// Routes under /api are an undocumented alias of /api/v1.
+const (
+	legacyPrefix = "/api/"
+	v1Prefix     = "/api/v1/"
+)
+
+// Spec is the OpenAPI document of the weather-api, the contract the
+// frontend generates its client from.
+type Spec struct {
+	doc  *openapi3.T
+	json []byte
+}
+
+// Load parses the embedded document and checks it is a valid OpenAPI 3 spec.
+func Load() (*Spec, error) {
+	loader := openapi3.NewLoader()
+
+	doc, err := loader.LoadFromData(document)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse openapi.yaml: %w", err)
+	}
+
+	if err := doc.Validate(context.Background()); err != nil {
+		return nil, fmt.Errorf("invalid openapi.yaml: %w", err)
+	}
+
+	data, err := doc.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Spec{doc: doc, json: data}, nil
+}
+
+// Serve writes the spec as JSON.
+func (s *Spec) Serve(c *gin.Context) {
+	c.Data(http.StatusOK, "application/json; charset=utf-8", s.json)
+}
+
+// Documents reports whether the spec has an operation for a gin route.
+func (s *Spec) Documents(method string, ginPath string) bool {
+	_, operation := s.find(method, ginPath)
+	return operation != nil
+}
+
+// Operations lists the documented operations as "METHOD /path" with gin
+// path parameters.
+func (s *Spec) Operations() []string {
+	var operations []string
+
+	for path, item := range s.doc.Paths.Map() {
+		for method := range item.Operations() {
+			operations = append(operations, method+" "+ginPath(path))
+		}
+	}
+
+	return operations
+}
+
+func (s *Spec) find(method string, ginPath string) (*openapi3.PathItem, *openapi3.Operation) {
+	item := s.doc.Paths.Value(specPath(ginPath))
+	if item == nil && strings.HasPrefix(ginPath, legacyPrefix) {
+		item = s.doc.Paths.Value(specPath(v1Prefix + strings.TrimPrefix(ginPath, legacyPrefix)))
+	}
+	if item == nil {
+		return nil, nil
+	}
+
+	return item, item.GetOperation(method)
+}
+
+// specPath turns gin's /subscriptions/:id into /subscriptions/{id}.
+func specPath(ginPath string) string {
+	segments := strings.Split(ginPath, "/")
+
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+
+	return strings.Join(segments, "/")
+}
+
+func ginPath(specPath string) string {
+	segments := strings.Split(specPath, "/")
+
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			segments[i] = ":" + segment[1:len(segment)-1]
+		}
+	}
+
+	return strings.Join(segments, "/")
+}
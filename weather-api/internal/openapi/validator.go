@@ -0,0 +1,116 @@
+package openapi
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/problem"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/gin-gonic/gin"
+)
+
+// Validate checks the parameters and body of requests to documented routes
+// against the spec and rejects invalid ones with a field per violation.
+// Authentication is left to the route's own middleware.
+func (s *Spec) Validate() gin.HandlerFunc {
+	options := &openapi3filter.Options{
+		MultiError:          true,
+		SkipSettingDefaults: true,
+		AuthenticationFunc:  openapi3filter.NoopAuthenticationFunc,
+	}
+
+	return func(c *gin.Context) {
+		item, operation := s.find(c.Request.Method, c.FullPath())
+		if operation == nil {
+			c.Next()
+			return
+		}
+
+		params := make(map[string]string, len(c.Params))
+		for _, param := range c.Params {
+			params[param.Key] = param.Value
+		}
+
+		input := &openapi3filter.RequestValidationInput{
+			Request:    c.Request,
+			PathParams: params,
+			Route: &routers.Route{
+				Spec:      s.doc,
+				Path:      specPath(c.FullPath()),
+				PathItem:  item,
+				Method:    c.Request.Method,
+				Operation: operation,
+			},
+			Options: options,
+		}
+
+		if err := openapi3filter.ValidateRequest(c.Request.Context(), input); err != nil {
+			problem.AbortValidation(c, fieldErrors(err)...)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func fieldErrors(err error) []problem.FieldError {
+	switch e := err.(type) {
+	case openapi3.MultiError:
+		var fields []problem.FieldError
+		for _, inner := range e {
+			fields = append(fields, fieldErrors(inner)...)
+		}
+		return fields
+
+	case *openapi3filter.RequestError:
+		if e.Parameter != nil {
+			return []problem.FieldError{parameterError(e)}
+		}
+		if errors.Is(e.Err, openapi3filter.ErrInvalidRequired) {
+			return []problem.FieldError{{Field: "body", Code: "required", Message: "request body is required"}}
+		}
+		if _, ok := e.Err.(openapi3.MultiError); ok {
+			return fieldErrors(e.Err)
+		}
+		if _, ok := e.Err.(*openapi3.SchemaError); ok {
+			return fieldErrors(e.Err)
+		}
+		return []problem.FieldError{{Field: "body", Code: "invalid", Message: e.Error()}}
+
+	case *openapi3.SchemaError:
+		field := strings.Join(e.JSONPointer(), ".")
+		if field == "" {
+			field = "body"
+		}
+		return []problem.FieldError{{Field: field, Code: schemaCode(e), Message: e.Reason}}
+
+	default:
+		return []problem.FieldError{{Field: "request", Code: "invalid", Message: err.Error()}}
+	}
+}
+
+func parameterError(e *openapi3filter.RequestError) problem.FieldError {
+	field := problem.FieldError{Field: e.Parameter.Name, Code: "invalid", Message: e.Reason}
+
+	var schemaErr *openapi3.SchemaError
+	switch {
+	case errors.Is(e.Err, openapi3filter.ErrInvalidRequired):
+		field.Code = "required"
+		field.Message = e.Parameter.Name + " is required"
+	case errors.As(e.Err, &schemaErr):
+		field.Message = schemaErr.Reason
+	case e.Err != nil:
+		field.Message = e.Err.Error()
+	}
+
+	return field
+}
+
+func schemaCode(e *openapi3.SchemaError) string {
+	if e.SchemaField == "required" {
+		return "required"
+	}
+	return "invalid"
+}
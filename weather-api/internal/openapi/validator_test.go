@@ -0,0 +1,121 @@
+//go:build unit
+// +build unit
+
+package openapi
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/logger"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/problem"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupValidator(t *testing.T) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	mockLog, _ := logger.NewTestLogger()
+
+	spec, err := Load()
+	require.NoError(t, err)
+
+	echo := func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		c.String(http.StatusOK, string(body))
+	}
+
+	router := gin.New()
+	router.Use(problem.Handler(*mockLog))
+	api := router.Group("/api", spec.Validate())
+	api.GET("/weather", echo)
+	api.POST("/subscribe", echo)
+	api.GET("/undocumented", echo)
+	router.Group("/admin", spec.Validate()).GET("/subscriptions", echo)
+
+	return router
+}
+
+func request(router *gin.Engine, method string, target string, body string) (*httptest.ResponseRecorder, problem.Problem) {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	var result problem.Problem
+	if resp.Header().Get("Content-Type") == problem.ContentType {
+		_ = json.Unmarshal(resp.Body.Bytes(), &result)
+	}
+
+	return resp, result
+}
+
+func TestValidate_MissingQueryParameter(t *testing.T) {
+	router := setupValidator(t)
+
+	resp, body := request(router, http.MethodGet, "/api/weather", "")
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.Equal(t, "validation_failed", body.Code)
+	assert.Equal(t, []problem.FieldError{{Field: "city", Code: "required", Message: "city is required"}}, body.Errors)
+}
+
+func TestValidate_BodyFields(t *testing.T) {
+	router := setupValidator(t)
+
+	resp, body := request(router, http.MethodPost, "/api/subscribe", `{"email": "user@example.com", "frequency": "weekly"}`)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	fields := map[string]string{}
+	for _, field := range body.Errors {
+		fields[field.Field] = field.Code
+	}
+	assert.Equal(t, map[string]string{"city": "required", "frequency": "invalid"}, fields)
+}
+
+func TestValidate_MalformedBody(t *testing.T) {
+	router := setupValidator(t)
+
+	resp, body := request(router, http.MethodPost, "/api/subscribe", `{"email":`)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	require.Len(t, body.Errors, 1)
+	assert.Equal(t, "body", body.Errors[0].Field)
+}
+
+func TestValidate_ValidRequestKeepsBody(t *testing.T) {
+	router := setupValidator(t)
+	payload := `{"email": "user@bücher.de", "city": "Kyiv", "frequency": "daily"}`
+
+	resp, _ := request(router, http.MethodPost, "/api/subscribe", payload)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, payload, resp.Body.String())
+}
+
+func TestValidate_QueryTypes(t *testing.T) {
+	router := setupValidator(t)
+
+	resp, body := request(router, http.MethodGet, "/admin/subscriptions?confirmed=maybe&page=0", "")
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.Len(t, body.Errors, 2)
+
+	resp, _ = request(router, http.MethodGet, "/admin/subscriptions?confirmed=true&frequency=daily", "")
+	assert.Equal(t, http.StatusOK, resp.Code)
+}
+
+func TestValidate_UndocumentedRouteIsSkipped(t *testing.T) {
+	router := setupValidator(t)
+
+	resp, _ := request(router, http.MethodGet, "/api/undocumented", "")
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+}
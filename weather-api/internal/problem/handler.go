@@ -0,0 +1,56 @@
+package problem
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/logger"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/requestid"
+	"github.com/gin-gonic/gin"
+)
+
+// Handler turns the last error recorded by a controller or middleware into
+// a problem details response. It has to run before them in the chain.
+func Handler(logger logger.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		err := c.Errors.Last().Err
+		problem := From(err)
+		problem.Instance = c.Request.URL.Path
+		problem.RequestID = requestid.FromContext(c)
+
+		if problem.Status >= http.StatusInternalServerError {
+			logger.WithContext(c.Request.Context()).Error("Request failed",
+				"path", c.Request.URL.Path,
+				"status", problem.Status,
+				"request_id", problem.RequestID,
+				"error", err)
+		}
+
+		c.Header("Content-Type", ContentType)
+		c.JSON(problem.Status, problem)
+	}
+}
+
+// From builds the problem for err. Errors that weren't mapped become a
+// 500 with a generic detail.
+func From(err error) Problem {
+	var mapped *Error
+	if !errors.As(err, &mapped) {
+		mapped = New(http.StatusInternalServerError, "internal_error", ErrInternal)
+	}
+
+	return Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(mapped.Status),
+		Status: mapped.Status,
+		Detail: mapped.Error(),
+		Code:   mapped.Code,
+		Errors: mapped.Fields,
+	}
+}
@@ -0,0 +1,115 @@
+//go:build unit
+// +build unit
+
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/logger"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/requestid"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serve(t *testing.T, handler gin.HandlerFunc, header http.Header) (*httptest.ResponseRecorder, Problem) {
+	gin.SetMode(gin.TestMode)
+	mockLog, _ := logger.NewTestLogger()
+
+	router := gin.New()
+	router.Use(requestid.Middleware(), Handler(*mockLog))
+	router.GET("/test", handler)
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	for key, values := range header {
+		req.Header.Set(key, values[0])
+	}
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	var body Problem
+	if resp.Header().Get("Content-Type") == ContentType {
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	}
+
+	return resp, body
+}
+
+func TestHandler_MappedError(t *testing.T) {
+	errNotFound := errors.New("token not found")
+
+	resp, body := serve(t, func(c *gin.Context) {
+		Abort(c, http.StatusNotFound, "token_not_found", errNotFound)
+	}, http.Header{requestid.Header: {"req-123"}})
+
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+	assert.Equal(t, ContentType, resp.Header().Get("Content-Type"))
+	assert.Equal(t, Problem{
+		Type:      "about:blank",
+		Title:     "Not Found",
+		Status:    http.StatusNotFound,
+		Detail:    "token not found",
+		Instance:  "/test",
+		Code:      "token_not_found",
+		RequestID: "req-123",
+	}, body)
+	assert.Equal(t, "req-123", resp.Header().Get(requestid.Header))
+}
+
+func TestHandler_ValidationFields(t *testing.T) {
+	resp, body := serve(t, func(c *gin.Context) {
+		AbortValidation(c,
+			FieldError{Field: "email", Code: "required", Message: "email is required"},
+			FieldError{Field: "city", Code: "required", Message: "city is required"})
+	}, nil)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.Equal(t, "validation_failed", body.Code)
+	assert.Len(t, body.Errors, 2)
+	assert.Equal(t, "email", body.Errors[0].Field)
+	assert.NotEmpty(t, body.RequestID)
+}
+
+func TestHandler_UnmappedErrorIsHidden(t *testing.T) {
+	resp, body := serve(t, func(c *gin.Context) {
+		AbortUnmapped(c, errors.New("pq: password authentication failed"))
+	}, nil)
+
+	assert.Equal(t, http.StatusInternalServerError, resp.Code)
+	assert.Equal(t, "internal_error", body.Code)
+	assert.Equal(t, ErrInternal.Error(), body.Detail)
+}
+
+func TestHandler_WrappedError(t *testing.T) {
+	resp, body := serve(t, func(c *gin.Context) {
+		_ = c.Error(errors.Join(errors.New("context"),
+			New(http.StatusServiceUnavailable, "queue_unavailable", errors.New("failed to publish"))))
+	}, nil)
+
+	assert.Equal(t, http.StatusServiceUnavailable, resp.Code)
+	assert.Equal(t, "queue_unavailable", body.Code)
+}
+
+func TestHandler_LeavesWrittenResponses(t *testing.T) {
+	resp, _ := serve(t, func(c *gin.Context) {
+		_ = c.Error(errors.New("logged only"))
+		c.String(http.StatusOK, "ok")
+	}, nil)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "ok", resp.Body.String())
+}
+
+func TestRequestID_RejectsUnsafeHeader(t *testing.T) {
+	resp, body := serve(t, func(c *gin.Context) {
+		AbortValidation(c)
+	}, http.Header{requestid.Header: {"bad id\n"}})
+
+	assert.NotEqual(t, "bad id\n", body.RequestID)
+	assert.Equal(t, body.RequestID, resp.Header().Get(requestid.Header))
+}
@@ -0,0 +1,79 @@
+package problem
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const ContentType = "application/problem+json"
+
+var (
+	ErrValidation  = errors.New("request validation failed")
+	ErrInvalidBody = errors.New("request body is not valid JSON")
+	ErrInternal    = errors.New("internal server error")
+)
+
+// Problem is an RFC 7807 problem details body. Code, RequestID and Errors
+// are extension members.
+type Problem struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Detail    string       `json:"detail,omitempty"`
+	Instance  string       `json:"instance,omitempty"`
+	Code      string       `json:"code"`
+	RequestID string       `json:"request_id,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"`
+}
+
+// FieldError points at one invalid field of the request.
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Error is an error already mapped to a status and a machine-readable code.
+type Error struct {
+	Status int
+	Code   string
+	Err    error
+	Fields []FieldError
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func New(status int, code string, err error, fields ...FieldError) *Error {
+	return &Error{Status: status, Code: code, Err: err, Fields: fields}
+}
+
+// Abort records the mapped error for Handler and stops the chain.
+func Abort(c *gin.Context, status int, code string, err error, fields ...FieldError) {
+	_ = c.Error(New(status, code, err, fields...))
+	c.Abort()
+}
+
+// AbortValidation rejects the request with 400 listing the invalid fields.
+func AbortValidation(c *gin.Context, fields ...FieldError) {
+	Abort(c, http.StatusBadRequest, "validation_failed", ErrValidation, fields...)
+}
+
+// AbortInvalidBody rejects a request whose body couldn't be decoded.
+func AbortInvalidBody(c *gin.Context) {
+	Abort(c, http.StatusBadRequest, "invalid_body", ErrInvalidBody)
+}
+
+// AbortUnmapped records an error no controller mapped; Handler answers it
+// with a 500 that doesn't leak its message.
+func AbortUnmapped(c *gin.Context, err error) {
+	_ = c.Error(err)
+	c.Abort()
+}
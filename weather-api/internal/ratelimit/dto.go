@@ -0,0 +1,11 @@
+package ratelimit
+
+import "time"
+
+// Decision is the outcome of a single Allow call.
+type Decision struct {
+	Allowed    bool
+	Limit      int64
+	Remaining  int64
+	RetryAfter time.Duration
+}
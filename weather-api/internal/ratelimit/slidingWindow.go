@@ -0,0 +1,135 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/logger"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/redis"
+)
+
+type counterStore interface {
+	Incr(ctx context.Context, key string, ttl time.Duration) (int64, error)
+	Decr(ctx context.Context, key string) (int64, error)
+	Get(ctx context.Context, key string, dest interface{}) error
+}
+
+const KeyPrefix = "ratelimit" + redis.Delimeter
+
+// SlidingWindow allows limit requests per key in any window-long period.
+// It keeps a counter per fixed window and weighs the previous window by
+// how much of it still overlaps the sliding one.
+type SlidingWindow struct {
+	name     string
+	limit    int64
+	window   time.Duration
+	counters counterStore
+	now      func() time.Time
+	logger   logger.Logger
+}
+
+// NewSlidingWindow creates a limiter whose counters are namespaced by
+// name; a negative limit allows everything.
+func NewSlidingWindow(name string, limit int64, window time.Duration,
+	counters counterStore, logger logger.Logger) *SlidingWindow {
+	return &SlidingWindow{
+		name:     name,
+		limit:    limit,
+		window:   window,
+		counters: counters,
+		now:      time.Now,
+		logger:   logger,
+	}
+}
+
+// Allow counts a request for key if it fits in the window. Counter errors
+// let the request through so a Redis outage doesn't block the endpoint.
+func (l *SlidingWindow) Allow(ctx context.Context, key string) Decision {
+	if l.limit < 0 {
+		return Decision{Allowed: true, Limit: l.limit, Remaining: -1}
+	}
+
+	now := l.now()
+	index := now.UnixNano() / int64(l.window)
+	elapsed := float64(now.UnixNano()-index*int64(l.window)) / float64(l.window)
+
+	previous, err := l.count(ctx, l.counterKey(key, index-1))
+	if err != nil {
+		return l.failOpen(key, err)
+	}
+
+	// The request is counted before the check, so concurrent requests can't
+	// all pass it before any of them is counted; a refused one is taken back.
+	currentKey := l.counterKey(key, index)
+	current, err := l.counters.Incr(ctx, currentKey, 2*l.window)
+	if err != nil {
+		return l.failOpen(key, err)
+	}
+
+	weighted := float64(previous) * (1 - elapsed)
+	if weighted+float64(current) > float64(l.limit) {
+		if _, err := l.counters.Decr(ctx, currentKey); err != nil {
+			l.logger.WithContext(ctx).Error("Failed to take back refused request",
+				"limiter", l.name, "key", key, "error", err)
+		}
+
+		return Decision{
+			Allowed:    false,
+			Limit:      l.limit,
+			RetryAfter: l.retryAfter(previous, current-1, elapsed),
+		}
+	}
+
+	remaining := l.limit - int64(math.Ceil(weighted)) - current
+
+	return Decision{Allowed: true, Limit: l.limit, Remaining: max(remaining, 0)}
+}
+
+// retryAfter returns how long until the weighted count drops enough to let
+// one more request in, either later in this window or in the next one.
+func (l *SlidingWindow) retryAfter(previous int64, current int64, elapsed float64) time.Duration {
+	room := float64(l.limit - 1)
+
+	var fraction float64
+	switch {
+	case float64(current) <= room && previous > 0:
+		// previous*(1-elapsed-wait) + current <= room
+		fraction = (1 - elapsed) - (room-float64(current))/float64(previous)
+	case current > 0:
+		// the current window becomes the previous one: current*(1-next) <= room
+		fraction = (1 - elapsed) + (1 - room/float64(current))
+	default:
+		fraction = 1 - elapsed
+	}
+
+	wait := time.Duration(fraction * float64(l.window))
+	if wait < time.Second {
+		return time.Second
+	}
+
+	return wait.Round(time.Second)
+}
+
+func (l *SlidingWindow) count(ctx context.Context, key string) (int64, error) {
+	var count int64
+
+	err := l.counters.Get(ctx, key, &count)
+	if err != nil && err.Error() == "redis: nil" {
+		return 0, nil
+	}
+
+	return count, err
+}
+
+func (l *SlidingWindow) counterKey(key string, index int64) string {
+	return KeyPrefix + l.name + redis.Delimeter + key + redis.Delimeter + strconv.FormatInt(index, 10)
+}
+
+func (l *SlidingWindow) failOpen(key string, err error) Decision {
+	l.logger.Error("Failed to read rate limit counter, allowing request",
+		"limiter", l.name, "key", key, "error", err)
+
+	return Decision{Allowed: true, Limit: l.limit, Remaining: -1}
+}
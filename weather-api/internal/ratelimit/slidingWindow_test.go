@@ -0,0 +1,141 @@
+//go:build unit
+// +build unit
+
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeCounters keeps counters in memory.
+type fakeCounters struct {
+	mu     sync.Mutex
+	values map[string]int64
+	err    error
+}
+
+func (f *fakeCounters) Incr(_ context.Context, key string, ttl time.Duration) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return 0, f.err
+	}
+	f.values[key]++
+	return f.values[key], nil
+}
+
+func (f *fakeCounters) Decr(_ context.Context, key string) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.values[key]--
+	return f.values[key], nil
+}
+
+func (f *fakeCounters) Get(_ context.Context, key string, dest interface{}) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return f.err
+	}
+	value, ok := f.values[key]
+	if !ok {
+		return errors.New("redis: nil")
+	}
+	*dest.(*int64) = value
+	return nil
+}
+
+func setupLimiter(limit int64, now time.Time) (*fakeCounters, *SlidingWindow, *time.Time) {
+	counters := &fakeCounters{values: map[string]int64{}}
+	mockLog, _ := logger.NewTestLogger()
+
+	limiter := NewSlidingWindow("test", limit, time.Hour, counters, *mockLog)
+	clock := now
+	limiter.now = func() time.Time { return clock }
+
+	return counters, limiter, &clock
+}
+
+var windowStart = time.Date(2025, time.June, 15, 10, 0, 0, 0, time.UTC)
+
+func TestAllow_UpToLimit(t *testing.T) {
+	_, limiter, _ := setupLimiter(3, windowStart.Add(10*time.Minute))
+
+	for i := 2; i >= 0; i-- {
+		decision := limiter.Allow(context.Background(), "1.2.3.4")
+		assert.True(t, decision.Allowed)
+		assert.Equal(t, int64(i), decision.Remaining)
+	}
+
+	decision := limiter.Allow(context.Background(), "1.2.3.4")
+	assert.False(t, decision.Allowed)
+	assert.Equal(t, 70*time.Minute, decision.RetryAfter)
+
+	assert.True(t, limiter.Allow(context.Background(), "5.6.7.8").Allowed)
+}
+
+func TestAllow_ConcurrentRequestsDontExceedLimit(t *testing.T) {
+	counters, limiter, _ := setupLimiter(5, windowStart.Add(10*time.Minute))
+
+	var allowed atomic.Int64
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if limiter.Allow(context.Background(), "key").Allowed {
+				allowed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int64(5), allowed.Load())
+	// refused requests are not counted
+	assert.Equal(t, int64(5), counters.values[limiter.counterKey("key", windowStart.UnixNano()/int64(time.Hour))])
+}
+
+func TestAllow_PreviousWindowIsWeighted(t *testing.T) {
+	_, limiter, clock := setupLimiter(4, windowStart.Add(50*time.Minute))
+
+	for i := 0; i < 4; i++ {
+		assert.True(t, limiter.Allow(context.Background(), "key").Allowed)
+	}
+
+	// A quarter into the next window, 3 of the previous 4 still count.
+	*clock = windowStart.Add(75 * time.Minute)
+	assert.True(t, limiter.Allow(context.Background(), "key").Allowed)
+
+	decision := limiter.Allow(context.Background(), "key")
+	assert.False(t, decision.Allowed)
+	assert.Equal(t, 15*time.Minute, decision.RetryAfter)
+
+	*clock = windowStart.Add(90 * time.Minute)
+	assert.True(t, limiter.Allow(context.Background(), "key").Allowed)
+}
+
+func TestAllow_NegativeLimitIsUnlimited(t *testing.T) {
+	counters, limiter, _ := setupLimiter(-1, windowStart)
+
+	for i := 0; i < 10; i++ {
+		assert.True(t, limiter.Allow(context.Background(), "key").Allowed)
+	}
+	assert.Empty(t, counters.values)
+}
+
+func TestAllow_FailsOpenWhenCountersAreDown(t *testing.T) {
+	counters, limiter, _ := setupLimiter(1, windowStart)
+	counters.err = errors.New("redis down")
+
+	for i := 0; i < 3; i++ {
+		assert.True(t, limiter.Allow(context.Background(), "key").Allowed)
+	}
+}
@@ -4,26 +4,37 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/logger"
 	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/config"
-	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/logger"
+	"github.com/redis/go-redis/extra/redisotel/v9"
 	"github.com/redis/go-redis/v9"
 )
 
-func ConnectToRedis(ctx context.Context, config config.Config, logger logger.Logger) (*redis.Client, error) {
-	redisClient := redis.NewClient(&redis.Options{
-		Addr:     fmt.Sprintf("%s:%d", config.RedisHost, config.RedisPort),
-		Password: config.RedisPassword,
-		DB:       0,
-	})
+// ConnectWithFallback never fails: when Redis doesn't answer it starts on
+// an in-memory cache and switches to Redis once Monitor sees it up.
+func ConnectWithFallback(ctx context.Context, config config.Config, logger logger.Logger) *FailoverClient {
+	client := newClient(config)
+	// commands run with a request context become spans of its trace
+	if err := redisotel.InstrumentTracing(client, redisotel.WithDBStatement(false)); err != nil {
+		logger.Error("Failed to enable Redis tracing", "error", err)
+	}
+
+	failover := NewFailoverClient(client, NewMemoryClient(config.CacheMemoryEntries),
+		config.RedisRetryInterval, logger)
 
-	// Ping to check Redis connection
-	if _, err := redisClient.Ping(ctx).Result(); err != nil {
-		logger.Error("Failed to connect to Redis", "error", err)
-		redisClient.Close()
-		return nil, err
+	failover.Probe(ctx)
+	if failover.Backend() == BackendMemory {
+		logger.Error("Redis is unavailable, starting with in-memory cache",
+			"host", config.RedisHost, "port", config.RedisPort)
 	}
 
-	logger.Info("Connected to Redis", "host", config.RedisHost, "port", config.RedisPort)
+	return failover
+}
 
-	return redisClient, nil
+func newClient(config config.Config) *redis.Client {
+	return redis.NewClient(&redis.Options{
+		Addr:     fmt.Sprintf("%s:%d", config.RedisHost, config.RedisPort),
+		Password: config.RedisPassword,
+		DB:       0,
+	})
 }
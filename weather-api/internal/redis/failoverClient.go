@@ -0,0 +1,166 @@
+package redis
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/logger"
+	metricP "github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/metrics"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	BackendRedis  = "redis"
+	BackendMemory = "memory"
+)
+
+type redisConnection interface {
+	redisClient
+	Publish(ctx context.Context, channel string, message interface{}) *redis.IntCmd
+	Subscribe(ctx context.Context, channels ...string) *redis.PubSub
+	Close() error
+}
+
+// FailoverClient sends commands to Redis while it answers and to an
+// in-memory cache otherwise. Monitor pings Redis in the background to
+// switch between them.
+type FailoverClient struct {
+	redis      redisConnection
+	memory     *MemoryClient
+	usingRedis atomic.Bool
+	interval   time.Duration
+	logger     logger.Logger
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewFailoverClient starts on the in-memory cache; Probe or Monitor switch
+// to Redis once it answers.
+func NewFailoverClient(redis redisConnection, memory *MemoryClient, interval time.Duration,
+	logger logger.Logger) *FailoverClient {
+	f := &FailoverClient{
+		redis:    redis,
+		memory:   memory,
+		interval: interval,
+		logger:   logger,
+		done:     make(chan struct{}),
+	}
+	f.report(BackendMemory)
+
+	return f
+}
+
+func (f *FailoverClient) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) *redis.StatusCmd {
+	return f.current().Set(ctx, key, value, ttl)
+}
+
+func (f *FailoverClient) Get(ctx context.Context, key string) *redis.StringCmd {
+	return f.current().Get(ctx, key)
+}
+
+func (f *FailoverClient) Del(ctx context.Context, keys ...string) *redis.IntCmd {
+	return f.current().Del(ctx, keys...)
+}
+
+func (f *FailoverClient) Incr(ctx context.Context, key string) *redis.IntCmd {
+	return f.current().Incr(ctx, key)
+}
+
+func (f *FailoverClient) Decr(ctx context.Context, key string) *redis.IntCmd {
+	return f.current().Decr(ctx, key)
+}
+
+func (f *FailoverClient) ExpireNX(ctx context.Context, key string, expiration time.Duration) *redis.BoolCmd {
+	return f.current().ExpireNX(ctx, key, expiration)
+}
+
+func (f *FailoverClient) TxPipelined(ctx context.Context, fn func(redis.Pipeliner) error) ([]redis.Cmder, error) {
+	return f.current().TxPipelined(ctx, fn)
+}
+
+// Ping always pings Redis, so health checks see it's down while the
+// in-memory cache serves.
+func (f *FailoverClient) Ping(ctx context.Context) *redis.StatusCmd {
+	return f.redis.Ping(ctx)
+}
+
+// Publish sends to Redis pub/sub. Without Redis there is nobody to reach,
+// so the message is dropped.
+func (f *FailoverClient) Publish(ctx context.Context, channel string, message interface{}) *redis.IntCmd {
+	if !f.usingRedis.Load() {
+		return redis.NewIntResult(0, nil)
+	}
+	return f.redis.Publish(ctx, channel, message)
+}
+
+// Subscribe subscribes on Redis whatever the backend; the subscription
+// reconnects on its own once Redis is back.
+func (f *FailoverClient) Subscribe(ctx context.Context, channels ...string) *redis.PubSub {
+	return f.redis.Subscribe(ctx, channels...)
+}
+
+// Backend returns BackendRedis or BackendMemory.
+func (f *FailoverClient) Backend() string {
+	if f.usingRedis.Load() {
+		return BackendRedis
+	}
+	return BackendMemory
+}
+
+// Probe pings Redis once and switches backend when its state changed.
+func (f *FailoverClient) Probe(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, f.interval)
+	defer cancel()
+
+	err := f.redis.Ping(ctx).Err()
+
+	switch {
+	case err == nil && !f.usingRedis.Load():
+		// entries written while Redis was away would go stale by the next outage
+		f.memory.Flush()
+		f.usingRedis.Store(true)
+		f.report(BackendRedis)
+		f.logger.Info("Redis is available, using it as cache")
+	case err != nil && f.usingRedis.Load():
+		f.usingRedis.Store(false)
+		f.report(BackendMemory)
+		f.logger.Error("Redis is unavailable, using in-memory cache", "error", err)
+	}
+}
+
+// Monitor probes Redis every interval until Close.
+func (f *FailoverClient) Monitor() {
+	ticker := time.NewTicker(f.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-f.done:
+			return
+		case <-ticker.C:
+			f.Probe(context.Background())
+		}
+	}
+}
+
+func (f *FailoverClient) Close() error {
+	f.closeOnce.Do(func() {
+		close(f.done)
+	})
+	return f.redis.Close()
+}
+
+func (f *FailoverClient) current() redisClient {
+	if f.usingRedis.Load() {
+		return f.redis
+	}
+	return f.memory
+}
+
+func (f *FailoverClient) report(backend string) {
+	metricP.SetCacheBackend(BackendRedis, backend == BackendRedis)
+	metricP.SetCacheBackend(BackendMemory, backend == BackendMemory)
+}
@@ -0,0 +1,68 @@
+//go:build unit
+// +build unit
+
+package redis
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/logger"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeRedis stores like Redis and can be taken down.
+type fakeRedis struct {
+	*MemoryClient
+	down bool
+}
+
+func (f *fakeRedis) Ping(context.Context) *redis.StatusCmd {
+	if f.down {
+		return redis.NewStatusResult("", errors.New("dial tcp: connection refused"))
+	}
+	return redis.NewStatusResult("PONG", nil)
+}
+
+func (f *fakeRedis) Publish(context.Context, string, interface{}) *redis.IntCmd {
+	return redis.NewIntResult(1, nil)
+}
+
+func (f *fakeRedis) Subscribe(context.Context, ...string) *redis.PubSub {
+	return nil
+}
+
+func (f *fakeRedis) Close() error { return nil }
+
+func TestFailoverClient_SwitchesBackends(t *testing.T) {
+	ctx := context.Background()
+	mockLog, _ := logger.NewTestLogger()
+
+	remote := &fakeRedis{MemoryClient: NewMemoryClient(10), down: true}
+	memory := NewMemoryClient(10)
+	failover := NewFailoverClient(remote, memory, time.Second, *mockLog)
+
+	failover.Probe(ctx)
+	assert.Equal(t, BackendMemory, failover.Backend())
+
+	failover.Set(ctx, "weather:Kyiv", "cached while down", 0)
+	assert.Equal(t, 1, memory.Len())
+	assert.Equal(t, 0, remote.Len())
+
+	remote.down = false
+	failover.Probe(ctx)
+	assert.Equal(t, BackendRedis, failover.Backend())
+	assert.Equal(t, 0, memory.Len(), "stale entries are dropped on switching back")
+
+	failover.Set(ctx, "weather:Kyiv", "cached in redis", 0)
+	assert.Equal(t, 1, remote.Len())
+
+	remote.down = true
+	failover.Probe(ctx)
+	assert.Equal(t, BackendMemory, failover.Backend())
+	assert.ErrorIs(t, failover.Get(ctx, "weather:Kyiv").Err(), redis.Nil)
+	assert.Error(t, failover.Ping(ctx).Err(), "Ping reports Redis itself")
+}
@@ -0,0 +1,207 @@
+package redis
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var ErrNotInteger = errors.New("ERR value is not an integer or out of range")
+
+type memoryEntry struct {
+	key       string
+	value     string
+	expiresAt time.Time
+}
+
+// MemoryClient is an in-process LRU cache with TTLs that answers the Redis
+// commands RedisProvider uses, so it can stand in while Redis is down.
+// Misses return redis.Nil like Redis does.
+type MemoryClient struct {
+	mu       sync.Mutex
+	capacity int
+	items    map[string]*list.Element
+	// most recently used first
+	order *list.List
+	now   func() time.Time
+}
+
+func NewMemoryClient(capacity int) *MemoryClient {
+	if capacity < 1 {
+		capacity = 1
+	}
+
+	return &MemoryClient{
+		capacity: capacity,
+		items:    make(map[string]*list.Element),
+		order:    list.New(),
+		now:      time.Now,
+	}
+}
+
+func (m *MemoryClient) Set(_ context.Context, key string, value interface{}, ttl time.Duration) *redis.StatusCmd {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry := &memoryEntry{key: key, value: toString(value)}
+	if ttl > 0 {
+		entry.expiresAt = m.now().Add(ttl)
+	}
+	m.put(entry)
+
+	return redis.NewStatusResult("OK", nil)
+}
+
+func (m *MemoryClient) Get(_ context.Context, key string) *redis.StringCmd {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.lookup(key)
+	if !ok {
+		return redis.NewStringResult("", redis.Nil)
+	}
+
+	return redis.NewStringResult(entry.value, nil)
+}
+
+func (m *MemoryClient) Del(_ context.Context, keys ...string) *redis.IntCmd {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var deleted int64
+	for _, key := range keys {
+		if _, ok := m.lookup(key); ok {
+			m.remove(m.items[key])
+			deleted++
+		}
+	}
+
+	return redis.NewIntResult(deleted, nil)
+}
+
+// Incr increments the counter at key, keeping its expiry like Redis does.
+func (m *MemoryClient) Incr(_ context.Context, key string) *redis.IntCmd {
+	return m.incrBy(key, 1)
+}
+
+func (m *MemoryClient) Decr(_ context.Context, key string) *redis.IntCmd {
+	return m.incrBy(key, -1)
+}
+
+func (m *MemoryClient) incrBy(key string, delta int64) *redis.IntCmd {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.lookup(key)
+	if !ok {
+		m.put(&memoryEntry{key: key, value: strconv.FormatInt(delta, 10)})
+		return redis.NewIntResult(delta, nil)
+	}
+
+	count, err := strconv.ParseInt(entry.value, 10, 64)
+	if err != nil {
+		return redis.NewIntResult(0, ErrNotInteger)
+	}
+
+	count += delta
+	entry.value = strconv.FormatInt(count, 10)
+
+	return redis.NewIntResult(count, nil)
+}
+
+// ExpireNX sets the expiry of key unless it already has one.
+func (m *MemoryClient) ExpireNX(_ context.Context, key string, expiration time.Duration) *redis.BoolCmd {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.lookup(key)
+	if !ok || !entry.expiresAt.IsZero() {
+		return redis.NewBoolResult(false, nil)
+	}
+
+	entry.expiresAt = m.now().Add(expiration)
+
+	return redis.NewBoolResult(true, nil)
+}
+
+// TxPipelined runs the commands fn queues right away, one by one. That's
+// enough for RedisProvider, whose transactions stay correct when another
+// command runs between theirs.
+func (m *MemoryClient) TxPipelined(_ context.Context, fn func(redis.Pipeliner) error) ([]redis.Cmder, error) {
+	return runPipeline(m, fn)
+}
+
+func (m *MemoryClient) Ping(context.Context) *redis.StatusCmd {
+	return redis.NewStatusResult("PONG", nil)
+}
+
+// Flush drops every entry.
+func (m *MemoryClient) Flush() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.items = make(map[string]*list.Element)
+	m.order.Init()
+}
+
+func (m *MemoryClient) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.order.Len()
+}
+
+// lookup returns a live entry and marks it as recently used; expired
+// entries are dropped on access.
+func (m *MemoryClient) lookup(key string) (*memoryEntry, bool) {
+	element, ok := m.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := element.Value.(*memoryEntry)
+	if !entry.expiresAt.IsZero() && !m.now().Before(entry.expiresAt) {
+		m.remove(element)
+		return nil, false
+	}
+
+	m.order.MoveToFront(element)
+
+	return entry, true
+}
+
+func (m *MemoryClient) put(entry *memoryEntry) {
+	if element, ok := m.items[entry.key]; ok {
+		element.Value = entry
+		m.order.MoveToFront(element)
+		return
+	}
+
+	m.items[entry.key] = m.order.PushFront(entry)
+
+	for m.order.Len() > m.capacity {
+		m.remove(m.order.Back())
+	}
+}
+
+func (m *MemoryClient) remove(element *list.Element) {
+	m.order.Remove(element)
+	delete(m.items, element.Value.(*memoryEntry).key)
+}
+
+func toString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,96 @@
+//go:build unit
+// +build unit
+
+package redis
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/logger"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time { return c.now }
+
+func newTestMemoryClient(capacity int) (*MemoryClient, *clock) {
+	c := &clock{now: time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)}
+	m := NewMemoryClient(capacity)
+	m.now = c.Now
+	return m, c
+}
+
+func TestMemoryClient_GetMissIsRedisNil(t *testing.T) {
+	m, _ := newTestMemoryClient(10)
+
+	err := m.Get(context.Background(), "weather:Kyiv").Err()
+
+	// callers tell misses from failures by this message
+	assert.EqualError(t, err, "redis: nil")
+}
+
+func TestMemoryClient_Expires(t *testing.T) {
+	ctx := context.Background()
+	m, c := newTestMemoryClient(10)
+
+	m.Set(ctx, "weather:Kyiv", []byte(`{"temperature":21.5}`), WeatherTTL)
+
+	value, err := m.Get(ctx, "weather:Kyiv").Result()
+	assert.NoError(t, err)
+	assert.Equal(t, `{"temperature":21.5}`, value)
+
+	c.now = c.now.Add(WeatherTTL)
+	assert.ErrorIs(t, m.Get(ctx, "weather:Kyiv").Err(), redis.Nil)
+	assert.Equal(t, 0, m.Len())
+}
+
+func TestMemoryClient_EvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	m, _ := newTestMemoryClient(2)
+
+	m.Set(ctx, "a", "1", 0)
+	m.Set(ctx, "b", "2", 0)
+	m.Get(ctx, "a")
+	m.Set(ctx, "c", "3", 0)
+
+	assert.NoError(t, m.Get(ctx, "a").Err())
+	assert.ErrorIs(t, m.Get(ctx, "b").Err(), redis.Nil)
+	assert.NoError(t, m.Get(ctx, "c").Err())
+}
+
+func TestMemoryClient_IncrKeepsExpiry(t *testing.T) {
+	ctx := context.Background()
+	m, c := newTestMemoryClient(10)
+	mockLog, _ := logger.NewTestLogger()
+	provider := NewRedisProvider(m, *mockLog)
+
+	count, err := provider.Incr(ctx, "quota:anon:1.2.3.4", time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	c.now = c.now.Add(30 * time.Second)
+	count, err = provider.Incr(ctx, "quota:anon:1.2.3.4", time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	c.now = c.now.Add(30 * time.Second)
+	count, err = provider.Incr(ctx, "quota:anon:1.2.3.4", time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestMemoryClient_IncrNotInteger(t *testing.T) {
+	ctx := context.Background()
+	m, _ := newTestMemoryClient(10)
+
+	m.Set(ctx, "weather:Kyiv", "{}", 0)
+
+	assert.True(t, errors.Is(m.Incr(ctx, "weather:Kyiv").Err(), ErrNotInteger))
+}
@@ -0,0 +1,50 @@
+package redis
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type pipelineClient interface {
+	Incr(ctx context.Context, key string) *redis.IntCmd
+	ExpireNX(ctx context.Context, key string, expiration time.Duration) *redis.BoolCmd
+}
+
+// eagerPipeline runs each queued command as soon as it's queued. Only the
+// commands RedisProvider pipelines are supported; the others panic.
+type eagerPipeline struct {
+	redis.Pipeliner
+	client pipelineClient
+	cmds   []redis.Cmder
+}
+
+func (p *eagerPipeline) Incr(ctx context.Context, key string) *redis.IntCmd {
+	cmd := p.client.Incr(ctx, key)
+	p.cmds = append(p.cmds, cmd)
+	return cmd
+}
+
+func (p *eagerPipeline) ExpireNX(ctx context.Context, key string, expiration time.Duration) *redis.BoolCmd {
+	cmd := p.client.ExpireNX(ctx, key, expiration)
+	p.cmds = append(p.cmds, cmd)
+	return cmd
+}
+
+// runPipeline returns the commands fn queued and, like go-redis, the first
+// error among them.
+func runPipeline(client pipelineClient, fn func(redis.Pipeliner) error) ([]redis.Cmder, error) {
+	pipe := &eagerPipeline{client: client}
+	if err := fn(pipe); err != nil {
+		return pipe.cmds, err
+	}
+
+	for _, cmd := range pipe.cmds {
+		if err := cmd.Err(); err != nil {
+			return pipe.cmds, err
+		}
+	}
+
+	return pipe.cmds, nil
+}
@@ -5,7 +5,7 @@ import (
 	"encoding/json"
 	"time"
 
-	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/logger"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/logger"
 	"github.com/redis/go-redis/v9"
 )
 
@@ -13,56 +13,87 @@ type redisClient interface {
 	Set(ctx context.Context, key string, value interface{}, ttl time.Duration) *redis.StatusCmd
 	Get(ctx context.Context, key string) *redis.StringCmd
 	Del(ctx context.Context, keys ...string) *redis.IntCmd
+	Incr(ctx context.Context, key string) *redis.IntCmd
+	Decr(ctx context.Context, key string) *redis.IntCmd
+	ExpireNX(ctx context.Context, key string, expiration time.Duration) *redis.BoolCmd
+	TxPipelined(ctx context.Context, fn func(redis.Pipeliner) error) ([]redis.Cmder, error)
+	Ping(ctx context.Context) *redis.StatusCmd
 }
 
 type RedisProvider struct {
 	rdb    redisClient
-	ctx    context.Context
 	logger logger.Logger
 }
 
-func NewRedisProvider(redis redisClient, ctx context.Context, logger logger.Logger) RedisProvider {
+func NewRedisProvider(redis redisClient, logger logger.Logger) RedisProvider {
 	return RedisProvider{
 		rdb:    redis,
-		ctx:    ctx,
 		logger: logger,
 	}
 }
 
-func (c *RedisProvider) Set(key string, value interface{}) error {
+// Ping checks that Redis answers, for readiness checks.
+func (c *RedisProvider) Ping(ctx context.Context) error {
+	return c.rdb.Ping(ctx).Err()
+}
+
+func (c *RedisProvider) Set(ctx context.Context, key string, value interface{}) error {
 	data, err := json.Marshal(value)
 	if err != nil {
 		return err
 	}
 
-	c.logger.Info("Set to Redis", "key", key)
+	c.logger.WithContext(ctx).Info("Set to Redis", "key", key)
 
-	return c.rdb.Set(c.ctx, key, data, 0).Err()
+	return c.rdb.Set(ctx, key, data, 0).Err()
 }
 
-func (c *RedisProvider) SetWithTTL(key string, value interface{}, ttl time.Duration) error {
+func (c *RedisProvider) SetWithTTL(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
 	data, err := json.Marshal(value)
 	if err != nil {
 		return err
 	}
 
-	c.logger.Info("Set to Redis with TTL", "key", key, "ttl", ttl)
+	c.logger.WithContext(ctx).Info("Set to Redis with TTL", "key", key, "ttl", ttl)
 
-	return c.rdb.Set(c.ctx, key, data, ttl).Err()
+	return c.rdb.Set(ctx, key, data, ttl).Err()
 }
 
-func (c *RedisProvider) Get(key string, dest interface{}) error {
-	data, err := c.rdb.Get(c.ctx, key).Result()
+func (c *RedisProvider) Get(ctx context.Context, key string, dest interface{}) error {
+	data, err := c.rdb.Get(ctx, key).Result()
 	if err != nil {
 		return err
 	}
 
-	c.logger.Info("Get from Redis", "key", key)
+	c.logger.WithContext(ctx).Info("Get from Redis", "key", key)
 
 	return json.Unmarshal([]byte(data), dest)
 }
 
-func (c *RedisProvider) Delete(key ...string) error {
-	c.logger.Info("Delete from Redis", "keys", key)
-	return c.rdb.Del(c.ctx, key...).Err()
+func (c *RedisProvider) Delete(ctx context.Context, key ...string) error {
+	c.logger.WithContext(ctx).Info("Delete from Redis", "keys", key)
+	return c.rdb.Del(ctx, key...).Err()
+}
+
+// Incr increments the counter at key and returns the new value. A new
+// counter expires after ttl; the expiry is set in the same transaction, so
+// a counter can't be left without one.
+func (c *RedisProvider) Incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	var count *redis.IntCmd
+	_, err := c.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		count = pipe.Incr(ctx, key)
+		pipe.ExpireNX(ctx, key, ttl)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return count.Val(), nil
+}
+
+// Decr decrements the counter at key and returns the new value, e.g. to
+// take back a request that was counted but refused.
+func (c *RedisProvider) Decr(ctx context.Context, key string) (int64, error) {
+	return c.rdb.Decr(ctx, key).Result()
 }
@@ -5,3 +5,8 @@ import "time"
 const Delimeter = ":"
 const WeatherKey = "weather" + Delimeter
 const WeatherTTL = time.Minute * 15
+
+const ForecastKey = "forecast" + Delimeter
+const ForecastTTL = time.Hour
+
+const QuotaKey = "quota" + Delimeter
@@ -10,7 +10,7 @@ import (
 	"testing"
 	"time"
 
-	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/logger"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/logger"
 	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -47,6 +47,39 @@ func (m *mockRedisClient) Del(ctx context.Context, keys ...string) *redis.IntCmd
 	return cmd
 }
 
+func (m *mockRedisClient) Incr(ctx context.Context, key string) *redis.IntCmd {
+	args := m.Called(ctx, key)
+	cmd := redis.NewIntCmd(ctx)
+	if err := args.Error(1); err != nil {
+		cmd.SetErr(err)
+	} else {
+		cmd.SetVal(args.Get(0).(int64))
+	}
+	return cmd
+}
+
+func (m *mockRedisClient) Decr(ctx context.Context, key string) *redis.IntCmd {
+	args := m.Called(ctx, key)
+	cmd := redis.NewIntCmd(ctx)
+	if err := args.Error(1); err != nil {
+		cmd.SetErr(err)
+	} else {
+		cmd.SetVal(args.Get(0).(int64))
+	}
+	return cmd
+}
+
+func (m *mockRedisClient) TxPipelined(_ context.Context, fn func(redis.Pipeliner) error) ([]redis.Cmder, error) {
+	return runPipeline(m, fn)
+}
+
+func (m *mockRedisClient) ExpireNX(ctx context.Context, key string, expiration time.Duration) *redis.BoolCmd {
+	args := m.Called(ctx, key, expiration)
+	cmd := redis.NewBoolCmd(ctx)
+	cmd.SetErr(args.Error(0))
+	return cmd
+}
+
 func (m *mockRedisClient) Ping(ctx context.Context) *redis.StatusCmd {
 	cmd := redis.NewStatusCmd(ctx)
 	return cmd
@@ -59,13 +92,13 @@ func TestSet_Success(t *testing.T) {
 	ctx := context.Background()
 
 	mockLog, _ := logger.NewTestLogger()
-	provider := NewRedisProvider(mockClient, ctx, *mockLog)
+	provider := NewRedisProvider(mockClient, *mockLog)
 
 	val := map[string]string{"foo": "bar"}
 	data, _ := json.Marshal(val)
 	mockClient.On("Set", ctx, "key1", data, time.Duration(0)).Return(nil)
 
-	err := provider.Set("key1", val)
+	err := provider.Set(ctx, "key1", val)
 	assert.NoError(t, err)
 	mockClient.AssertExpectations(t)
 }
@@ -75,14 +108,14 @@ func TestSetWithTTL_Success(t *testing.T) {
 	ctx := context.Background()
 
 	mockLog, _ := logger.NewTestLogger()
-	provider := NewRedisProvider(mockClient, ctx, *mockLog)
+	provider := NewRedisProvider(mockClient, *mockLog)
 
 	val := map[string]string{"foo": "bar"}
 	data, _ := json.Marshal(val)
 	ttl := 5 * time.Minute
 	mockClient.On("Set", ctx, "key2", data, ttl).Return(nil)
 
-	err := provider.SetWithTTL("key2", val, ttl)
+	err := provider.SetWithTTL(ctx, "key2", val, ttl)
 	assert.NoError(t, err)
 	mockClient.AssertExpectations(t)
 }
@@ -92,14 +125,14 @@ func TestGet_Success(t *testing.T) {
 	ctx := context.Background()
 
 	mockLog, _ := logger.NewTestLogger()
-	provider := NewRedisProvider(mockClient, ctx, *mockLog)
+	provider := NewRedisProvider(mockClient, *mockLog)
 
 	val := map[string]string{"foo": "bar"}
 	data, _ := json.Marshal(val)
 	mockClient.On("Get", ctx, "key3").Return(string(data), nil)
 
 	var result map[string]string
-	err := provider.Get("key3", &result)
+	err := provider.Get(ctx, "key3", &result)
 	assert.NoError(t, err)
 	assert.Equal(t, val, result)
 	mockClient.AssertExpectations(t)
@@ -110,12 +143,12 @@ func TestGet_Error(t *testing.T) {
 	ctx := context.Background()
 
 	mockLog, _ := logger.NewTestLogger()
-	provider := NewRedisProvider(mockClient, ctx, *mockLog)
+	provider := NewRedisProvider(mockClient, *mockLog)
 
 	mockClient.On("Get", ctx, "key4").Return("", errors.New("not found"))
 
 	var result map[string]string
-	err := provider.Get("key4", &result)
+	err := provider.Get(ctx, "key4", &result)
 	assert.Error(t, err)
 	mockClient.AssertExpectations(t)
 }
@@ -125,11 +158,11 @@ func TestDelete_Success(t *testing.T) {
 	ctx := context.Background()
 
 	mockLog, _ := logger.NewTestLogger()
-	provider := NewRedisProvider(mockClient, ctx, *mockLog)
+	provider := NewRedisProvider(mockClient, *mockLog)
 
 	mockClient.On("Del", ctx, []string{"key5"}).Return(nil)
 
-	err := provider.Delete("key5")
+	err := provider.Delete(ctx, "key5")
 	assert.NoError(t, err)
 	mockClient.AssertExpectations(t)
 }
@@ -139,11 +172,39 @@ func TestDelete_Error(t *testing.T) {
 	ctx := context.Background()
 
 	mockLog, _ := logger.NewTestLogger()
-	provider := NewRedisProvider(mockClient, ctx, *mockLog)
+	provider := NewRedisProvider(mockClient, *mockLog)
 
 	mockClient.On("Del", ctx, []string{"key6"}).Return(errors.New("del error"))
 
-	err := provider.Delete("key6")
+	err := provider.Delete(ctx, "key6")
 	assert.Error(t, err)
 	mockClient.AssertExpectations(t)
 }
+
+func TestIncr_SetsTTLInSameTransaction(t *testing.T) {
+	mockClient := new(mockRedisClient)
+	ctx := context.Background()
+	mockLog, _ := logger.NewTestLogger()
+	provider := NewRedisProvider(mockClient, *mockLog)
+
+	mockClient.On("Incr", ctx, "counter").Return(int64(1), nil).Once()
+	mockClient.On("ExpireNX", ctx, "counter", time.Hour).Return(nil).Once()
+
+	count, err := provider.Incr(ctx, "counter", time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+	mockClient.AssertExpectations(t)
+}
+
+func TestIncr_Error(t *testing.T) {
+	mockClient := new(mockRedisClient)
+	ctx := context.Background()
+	mockLog, _ := logger.NewTestLogger()
+	provider := NewRedisProvider(mockClient, *mockLog)
+
+	mockClient.On("Incr", ctx, "counter").Return(int64(0), errors.New("redis down"))
+	mockClient.On("ExpireNX", ctx, "counter", time.Hour).Return(nil)
+
+	_, err := provider.Incr(ctx, "counter", time.Hour)
+	assert.Error(t, err)
+}
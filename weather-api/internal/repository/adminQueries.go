@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"strings"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/service/subscription"
+	"gorm.io/gorm"
+)
+
+func (r *SubscriptionRepository) FindByID(ctx context.Context, id uint) (*subscription.Subscription, error) {
+	var sub subscription.Subscription
+	err := r.db.WithContext(ctx).First(&sub, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &sub, nil
+}
+
+// Search returns one page of the subscriptions matching filter, newest
+// first, and the number of matches across all pages.
+func (r *SubscriptionRepository) Search(ctx context.Context,
+	filter subscription.Filter) ([]subscription.Subscription, int64, error) {
+	query := applyFilter(r.db.WithContext(ctx).Model(&subscription.Subscription{}), filter)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	query = query.Order("created_at DESC, id DESC")
+	if filter.PageSize > 0 {
+		query = query.Offset(filter.Offset()).Limit(filter.PageSize)
+	}
+
+	var subs []subscription.Subscription
+	if err := query.Find(&subs).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return subs, total, nil
+}
+
+func (r *SubscriptionRepository) CountByCityAndFrequency(ctx context.Context) ([]subscription.CityFrequencyCount, error) {
+	var counts []subscription.CityFrequencyCount
+
+	err := r.db.WithContext(ctx).Model(&subscription.Subscription{}).
+		Select("city, frequency, COUNT(*) AS total, SUM(CASE WHEN confirmed THEN 1 ELSE 0 END) AS confirmed").
+		Group("city, frequency").
+		Order("city, frequency").
+		Scan(&counts).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+func applyFilter(query *gorm.DB, filter subscription.Filter) *gorm.DB {
+	if filter.Email != "" {
+		query = query.Where("LOWER(email) LIKE ?", "%"+escapeLike(strings.ToLower(filter.Email))+"%")
+	}
+	if filter.City != "" {
+		query = query.Where("LOWER(city) = ?", strings.ToLower(filter.City))
+	}
+	if filter.Frequency != "" {
+		query = query.Where("frequency = ?", filter.Frequency)
+	}
+	if filter.Confirmed != nil {
+		query = query.Where("confirmed = ?", *filter.Confirmed)
+	}
+	if filter.CreatedFrom != nil {
+		query = query.Where("created_at >= ?", *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		query = query.Where("created_at < ?", *filter.CreatedTo)
+	}
+
+	return query
+}
+
+// escapeLike makes % and _ in user input match literally.
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/service/apikey"
+	"gorm.io/gorm"
+)
+
+type APIKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewAPIKeyRepository(database *gorm.DB) *APIKeyRepository {
+	return &APIKeyRepository{db: database}
+}
+
+func (r *APIKeyRepository) Create(ctx context.Context, key *apikey.APIKey) error {
+	return r.db.WithContext(ctx).Create(key).Error
+}
+
+func (r *APIKeyRepository) Update(ctx context.Context, key apikey.APIKey) error {
+	return r.db.WithContext(ctx).Save(&key).Error
+}
+
+func (r *APIKeyRepository) FindAll(ctx context.Context) ([]apikey.APIKey, error) {
+	var keys []apikey.APIKey
+	err := r.db.WithContext(ctx).Order("id").Find(&keys).Error
+	if err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (r *APIKeyRepository) FindByID(ctx context.Context, id uint) (*apikey.APIKey, error) {
+	var key apikey.APIKey
+	err := r.db.WithContext(ctx).First(&key, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *APIKeyRepository) FindByHash(ctx context.Context, hash string) (*apikey.APIKey, error) {
+	var key apikey.APIKey
+	err := r.db.WithContext(ctx).Where("hash = ?", hash).First(&key).Error
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/service/subscription"
 	"gorm.io/gorm"
 )
@@ -13,42 +14,74 @@ func NewSubscriptionRepository(database *gorm.DB) *SubscriptionRepository {
 	return &SubscriptionRepository{db: database}
 }
 
-func (r *SubscriptionRepository) Create(sub subscription.Subscription) error {
-	return r.db.Create(&sub).Error
+func (r *SubscriptionRepository) Create(ctx context.Context, sub subscription.Subscription) error {
+	return r.db.WithContext(ctx).Create(&sub).Error
 }
 
-func (r *SubscriptionRepository) Update(sub subscription.Subscription) error {
-	return r.db.Save(&sub).Error
+func (r *SubscriptionRepository) Update(ctx context.Context, sub subscription.Subscription) error {
+	return r.db.WithContext(ctx).Save(&sub).Error
 }
 
-func (r *SubscriptionRepository) FindByToken(token string) (*subscription.Subscription, error) {
+func (r *SubscriptionRepository) FindByToken(ctx context.Context, token string) (*subscription.Subscription, error) {
 	var sub subscription.Subscription
-	err := r.db.Where("token = ?", token).First(&sub).Error
+	err := r.db.WithContext(ctx).Where("token = ?", token).First(&sub).Error
 	if err != nil {
 		return nil, err
 	}
 	return &sub, nil
 }
 
-func (r *SubscriptionRepository) Delete(sub subscription.Subscription) error {
-	return r.db.Unscoped().Delete(&sub).Error
+func (r *SubscriptionRepository) Delete(ctx context.Context, sub subscription.Subscription) error {
+	return r.db.WithContext(ctx).Unscoped().Delete(&sub).Error
 }
 
-func (r *SubscriptionRepository) FindByEmail(email string) (*subscription.Subscription, error) {
+func (r *SubscriptionRepository) FindByEmailAndCity(ctx context.Context, email string,
+	city string) (*subscription.Subscription, error) {
 	var sub subscription.Subscription
-	err := r.db.Where("email = ?", email).First(&sub).Error
+	err := r.db.WithContext(ctx).
+		Where("lower(email) = lower(?) AND lower(city) = lower(?)", email, city).
+		First(&sub).Error
 	if err != nil {
 		return nil, err
 	}
 	return &sub, nil
 }
 
-func (r *SubscriptionRepository) FindByFrequencyAndConfirmation(
+func (r *SubscriptionRepository) FindAllByEmail(ctx context.Context, email string) ([]subscription.Subscription, error) {
+	var subs []subscription.Subscription
+	err := r.db.WithContext(ctx).Where("lower(email) = lower(?)", email).Find(&subs).Error
+	if err != nil {
+		return nil, err
+	}
+	return subs, nil
+}
+
+// DeactivateByEmail unconfirms every subscription of email in a single
+// statement and returns how many were confirmed.
+func (r *SubscriptionRepository) DeactivateByEmail(ctx context.Context, email string) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&subscription.Subscription{}).
+		Where("lower(email) = lower(?) AND confirmed = true", email).
+		Update("confirmed", false)
+	return result.RowsAffected, result.Error
+}
+
+func (r *SubscriptionRepository) FindByFrequencyAndConfirmation(ctx context.Context,
 	freq subscription.Frequency) ([]subscription.Subscription, error) {
 	var subs []subscription.Subscription
-	err := r.db.Where("frequency = ? AND confirmed = true", freq).Find(&subs).Error
+	err := r.db.WithContext(ctx).Where("frequency = ? AND confirmed = true", freq).Find(&subs).Error
 	if err != nil {
 		return nil, err
 	}
 	return subs, nil
 }
+
+// UpdateLastSent only writes the last sent weather columns, so it cannot
+// undo a concurrent confirmation or unsubscribe.
+func (r *SubscriptionRepository) UpdateLastSent(ctx context.Context, id uint, sent subscription.SentWeather) error {
+	return r.db.WithContext(ctx).Model(&subscription.Subscription{}).Where("id = ?", id).Updates(map[string]any{
+		"last_sent_temperature": sent.Temperature,
+		"last_sent_humidity":    sent.Humidity,
+		"last_sent_description": sent.Description,
+		"last_sent_at":          sent.At,
+	}).Error
+}
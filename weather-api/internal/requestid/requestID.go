@@ -0,0 +1,47 @@
+package requestid
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const Header = "X-Request-ID"
+
+const contextKey = "request_id"
+
+const maxLength = 128
+
+// Middleware tags every request with an id, taken from the X-Request-ID
+// header when the caller sent a sensible one, and echoes it back.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(Header)
+		if !valid(id) {
+			id = uuid.New().String()
+		}
+
+		c.Set(contextKey, id)
+		c.Header(Header, id)
+
+		c.Next()
+	}
+}
+
+// FromContext returns the id set by Middleware, or "".
+func FromContext(c *gin.Context) string {
+	return c.GetString(contextKey)
+}
+
+func valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+
+	for _, r := range id {
+		if r < '!' || r > '~' {
+			return false
+		}
+	}
+
+	return true
+}
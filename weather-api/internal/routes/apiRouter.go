@@ -1,21 +1,60 @@
 package routes
 
 import (
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/health"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/service/admin"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/service/apikey"
 	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/service/subscription"
 	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/service/weather"
 	"github.com/gin-gonic/gin"
 )
 
-func WeatherRoute(router *gin.RouterGroup, weatherController *weather.WeatherController) {
+func WeatherRoute(router *gin.RouterGroup, weatherController *weather.WeatherController,
+	middleware ...gin.HandlerFunc) {
 
-	router.GET("/weather", weatherController.GetWeather)
+	router.GET("/weather", append(middleware, weatherController.GetWeather)...)
 
 }
 
-func SubscribeRoute(router *gin.RouterGroup, subscribeController *subscription.SubscribeController) {
+func UsageRoute(router *gin.RouterGroup, apiKeyController *apikey.APIKeyController) {
 
-	router.POST("/subscribe", subscribeController.SubscribeForWeatherUpdates)
+	router.GET("/usage", apiKeyController.CurrentUsage)
+
+}
+
+func SubscribeRoute(router *gin.RouterGroup, subscribeController *subscription.SubscribeController,
+	subscribeMiddleware ...gin.HandlerFunc) {
+
+	router.POST("/subscribe", append(subscribeMiddleware, subscribeController.SubscribeForWeatherUpdates)...)
 	router.GET("/confirm/:token", subscribeController.ConfirmSubscription)
-	router.GET("/unsubscribe/:token", subscribeController.Unsubscribe)
+	router.GET("/unsubscribe/:token", subscribeController.ConfirmUnsubscribe)
+	router.POST("/unsubscribe/:token", subscribeController.Unsubscribe)
+
+}
+
+func AdminRoute(router *gin.RouterGroup, adminController *admin.AdminController) {
+
+	router.GET("/subscriptions", adminController.ListSubscriptions)
+	router.GET("/subscriptions/export", adminController.ExportSubscriptions)
+	router.POST("/subscriptions/:id/confirm", adminController.ConfirmSubscription)
+	router.DELETE("/subscriptions/:id", adminController.DeleteSubscription)
+	router.GET("/stats", adminController.Counts)
+	router.POST("/dispatch", adminController.Dispatch)
+
+}
+
+func APIKeyRoute(router *gin.RouterGroup, apiKeyController *apikey.APIKeyController) {
+
+	router.GET("/api-keys", apiKeyController.List)
+	router.POST("/api-keys", apiKeyController.Create)
+	router.DELETE("/api-keys/:id", apiKeyController.Revoke)
+	router.GET("/api-keys/:id/usage", apiKeyController.Usage)
+
+}
+
+func HealthRoute(router *gin.Engine, healthController *health.HealthController) {
+
+	router.GET("/healthz", healthController.Liveness)
+	router.GET("/readyz", healthController.Readiness)
 
 }
@@ -1,33 +1,70 @@
 package scheduler
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/logger"
 	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/service/subscription"
-	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/logger"
 	"github.com/robfig/cron/v3"
 )
 
 type subscribeService interface {
-	SendSubscriptionEmails(freq subscription.Frequency)
+	SendSubscriptionEmails(ctx context.Context, freq subscription.Frequency)
+}
+
+type cacheWarmer interface {
+	WarmUp(ctx context.Context, freq subscription.Frequency) int
 }
 
+// The daily emails go out at dailyHour, the hourly ones on the hour.
+const dailyHour = 9
+
+var ErrStopped = errors.New("scheduler stopped")
+
 type Scheduler struct {
 	subscribeService subscribeService
+	cacheWarmer      cacheWarmer
+	warmUpLead       time.Duration
 	logger           logger.Logger
+	cron             *cron.Cron
+
+	// jobs run with ctx, cancelled when Stop gives up waiting on them
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// dispatches started by Dispatch, outside the cron schedule
+	mu       sync.Mutex
+	stopped  bool
+	triggers sync.WaitGroup
 }
 
-func NewScheduler(subscribeService subscribeService, logger logger.Logger) *Scheduler {
+// NewScheduler warms the weather cache up warmUpLead before every dispatch;
+// a lead that isn't between a minute and an hour disables the warm-up.
+func NewScheduler(subscribeService subscribeService, cacheWarmer cacheWarmer, warmUpLead time.Duration,
+	logger logger.Logger) *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+
 	return &Scheduler{
 		subscribeService: subscribeService,
+		cacheWarmer:      cacheWarmer,
+		warmUpLead:       warmUpLead,
 		logger:           logger,
+		cron:             cron.New(),
+		ctx:              ctx,
+		cancel:           cancel,
 	}
 }
 
 func (ss *Scheduler) StartCronJobs() {
-	c := cron.New()
+	c := ss.cron
 
 	// at 9 oclock
-	if _, err := c.AddFunc("0 9 * * *", func() {
-		ss.subscribeService.SendSubscriptionEmails(subscription.FrequencyDaily)
+	if _, err := c.AddFunc(fmt.Sprintf("0 %d * * *", dailyHour), func() {
+		ss.subscribeService.SendSubscriptionEmails(ss.ctx, subscription.FrequencyDaily)
 	}); err != nil {
 		ss.logger.Error("Failed to schedule daily job", "error", err)
 
@@ -35,10 +72,89 @@ func (ss *Scheduler) StartCronJobs() {
 
 	// Every hour
 	if _, err := c.AddFunc("0 * * * *", func() {
-		ss.subscribeService.SendSubscriptionEmails(subscription.FrequencyHourly)
+		ss.subscribeService.SendSubscriptionEmails(ss.ctx, subscription.FrequencyHourly)
 	}); err != nil {
 		ss.logger.Error("Failed to schedule hourly job", "error", err)
 	}
 
+	ss.scheduleWarmUp(c)
+
 	c.Start()
 }
+
+func (ss *Scheduler) scheduleWarmUp(c *cron.Cron) {
+	hourlySpec, dailySpec, ok := warmUpSpecs(ss.warmUpLead)
+	if !ok {
+		ss.logger.Info("Cache warm-up disabled", "lead", ss.warmUpLead)
+		return
+	}
+
+	if _, err := c.AddFunc(dailySpec, func() {
+		ss.cacheWarmer.WarmUp(ss.ctx, subscription.FrequencyDaily)
+	}); err != nil {
+		ss.logger.Error("Failed to schedule daily warm-up", "error", err)
+	}
+
+	if _, err := c.AddFunc(hourlySpec, func() {
+		ss.cacheWarmer.WarmUp(ss.ctx, subscription.FrequencyHourly)
+	}); err != nil {
+		ss.logger.Error("Failed to schedule hourly warm-up", "error", err)
+	}
+}
+
+// warmUpSpecs returns the cron specs running lead before the hourly and
+// the daily dispatch, in whole minutes.
+func warmUpSpecs(lead time.Duration) (hourly string, daily string, ok bool) {
+	minutes := int(lead / time.Minute)
+	if minutes < 1 || minutes > 59 {
+		return "", "", false
+	}
+
+	minute := 60 - minutes
+
+	return fmt.Sprintf("%d * * * *", minute), fmt.Sprintf("%d %d * * *", minute, dailyHour-1), true
+}
+
+// Dispatch starts the emails of freq now, in the background. Stop waits
+// for it like for the scheduled runs.
+func (ss *Scheduler) Dispatch(freq subscription.Frequency) error {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	if ss.stopped {
+		return ErrStopped
+	}
+
+	ss.triggers.Add(1)
+	go func() {
+		defer ss.triggers.Done()
+		ss.subscribeService.SendSubscriptionEmails(ss.ctx, freq)
+	}()
+
+	return nil
+}
+
+// Stop stops scheduling new runs and waits for the running ones, such as
+// an hourly SendSubscriptionEmails, to finish. When ctx expires first, the
+// running jobs are cancelled.
+func (ss *Scheduler) Stop(ctx context.Context) error {
+	defer ss.cancel()
+
+	ss.mu.Lock()
+	ss.stopped = true
+	ss.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		<-ss.cron.Stop().Done()
+		ss.triggers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -4,11 +4,14 @@
 package scheduler
 
 import (
+	"context"
 	"testing"
+	"time"
 
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/logger"
 	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/service/subscription"
-	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/logger"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -18,10 +21,19 @@ type mockSubscribeService struct {
 	mock.Mock
 }
 
-func (m *mockSubscribeService) SendSubscriptionEmails(freq subscription.Frequency) {
+func (m *mockSubscribeService) SendSubscriptionEmails(_ context.Context, freq subscription.Frequency) {
 	m.Called(freq)
 }
 
+type mockCacheWarmer struct {
+	mock.Mock
+}
+
+func (m *mockCacheWarmer) WarmUp(_ context.Context, freq subscription.Frequency) int {
+	args := m.Called(freq)
+	return args.Int(0)
+}
+
 // --- Tests ---
 
 func TestStartCronJobs_SchedulesJobs(t *testing.T) {
@@ -33,14 +45,81 @@ func TestStartCronJobs_SchedulesJobs(t *testing.T) {
 
 	mockLog, _ := logger.NewTestLogger()
 
-	scheduler := NewScheduler(mockService, *mockLog) // Assuming constructor exists
+	scheduler := NewScheduler(mockService, new(mockCacheWarmer), 5*time.Minute, *mockLog)
 	scheduler.StartCronJobs()
 
-	mockService.SendSubscriptionEmails(subscription.FrequencyDaily)
-	mockService.SendSubscriptionEmails(subscription.FrequencyHourly)
+	mockService.SendSubscriptionEmails(context.Background(), subscription.FrequencyDaily)
+	mockService.SendSubscriptionEmails(context.Background(), subscription.FrequencyHourly)
 
 	// Assert expectations
 	mockService.AssertCalled(t, "SendSubscriptionEmails", subscription.FrequencyDaily)
 	mockService.AssertCalled(t, "SendSubscriptionEmails", subscription.FrequencyHourly)
 	mockService.AssertExpectations(t)
 }
+
+func TestStop_WaitsForRunningJob(t *testing.T) {
+	mockLog, _ := logger.NewTestLogger()
+	scheduler := NewScheduler(new(mockSubscribeService), new(mockCacheWarmer), 0, *mockLog)
+
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	_, err := scheduler.cron.AddFunc("@every 1s", func() {
+		started <- struct{}{}
+		<-release
+	})
+	assert.NoError(t, err)
+	scheduler.cron.Start()
+
+	select {
+	case <-started:
+	case <-time.After(3 * time.Second):
+		t.Fatal("job did not start")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, scheduler.Stop(ctx), context.DeadlineExceeded)
+	assert.ErrorIs(t, scheduler.ctx.Err(), context.Canceled, "jobs still running are cancelled")
+
+	close(release)
+	assert.NoError(t, scheduler.Stop(context.Background()))
+}
+
+func TestDispatch_StopWaitsForIt(t *testing.T) {
+	mockService := new(mockSubscribeService)
+	mockLog, _ := logger.NewTestLogger()
+	scheduler := NewScheduler(mockService, new(mockCacheWarmer), 0, *mockLog)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	mockService.On("SendSubscriptionEmails", subscription.FrequencyHourly).
+		Run(func(mock.Arguments) {
+			close(started)
+			<-release
+		}).Return()
+
+	assert.NoError(t, scheduler.Dispatch(subscription.FrequencyHourly))
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, scheduler.Stop(ctx), context.DeadlineExceeded)
+	assert.ErrorIs(t, scheduler.Dispatch(subscription.FrequencyDaily), ErrStopped)
+
+	close(release)
+	assert.NoError(t, scheduler.Stop(context.Background()))
+	mockService.AssertNotCalled(t, "SendSubscriptionEmails", subscription.FrequencyDaily)
+}
+
+func TestWarmUpSpecs(t *testing.T) {
+	hourly, daily, ok := warmUpSpecs(5 * time.Minute)
+	assert.True(t, ok)
+	assert.Equal(t, "55 * * * *", hourly)
+	assert.Equal(t, "55 8 * * *", daily)
+
+	_, _, ok = warmUpSpecs(-time.Minute)
+	assert.False(t, ok)
+
+	_, _, ok = warmUpSpecs(time.Hour)
+	assert.False(t, ok)
+}
@@ -0,0 +1,139 @@
+package admin
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/problem"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/service/subscription"
+	"github.com/gin-gonic/gin"
+)
+
+type adminService interface {
+	ListSubscriptions(ctx context.Context, filter subscription.Filter) (*SubscriptionPage, error)
+	ExportSubscriptions(ctx context.Context, filter subscription.Filter) ([]SubscriptionView, error)
+	ConfirmSubscription(ctx context.Context, id uint) (*SubscriptionView, error)
+	DeleteSubscription(ctx context.Context, id uint) error
+	Counts(ctx context.Context) ([]subscription.CityFrequencyCount, error)
+	Dispatch(ctx context.Context, request DispatchRequest) error
+}
+
+type AdminController struct {
+	service adminService
+}
+
+func NewAdminController(service adminService) *AdminController {
+	return &AdminController{service: service}
+}
+
+func (ac *AdminController) ListSubscriptions(c *gin.Context) {
+	filter, err := parseFilter(c)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	page, err := ac.service.ListSubscriptions(c.Request.Context(), filter)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+func (ac *AdminController) ExportSubscriptions(c *gin.Context) {
+	filter, err := parseFilter(c)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	subs, err := ac.service.ExportSubscriptions(c.Request.Context(), filter)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="subscriptions.csv"`)
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Status(http.StatusOK)
+
+	if err := writeCSV(c.Writer, subs); err != nil {
+		_ = c.Error(err)
+	}
+}
+
+func (ac *AdminController) ConfirmSubscription(c *gin.Context) {
+	id, err := parseID(c)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	sub, err := ac.service.ConfirmSubscription(c.Request.Context(), id)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, sub)
+}
+
+func (ac *AdminController) DeleteSubscription(c *gin.Context) {
+	id, err := parseID(c)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	if err := ac.service.DeleteSubscription(c.Request.Context(), id); err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (ac *AdminController) Counts(c *gin.Context) {
+	counts, err := ac.service.Counts(c.Request.Context())
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, counts)
+}
+
+// Dispatch answers 202 for a frequency, whose emails are sent in the
+// background, and 200 once a single address has been handled.
+func (ac *AdminController) Dispatch(c *gin.Context) {
+	var request DispatchRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		problem.AbortInvalidBody(c)
+		return
+	}
+
+	if err := ac.service.Dispatch(c.Request.Context(), request); err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	if request.Email != "" {
+		c.String(http.StatusOK, "Weather emails sent.")
+		return
+	}
+
+	c.String(http.StatusAccepted, "Weather emails dispatch started.")
+}
+
+func parseID(c *gin.Context) (uint, error) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 0)
+	if err != nil || id == 0 {
+		return 0, ErrInvalidID
+	}
+
+	return uint(id), nil
+}
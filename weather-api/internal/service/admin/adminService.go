@@ -0,0 +1,189 @@
+package admin
+
+import (
+	"context"
+	"errors"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/logger"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/service/subscription"
+	"gorm.io/gorm"
+)
+
+type subscriptionRepository interface {
+	Search(ctx context.Context, filter subscription.Filter) ([]subscription.Subscription, int64, error)
+	FindByID(ctx context.Context, id uint) (*subscription.Subscription, error)
+	Update(ctx context.Context, sub subscription.Subscription) error
+	Delete(ctx context.Context, sub subscription.Subscription) error
+	CountByCityAndFrequency(ctx context.Context) ([]subscription.CityFrequencyCount, error)
+}
+
+type emailDispatcher interface {
+	SendSubscriberEmails(ctx context.Context, email string) error
+}
+
+// jobRunner runs the dispatch of a whole frequency in the background, so
+// that shutdown waits for it.
+type jobRunner interface {
+	Dispatch(freq subscription.Frequency) error
+}
+
+type AdminService struct {
+	repository subscriptionRepository
+	dispatcher emailDispatcher
+	jobs       jobRunner
+	logger     logger.Logger
+}
+
+func NewAdminService(repository subscriptionRepository, dispatcher emailDispatcher,
+	jobs jobRunner, logger logger.Logger) *AdminService {
+	return &AdminService{
+		repository: repository,
+		dispatcher: dispatcher,
+		jobs:       jobs,
+		logger:     logger,
+	}
+}
+
+func (as *AdminService) ListSubscriptions(ctx context.Context,
+	filter subscription.Filter) (*SubscriptionPage, error) {
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.PageSize <= 0 {
+		filter.PageSize = defaultPageSize
+	}
+	if filter.PageSize > maxPageSize {
+		filter.PageSize = maxPageSize
+	}
+
+	subs, total, err := as.repository.Search(ctx, filter)
+	if err != nil {
+		as.logger.WithContext(ctx).Error("Failed to search subscriptions", "error", err)
+		return nil, ErrFailedToLoad
+	}
+
+	items := make([]SubscriptionView, 0, len(subs))
+	for _, sub := range subs {
+		items = append(items, newSubscriptionView(sub))
+	}
+
+	return &SubscriptionPage{
+		Items:    items,
+		Total:    total,
+		Page:     filter.Page,
+		PageSize: filter.PageSize,
+	}, nil
+}
+
+// ExportSubscriptions returns every subscription matching filter,
+// ignoring its paging.
+func (as *AdminService) ExportSubscriptions(ctx context.Context,
+	filter subscription.Filter) ([]SubscriptionView, error) {
+	filter.Page = 1
+	filter.PageSize = 0
+
+	subs, _, err := as.repository.Search(ctx, filter)
+	if err != nil {
+		as.logger.WithContext(ctx).Error("Failed to export subscriptions", "error", err)
+		return nil, ErrFailedToLoad
+	}
+
+	views := make([]SubscriptionView, 0, len(subs))
+	for _, sub := range subs {
+		views = append(views, newSubscriptionView(sub))
+	}
+
+	return views, nil
+}
+
+// ConfirmSubscription confirms a subscription without sending the
+// confirmation emails.
+func (as *AdminService) ConfirmSubscription(ctx context.Context, id uint) (*SubscriptionView, error) {
+	sub, err := as.findByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !sub.Confirmed {
+		sub.Confirmed = true
+
+		if err := as.repository.Update(ctx, *sub); err != nil {
+			as.logger.WithContext(ctx).Error("Failed to confirm subscription", "id", id, "error", err)
+			return nil, ErrFailedToSave
+		}
+
+		as.logger.WithContext(ctx).Info("Subscription confirmed by admin", "id", id, "email", sub.Email)
+	}
+
+	view := newSubscriptionView(*sub)
+	return &view, nil
+}
+
+func (as *AdminService) DeleteSubscription(ctx context.Context, id uint) error {
+	sub, err := as.findByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := as.repository.Delete(ctx, *sub); err != nil {
+		as.logger.WithContext(ctx).Error("Failed to delete subscription", "id", id, "error", err)
+		return ErrFailedToSave
+	}
+
+	as.logger.WithContext(ctx).Info("Subscription deleted by admin", "id", id, "email", sub.Email)
+
+	return nil
+}
+
+func (as *AdminService) Counts(ctx context.Context) ([]subscription.CityFrequencyCount, error) {
+	counts, err := as.repository.CountByCityAndFrequency(ctx)
+	if err != nil {
+		as.logger.WithContext(ctx).Error("Failed to count subscriptions", "error", err)
+		return nil, ErrFailedToLoad
+	}
+
+	return counts, nil
+}
+
+// Dispatch sends the weather emails of a single address right away, or
+// starts the dispatch of a whole frequency in the background, as the
+// scheduler would.
+func (as *AdminService) Dispatch(ctx context.Context, request DispatchRequest) error {
+	if request.Email != "" {
+		as.logger.WithContext(ctx).Info("Admin triggered dispatch", "email", request.Email)
+		return as.dispatcher.SendSubscriberEmails(ctx, request.Email)
+	}
+
+	if request.Frequency == "" {
+		return ErrInvalidDispatch
+	}
+
+	freq, err := subscription.ParseFrequency(request.Frequency)
+	if err != nil {
+		return ErrInvalidDispatch
+	}
+
+	as.logger.WithContext(ctx).Info("Admin triggered dispatch", "frequency", freq)
+
+	if err := as.jobs.Dispatch(freq); err != nil {
+		as.logger.WithContext(ctx).Error("Failed to start dispatch", "frequency", freq, "error", err)
+		return ErrDispatchUnavailable
+	}
+
+	return nil
+}
+
+func (as *AdminService) findByID(ctx context.Context, id uint) (*subscription.Subscription, error) {
+	sub, err := as.repository.FindByID(ctx, id)
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrSubscriptionNotFound
+	}
+
+	if err != nil {
+		as.logger.WithContext(ctx).Error("Failed to load subscription", "id", id, "error", err)
+		return nil, ErrFailedToLoad
+	}
+
+	return sub, nil
+}
@@ -0,0 +1,227 @@
+//go:build unit
+// +build unit
+
+package admin
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/logger"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/service/subscription"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// --- Mocks ---
+
+type mockRepository struct {
+	mock.Mock
+}
+
+func (m *mockRepository) Search(_ context.Context, filter subscription.Filter) ([]subscription.Subscription, int64, error) {
+	args := m.Called(filter)
+	subs, _ := args.Get(0).([]subscription.Subscription)
+	return subs, args.Get(1).(int64), args.Error(2)
+}
+
+func (m *mockRepository) FindByID(_ context.Context, id uint) (*subscription.Subscription, error) {
+	args := m.Called(id)
+	sub, _ := args.Get(0).(*subscription.Subscription)
+	return sub, args.Error(1)
+}
+
+func (m *mockRepository) Update(_ context.Context, sub subscription.Subscription) error {
+	return m.Called(sub).Error(0)
+}
+
+func (m *mockRepository) Delete(_ context.Context, sub subscription.Subscription) error {
+	return m.Called(sub).Error(0)
+}
+
+func (m *mockRepository) CountByCityAndFrequency(context.Context) ([]subscription.CityFrequencyCount, error) {
+	args := m.Called()
+	counts, _ := args.Get(0).([]subscription.CityFrequencyCount)
+	return counts, args.Error(1)
+}
+
+type mockDispatcher struct {
+	mock.Mock
+}
+
+func (m *mockDispatcher) Dispatch(freq subscription.Frequency) error {
+	return m.Called(freq).Error(0)
+}
+
+func (m *mockDispatcher) SendSubscriberEmails(_ context.Context, email string) error {
+	return m.Called(email).Error(0)
+}
+
+func setupAdminTest() (*mockRepository, *mockDispatcher, *AdminService) {
+	repo := new(mockRepository)
+	dispatcher := new(mockDispatcher)
+	mockLog, _ := logger.NewTestLogger()
+
+	return repo, dispatcher, NewAdminService(repo, dispatcher, dispatcher, *mockLog)
+}
+
+func newSubscription(id uint, email string, confirmed bool) subscription.Subscription {
+	sub := subscription.Subscription{Email: email, City: "Kyiv", Frequency: subscription.FrequencyDaily,
+		Token: "secret-token", Confirmed: confirmed}
+	sub.ID = id
+	return sub
+}
+
+// --- Tests ---
+
+func TestListSubscriptions_DefaultsAndClampsPaging(t *testing.T) {
+	repo, _, service := setupAdminTest()
+
+	repo.On("Search", mock.MatchedBy(func(f subscription.Filter) bool {
+		return f.Page == 1 && f.PageSize == defaultPageSize
+	})).Return([]subscription.Subscription{newSubscription(1, "a@example.com", true)}, int64(1), nil).Once()
+	repo.On("Search", mock.MatchedBy(func(f subscription.Filter) bool {
+		return f.Page == 3 && f.PageSize == maxPageSize
+	})).Return([]subscription.Subscription{}, int64(1), nil).Once()
+
+	page, err := service.ListSubscriptions(context.Background(), subscription.Filter{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), page.Total)
+	assert.Equal(t, "a@example.com", page.Items[0].Email)
+
+	page, err = service.ListSubscriptions(context.Background(), subscription.Filter{Page: 3, PageSize: 10000})
+	require.NoError(t, err)
+	assert.Empty(t, page.Items)
+	assert.Equal(t, maxPageSize, page.PageSize)
+	repo.AssertExpectations(t)
+}
+
+func TestListSubscriptions_RepositoryError(t *testing.T) {
+	repo, _, service := setupAdminTest()
+	repo.On("Search", mock.Anything).Return(nil, int64(0), errors.New("db down"))
+
+	_, err := service.ListSubscriptions(context.Background(), subscription.Filter{})
+	assert.ErrorIs(t, err, ErrFailedToLoad)
+}
+
+func TestExportSubscriptions_IgnoresPaging(t *testing.T) {
+	repo, _, service := setupAdminTest()
+	repo.On("Search", mock.MatchedBy(func(f subscription.Filter) bool {
+		return f.PageSize == 0 && f.City == "Kyiv"
+	})).Return([]subscription.Subscription{newSubscription(1, "a@example.com", true)}, int64(1), nil)
+
+	views, err := service.ExportSubscriptions(context.Background(), subscription.Filter{City: "Kyiv", Page: 2, PageSize: 10})
+	require.NoError(t, err)
+	assert.Len(t, views, 1)
+}
+
+func TestConfirmSubscription(t *testing.T) {
+	repo, _, service := setupAdminTest()
+	sub := newSubscription(7, "a@example.com", false)
+
+	repo.On("FindByID", uint(7)).Return(&sub, nil)
+	repo.On("Update", mock.MatchedBy(func(s subscription.Subscription) bool {
+		return s.ID == 7 && s.Confirmed
+	})).Return(nil).Once()
+
+	view, err := service.ConfirmSubscription(context.Background(), 7)
+	require.NoError(t, err)
+	assert.True(t, view.Confirmed)
+	repo.AssertExpectations(t)
+}
+
+func TestConfirmSubscription_AlreadyConfirmed(t *testing.T) {
+	repo, _, service := setupAdminTest()
+	sub := newSubscription(7, "a@example.com", true)
+	repo.On("FindByID", uint(7)).Return(&sub, nil)
+
+	_, err := service.ConfirmSubscription(context.Background(), 7)
+	require.NoError(t, err)
+	repo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestConfirmSubscription_NotFound(t *testing.T) {
+	repo, _, service := setupAdminTest()
+	repo.On("FindByID", uint(9)).Return(nil, gorm.ErrRecordNotFound)
+
+	_, err := service.ConfirmSubscription(context.Background(), 9)
+	assert.ErrorIs(t, err, ErrSubscriptionNotFound)
+}
+
+func TestDeleteSubscription(t *testing.T) {
+	repo, _, service := setupAdminTest()
+	sub := newSubscription(3, "a@example.com", true)
+	repo.On("FindByID", uint(3)).Return(&sub, nil)
+	repo.On("Delete", sub).Return(nil).Once()
+
+	require.NoError(t, service.DeleteSubscription(context.Background(), 3))
+	repo.AssertExpectations(t)
+}
+
+func TestDeleteSubscription_Error(t *testing.T) {
+	repo, _, service := setupAdminTest()
+	sub := newSubscription(3, "a@example.com", true)
+	repo.On("FindByID", uint(3)).Return(&sub, nil)
+	repo.On("Delete", sub).Return(errors.New("db down"))
+
+	assert.ErrorIs(t, service.DeleteSubscription(context.Background(), 3), ErrFailedToSave)
+}
+
+func TestCounts(t *testing.T) {
+	repo, _, service := setupAdminTest()
+	expected := []subscription.CityFrequencyCount{{City: "Kyiv", Frequency: subscription.FrequencyDaily, Total: 3, Confirmed: 2}}
+	repo.On("CountByCityAndFrequency").Return(expected, nil)
+
+	counts, err := service.Counts(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, expected, counts)
+}
+
+func TestDispatch_Frequency(t *testing.T) {
+	_, dispatcher, service := setupAdminTest()
+
+	dispatcher.On("Dispatch", subscription.FrequencyHourly).Return(nil).Once()
+	dispatcher.On("Dispatch", subscription.FrequencyDaily).Return(errors.New("scheduler stopped")).Once()
+
+	require.NoError(t, service.Dispatch(context.Background(), DispatchRequest{Frequency: "hourly"}))
+	assert.ErrorIs(t, service.Dispatch(context.Background(), DispatchRequest{Frequency: "daily"}),
+		ErrDispatchUnavailable)
+	dispatcher.AssertExpectations(t)
+}
+
+func TestDispatch_Subscriber(t *testing.T) {
+	_, dispatcher, service := setupAdminTest()
+	dispatcher.On("SendSubscriberEmails", "a@example.com").Return(subscription.ErrSubscriberNotFound)
+
+	err := service.Dispatch(context.Background(), DispatchRequest{Email: "a@example.com", Frequency: "daily"})
+	assert.ErrorIs(t, err, subscription.ErrSubscriberNotFound)
+	dispatcher.AssertNotCalled(t, "Dispatch", mock.Anything)
+}
+
+func TestDispatch_Invalid(t *testing.T) {
+	_, _, service := setupAdminTest()
+
+	assert.ErrorIs(t, service.Dispatch(context.Background(), DispatchRequest{}), ErrInvalidDispatch)
+	assert.ErrorIs(t, service.Dispatch(context.Background(), DispatchRequest{Frequency: "weekly"}), ErrInvalidDispatch)
+}
+
+func TestWriteCSV(t *testing.T) {
+	sentAt := time.Date(2025, time.June, 1, 9, 0, 0, 0, time.UTC)
+	sub := newSubscription(1, "a@example.com", true)
+	sub.City = "Kyiv, Center"
+	sub.Language = subscription.LanguageEnglish
+	sub.CreatedAt = time.Date(2025, time.May, 30, 12, 0, 0, 0, time.UTC)
+	sub.LastSent.At = &sentAt
+
+	var buf bytes.Buffer
+	require.NoError(t, writeCSV(&buf, []SubscriptionView{newSubscriptionView(sub)}))
+
+	assert.Equal(t, "id,email,city,frequency,language,confirmed,only_on_change,created_at,last_sent_at\n"+
+		"1,a@example.com,\"Kyiv, Center\",daily,en,true,false,2025-05-30T12:00:00Z,2025-06-01T09:00:00Z\n",
+		buf.String())
+}
@@ -0,0 +1,45 @@
+package admin
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+)
+
+var csvHeader = []string{
+	"id", "email", "city", "frequency", "language", "confirmed", "only_on_change", "created_at", "last_sent_at",
+}
+
+func writeCSV(w io.Writer, subs []SubscriptionView) error {
+	writer := csv.NewWriter(w)
+
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, sub := range subs {
+		lastSentAt := ""
+		if sub.LastSentAt != nil {
+			lastSentAt = sub.LastSentAt.UTC().Format(time.RFC3339)
+		}
+
+		err := writer.Write([]string{
+			strconv.FormatUint(uint64(sub.ID), 10),
+			sub.Email,
+			sub.City,
+			string(sub.Frequency),
+			string(sub.Language),
+			strconv.FormatBool(sub.Confirmed),
+			strconv.FormatBool(sub.OnlyOnChange),
+			sub.CreatedAt.UTC().Format(time.RFC3339),
+			lastSentAt,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,64 @@
+package admin
+
+import (
+	"errors"
+	"time"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/service/subscription"
+)
+
+var (
+	ErrInvalidFilter        = errors.New("invalid filter")
+	ErrInvalidID            = errors.New("invalid subscription id")
+	ErrSubscriptionNotFound = errors.New("subscription not found")
+	ErrInvalidDispatch      = errors.New("either frequency or email is required")
+	ErrDispatchUnavailable  = errors.New("dispatch unavailable, shutting down")
+	ErrFailedToLoad         = errors.New("failed to load subscriptions")
+	ErrFailedToSave         = errors.New("failed to save subscription")
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 500
+)
+
+// SubscriptionView is a subscription as shown to admins, without its token.
+type SubscriptionView struct {
+	ID           uint                   `json:"id"`
+	Email        string                 `json:"email"`
+	City         string                 `json:"city"`
+	Frequency    subscription.Frequency `json:"frequency"`
+	Language     subscription.Language  `json:"language"`
+	Confirmed    bool                   `json:"confirmed"`
+	OnlyOnChange bool                   `json:"only_on_change"`
+	CreatedAt    time.Time              `json:"created_at"`
+	LastSentAt   *time.Time             `json:"last_sent_at,omitempty"`
+}
+
+func newSubscriptionView(sub subscription.Subscription) SubscriptionView {
+	return SubscriptionView{
+		ID:           sub.ID,
+		Email:        sub.Email,
+		City:         sub.City,
+		Frequency:    sub.Frequency,
+		Language:     sub.Language,
+		Confirmed:    sub.Confirmed,
+		OnlyOnChange: sub.OnlyOnChange,
+		CreatedAt:    sub.CreatedAt,
+		LastSentAt:   sub.LastSent.At,
+	}
+}
+
+type SubscriptionPage struct {
+	Items    []SubscriptionView `json:"items"`
+	Total    int64              `json:"total"`
+	Page     int                `json:"page"`
+	PageSize int                `json:"page_size"`
+}
+
+// DispatchRequest triggers the weather emails of a whole frequency or of
+// a single address.
+type DispatchRequest struct {
+	Frequency string `json:"frequency"`
+	Email     string `json:"email"`
+}
@@ -0,0 +1,75 @@
+package admin
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/service/subscription"
+	"github.com/gin-gonic/gin"
+)
+
+// parseFilter reads the list and export filters from the query string.
+// Dates are RFC 3339 timestamps or plain YYYY-MM-DD days.
+func parseFilter(c *gin.Context) (subscription.Filter, error) {
+	filter := subscription.Filter{
+		Email: c.Query("email"),
+		City:  c.Query("city"),
+	}
+
+	if value := c.Query("frequency"); value != "" {
+		freq, err := subscription.ParseFrequency(value)
+		if err != nil {
+			return filter, fmt.Errorf("%w: %v", ErrInvalidFilter, err)
+		}
+		filter.Frequency = freq
+	}
+
+	if value := c.Query("confirmed"); value != "" {
+		confirmed, err := strconv.ParseBool(value)
+		if err != nil {
+			return filter, fmt.Errorf("%w: confirmed must be true or false", ErrInvalidFilter)
+		}
+		filter.Confirmed = &confirmed
+	}
+
+	var err error
+
+	if filter.CreatedFrom, err = parseDate(c.Query("created_from")); err != nil {
+		return filter, fmt.Errorf("%w: created_from: %v", ErrInvalidFilter, err)
+	}
+	if filter.CreatedTo, err = parseDate(c.Query("created_to")); err != nil {
+		return filter, fmt.Errorf("%w: created_to: %v", ErrInvalidFilter, err)
+	}
+
+	if filter.Page, err = parseInt(c.Query("page")); err != nil {
+		return filter, fmt.Errorf("%w: page: %v", ErrInvalidFilter, err)
+	}
+	if filter.PageSize, err = parseInt(c.Query("page_size")); err != nil {
+		return filter, fmt.Errorf("%w: page_size: %v", ErrInvalidFilter, err)
+	}
+
+	return filter, nil
+}
+
+func parseDate(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return &parsed, nil
+		}
+	}
+
+	return nil, fmt.Errorf("invalid date %q", value)
+}
+
+func parseInt(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	return strconv.Atoi(value)
+}
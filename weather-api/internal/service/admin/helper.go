@@ -0,0 +1,40 @@
+package admin
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/problem"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/service/subscription"
+	"github.com/gin-gonic/gin"
+)
+
+func HandleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrInvalidFilter):
+		problem.Abort(c, http.StatusBadRequest, "invalid_filter", err)
+
+	case errors.Is(err, ErrInvalidID):
+		problem.Abort(c, http.StatusBadRequest, "invalid_id", err)
+
+	case errors.Is(err, ErrInvalidDispatch):
+		problem.Abort(c, http.StatusBadRequest, "invalid_dispatch", err)
+
+	case errors.Is(err, ErrSubscriptionNotFound):
+		problem.Abort(c, http.StatusNotFound, "subscription_not_found", err)
+
+	case errors.Is(err, subscription.ErrSubscriberNotFound):
+		problem.Abort(c, http.StatusNotFound, "subscriber_not_found", err)
+
+	case errors.Is(err, ErrFailedToLoad),
+		errors.Is(err, ErrFailedToSave),
+		errors.Is(err, subscription.ErrFailedToLoadSubscription):
+		problem.Abort(c, http.StatusInternalServerError, "persistence_failed", err)
+
+	case errors.Is(err, ErrDispatchUnavailable):
+		problem.Abort(c, http.StatusServiceUnavailable, "dispatch_unavailable", err)
+
+	default:
+		problem.AbortUnmapped(c, err)
+	}
+}
@@ -0,0 +1,104 @@
+package apikey
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/problem"
+	"github.com/gin-gonic/gin"
+)
+
+type apiKeyService interface {
+	Create(ctx context.Context, request CreateRequest) (*CreatedKey, error)
+	List(ctx context.Context) ([]APIKey, error)
+	Revoke(ctx context.Context, id uint) error
+	Usage(ctx context.Context, id uint) (*KeyUsage, error)
+	CurrentUsage(ctx context.Context, rawKey string) (*KeyUsage, error)
+}
+
+type APIKeyController struct {
+	service apiKeyService
+}
+
+func NewAPIKeyController(service apiKeyService) *APIKeyController {
+	return &APIKeyController{service: service}
+}
+
+func (kc *APIKeyController) Create(c *gin.Context) {
+	var request CreateRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		problem.AbortInvalidBody(c)
+		return
+	}
+
+	created, err := kc.service.Create(c.Request.Context(), request)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, created)
+}
+
+func (kc *APIKeyController) List(c *gin.Context) {
+	keys, err := kc.service.List(c.Request.Context())
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, keys)
+}
+
+func (kc *APIKeyController) Revoke(c *gin.Context) {
+	id, err := parseID(c)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	if err := kc.service.Revoke(c.Request.Context(), id); err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (kc *APIKeyController) Usage(c *gin.Context) {
+	id, err := parseID(c)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	usage, err := kc.service.Usage(c.Request.Context(), id)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, usage)
+}
+
+// CurrentUsage lets partners check the usage of their own key.
+func (kc *APIKeyController) CurrentUsage(c *gin.Context) {
+	usage, err := kc.service.CurrentUsage(c.Request.Context(), c.GetHeader(HeaderAPIKey))
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, usage)
+}
+
+func parseID(c *gin.Context) (uint, error) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 0)
+	if err != nil || id == 0 {
+		return 0, ErrKeyNotFound
+	}
+
+	return uint(id), nil
+}
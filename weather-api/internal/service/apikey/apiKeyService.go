@@ -0,0 +1,342 @@
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/logger"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/redis"
+	goredis "github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+type apiKeyRepository interface {
+	Create(ctx context.Context, key *APIKey) error
+	Update(ctx context.Context, key APIKey) error
+	FindAll(ctx context.Context) ([]APIKey, error)
+	FindByID(ctx context.Context, id uint) (*APIKey, error)
+	FindByHash(ctx context.Context, hash string) (*APIKey, error)
+}
+
+type counterStore interface {
+	Incr(ctx context.Context, key string, ttl time.Duration) (int64, error)
+	Decr(ctx context.Context, key string) (int64, error)
+	Get(ctx context.Context, key string, dest interface{}) error
+}
+
+type keyCache interface {
+	Get(key string) (any, bool)
+	Set(key string, value any, ttl time.Duration)
+	Delete(key string)
+}
+
+const (
+	keyPrefix    = "wk_"
+	prefixLength = len(keyPrefix) + 8
+)
+
+type APIKeyService struct {
+	repository   apiKeyRepository
+	counters     counterStore
+	keys         keyCache
+	anonymous    Quota
+	defaultQuota Quota
+	requireKey   bool
+	now          func() time.Time
+	logger       logger.Logger
+}
+
+// NewAPIKeyService counts requests with anonymous quota per client IP
+// unless requireKey is set; keys created without quotas get defaultQuota.
+// Keys looked up by their hash are kept in keys, so a revocation reaches
+// other replicas once their entry expires.
+func NewAPIKeyService(repository apiKeyRepository, counters counterStore, keys keyCache,
+	anonymous Quota, defaultQuota Quota, requireKey bool, logger logger.Logger) *APIKeyService {
+	return &APIKeyService{
+		repository:   repository,
+		counters:     counters,
+		keys:         keys,
+		anonymous:    anonymous,
+		defaultQuota: defaultQuota,
+		requireKey:   requireKey,
+		now:          time.Now,
+		logger:       logger,
+	}
+}
+
+func (s *APIKeyService) Create(ctx context.Context, request CreateRequest) (*CreatedKey, error) {
+	if request.Name == "" {
+		return nil, ErrNameRequired
+	}
+
+	raw, err := generateKey()
+	if err != nil {
+		s.logger.WithContext(ctx).Error("Failed to generate API key", "error", err)
+		return nil, ErrFailedToSave
+	}
+
+	key := APIKey{
+		Name:         request.Name,
+		Prefix:       raw[:prefixLength],
+		Hash:         hashKey(raw),
+		DailyQuota:   s.defaultQuota.Daily,
+		MonthlyQuota: s.defaultQuota.Monthly,
+	}
+	if request.DailyQuota != nil {
+		key.DailyQuota = *request.DailyQuota
+	}
+	if request.MonthlyQuota != nil {
+		key.MonthlyQuota = *request.MonthlyQuota
+	}
+
+	if err := s.repository.Create(ctx, &key); err != nil {
+		s.logger.WithContext(ctx).Error("Failed to save API key", "name", request.Name, "error", err)
+		return nil, ErrFailedToSave
+	}
+
+	s.logger.WithContext(ctx).Info("API key created", "id", key.ID, "name", key.Name, "prefix", key.Prefix)
+
+	return &CreatedKey{APIKey: key, Key: raw}, nil
+}
+
+func (s *APIKeyService) List(ctx context.Context) ([]APIKey, error) {
+	keys, err := s.repository.FindAll(ctx)
+	if err != nil {
+		s.logger.WithContext(ctx).Error("Failed to list API keys", "error", err)
+		return nil, ErrFailedToLoad
+	}
+
+	return keys, nil
+}
+
+func (s *APIKeyService) Revoke(ctx context.Context, id uint) error {
+	key, err := s.findByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if key.RevokedAt != nil {
+		return nil
+	}
+
+	now := s.now()
+	key.RevokedAt = &now
+
+	if err := s.repository.Update(ctx, *key); err != nil {
+		s.logger.WithContext(ctx).Error("Failed to revoke API key", "id", id, "error", err)
+		return ErrFailedToSave
+	}
+	s.keys.Delete(key.Hash)
+
+	s.logger.WithContext(ctx).Info("API key revoked", "id", id, "name", key.Name)
+
+	return nil
+}
+
+// Usage reports the current day and month of a key, for admins.
+func (s *APIKeyService) Usage(ctx context.Context, id uint) (*KeyUsage, error) {
+	key, err := s.findByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &KeyUsage{APIKey: *key, Usage: s.usage(ctx, keySubject(key), key.Quota(), s.now().UTC(), false)}, nil
+}
+
+// CurrentUsage reports the usage of the key a partner presents, without
+// counting the request.
+func (s *APIKeyService) CurrentUsage(ctx context.Context, rawKey string) (*KeyUsage, error) {
+	if rawKey == "" {
+		return nil, ErrAPIKeyRequired
+	}
+
+	key, err := s.authenticate(ctx, rawKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &KeyUsage{APIKey: *key, Usage: s.usage(ctx, keySubject(key), key.Quota(), s.now().UTC(), false)}, nil
+}
+
+// Consume counts one request against the quota of rawKey, or of clientIP
+// when no key is given. It returns ErrQuotaExceeded with the usage once a
+// window is used up; a refused request counts against neither window.
+// Counting fails open: when Redis is down, requests are let through.
+func (s *APIKeyService) Consume(ctx context.Context, rawKey string, clientIP string) (Usage, error) {
+	if rawKey == "" {
+		if s.requireKey {
+			return Usage{}, ErrAPIKeyRequired
+		}
+
+		return s.consume(ctx, "anon"+redis.Delimeter+clientIP, s.anonymous)
+	}
+
+	key, err := s.authenticate(ctx, rawKey)
+	if err != nil {
+		return Usage{}, err
+	}
+
+	return s.consume(ctx, keySubject(key), key.Quota())
+}
+
+func (s *APIKeyService) consume(ctx context.Context, subject string, quota Quota) (Usage, error) {
+	now := s.now().UTC()
+	usage := s.usage(ctx, subject, quota, now, true)
+
+	if usage.Exceeded() {
+		s.takeBack(ctx, subject, usage, now)
+		return usage, ErrQuotaExceeded
+	}
+
+	return usage, nil
+}
+
+// takeBack uncounts a refused request. The request is counted before the
+// check, like in the sliding window limiter, so concurrent requests can't
+// all pass it before any of them is counted.
+func (s *APIKeyService) takeBack(ctx context.Context, subject string, usage Usage, now time.Time) {
+	windows := []struct {
+		counter string
+		usage   WindowUsage
+	}{
+		{dayCounter(subject, now), usage.Day},
+		{monthCounter(subject, now), usage.Month},
+	}
+
+	for _, window := range windows {
+		// unlimited windows aren't counted, and a zero count means the
+		// increment failed
+		if window.usage.Unlimited() || window.usage.Used == 0 {
+			continue
+		}
+
+		key := redis.QuotaKey + window.counter
+		if _, err := s.counters.Decr(ctx, key); err != nil {
+			s.logger.WithContext(ctx).Error("Failed to take back refused request", "key", key, "error", err)
+		}
+	}
+}
+
+func (s *APIKeyService) usage(ctx context.Context, subject string, quota Quota, now time.Time,
+	increment bool) Usage {
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	return Usage{
+		Day: s.window(ctx, dayCounter(subject, now), quota.Daily, dayStart.AddDate(0, 0, 1), now, increment),
+		Month: s.window(ctx, monthCounter(subject, now), quota.Monthly, monthStart.AddDate(0, 1, 0), now,
+			increment),
+	}
+}
+
+func (s *APIKeyService) window(ctx context.Context, counter string, limit int64,
+	reset time.Time, now time.Time, increment bool) WindowUsage {
+	window := WindowUsage{Limit: limit, Reset: reset}
+
+	if window.Unlimited() {
+		return window
+	}
+
+	key := redis.QuotaKey + counter
+
+	var err error
+	if increment {
+		// counters outlive their window a little, so a late request
+		// never restarts a finished one
+		window.Used, err = s.counters.Incr(ctx, key, reset.Sub(now)+time.Hour)
+	} else {
+		err = s.counters.Get(ctx, key, &window.Used)
+		if errors.Is(err, goredis.Nil) {
+			err = nil
+		}
+	}
+
+	if err != nil {
+		s.logger.WithContext(ctx).Error("Failed to read quota counter", "key", key, "error", err)
+		window.Used = 0
+	}
+
+	return window
+}
+
+func (s *APIKeyService) authenticate(ctx context.Context, rawKey string) (*APIKey, error) {
+	key, err := s.findByHash(ctx, hashKey(rawKey))
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	if err != nil {
+		s.logger.WithContext(ctx).Error("Failed to look up API key", "error", err)
+		return nil, ErrFailedToLoad
+	}
+
+	if key.RevokedAt != nil {
+		return nil, ErrInvalidAPIKey
+	}
+
+	return key, nil
+}
+
+func (s *APIKeyService) findByHash(ctx context.Context, hash string) (*APIKey, error) {
+	if cached, ok := s.keys.Get(hash); ok {
+		key := cached.(APIKey)
+		return &key, nil
+	}
+
+	key, err := s.repository.FindByHash(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	s.keys.Set(hash, *key, 0)
+
+	return key, nil
+}
+
+func (s *APIKeyService) findByID(ctx context.Context, id uint) (*APIKey, error) {
+	key, err := s.repository.FindByID(ctx, id)
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrKeyNotFound
+	}
+
+	if err != nil {
+		s.logger.WithContext(ctx).Error("Failed to load API key", "id", id, "error", err)
+		return nil, ErrFailedToLoad
+	}
+
+	return key, nil
+}
+
+func dayCounter(subject string, now time.Time) string {
+	return subject + redis.Delimeter + "day" + redis.Delimeter + now.Format(time.DateOnly)
+}
+
+func monthCounter(subject string, now time.Time) string {
+	return subject + redis.Delimeter + "month" + redis.Delimeter + now.Format("2006-01")
+}
+
+func keySubject(key *APIKey) string {
+	return "key" + redis.Delimeter + strconv.FormatUint(uint64(key.ID), 10)
+}
+
+func generateKey() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return keyPrefix + hex.EncodeToString(buf), nil
+}
+
+// Keys are random, so a plain SHA-256 is enough to make the stored hash
+// useless to an attacker.
+func hashKey(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,304 @@
+//go:build unit
+// +build unit
+
+package apikey
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/logger"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/cache"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// --- Mocks ---
+
+type mockRepository struct {
+	mock.Mock
+}
+
+func (m *mockRepository) Create(_ context.Context, key *APIKey) error {
+	args := m.Called(key)
+	key.ID = 1
+	return args.Error(0)
+}
+
+func (m *mockRepository) Update(_ context.Context, key APIKey) error {
+	return m.Called(key).Error(0)
+}
+
+func (m *mockRepository) FindAll(context.Context) ([]APIKey, error) {
+	args := m.Called()
+	keys, _ := args.Get(0).([]APIKey)
+	return keys, args.Error(1)
+}
+
+func (m *mockRepository) FindByID(_ context.Context, id uint) (*APIKey, error) {
+	args := m.Called(id)
+	key, _ := args.Get(0).(*APIKey)
+	return key, args.Error(1)
+}
+
+func (m *mockRepository) FindByHash(_ context.Context, hash string) (*APIKey, error) {
+	args := m.Called(hash)
+	key, _ := args.Get(0).(*APIKey)
+	return key, args.Error(1)
+}
+
+// fakeCounters keeps counters in memory.
+type fakeCounters struct {
+	values map[string]int64
+	ttls   map[string]time.Duration
+	err    error
+}
+
+func newFakeCounters() *fakeCounters {
+	return &fakeCounters{values: map[string]int64{}, ttls: map[string]time.Duration{}}
+}
+
+func (f *fakeCounters) Incr(_ context.Context, key string, ttl time.Duration) (int64, error) {
+	if f.err != nil {
+		return 0, f.err
+	}
+	f.values[key]++
+	if f.values[key] == 1 {
+		f.ttls[key] = ttl
+	}
+	return f.values[key], nil
+}
+
+func (f *fakeCounters) Decr(_ context.Context, key string) (int64, error) {
+	if f.err != nil {
+		return 0, f.err
+	}
+	f.values[key]--
+	return f.values[key], nil
+}
+
+func (f *fakeCounters) Get(_ context.Context, key string, dest interface{}) error {
+	if f.err != nil {
+		return f.err
+	}
+	value, ok := f.values[key]
+	if !ok {
+		return fmt.Errorf("get %s: %w", key, redis.Nil)
+	}
+	*dest.(*int64) = value
+	return nil
+}
+
+var fixedNow = time.Date(2025, time.June, 15, 22, 0, 0, 0, time.UTC)
+
+func setupKeyTest(requireKey bool) (*mockRepository, *fakeCounters, *APIKeyService) {
+	repo := new(mockRepository)
+	counters := newFakeCounters()
+	mockLog, _ := logger.NewTestLogger()
+
+	service := NewAPIKeyService(repo, counters, cache.NewLocal(10, time.Minute),
+		Quota{Daily: 2, Monthly: 100}, Quota{Daily: 1000, Monthly: 20000}, requireKey, *mockLog)
+	service.now = func() time.Time { return fixedNow }
+
+	return repo, counters, service
+}
+
+// --- Tests ---
+
+func TestCreate_StoresOnlyTheHash(t *testing.T) {
+	repo, _, service := setupKeyTest(false)
+
+	var stored *APIKey
+	repo.On("Create", mock.AnythingOfType("*apikey.APIKey")).
+		Run(func(args mock.Arguments) { stored = args.Get(0).(*APIKey) }).Return(nil)
+
+	daily := int64(50)
+	created, err := service.Create(context.Background(), CreateRequest{Name: "partner", DailyQuota: &daily})
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(created.Key, keyPrefix))
+	assert.Equal(t, hashKey(created.Key), stored.Hash)
+	assert.NotContains(t, stored.Hash, created.Key)
+	assert.Equal(t, created.Key[:prefixLength], stored.Prefix)
+	assert.Equal(t, int64(50), stored.DailyQuota)
+	assert.Equal(t, int64(20000), stored.MonthlyQuota)
+}
+
+func TestCreate_NameRequired(t *testing.T) {
+	_, _, service := setupKeyTest(false)
+
+	_, err := service.Create(context.Background(), CreateRequest{})
+	assert.ErrorIs(t, err, ErrNameRequired)
+}
+
+func TestConsume_AnonymousQuotaPerIP(t *testing.T) {
+	_, counters, service := setupKeyTest(false)
+
+	for i := 0; i < 2; i++ {
+		_, err := service.Consume(context.Background(), "", "10.0.0.1")
+		require.NoError(t, err)
+	}
+
+	usage, err := service.Consume(context.Background(), "", "10.0.0.1")
+	assert.ErrorIs(t, err, ErrQuotaExceeded)
+	assert.Equal(t, int64(0), usage.Day.Remaining())
+	assert.Equal(t, time.Date(2025, time.June, 16, 0, 0, 0, 0, time.UTC), usage.Day.Reset)
+
+	_, err = service.Consume(context.Background(), "", "10.0.0.2")
+	assert.NoError(t, err)
+
+	assert.Equal(t, 3*time.Hour, counters.ttls["quota:anon:10.0.0.1:day:2025-06-15"])
+	assert.Equal(t, int64(2), counters.values["quota:anon:10.0.0.1:day:2025-06-15"])
+	assert.Equal(t, int64(2), counters.values["quota:anon:10.0.0.1:month:2025-06"])
+}
+
+func TestConsume_RefusedRequestsDontUseMonthlyQuota(t *testing.T) {
+	repo, _, service := setupKeyTest(false)
+	key := &APIKey{ID: 7, Name: "partner", DailyQuota: 2, MonthlyQuota: 100}
+	repo.On("FindByHash", hashKey("wk_secret")).Return(key, nil)
+
+	for i := 0; i < 5; i++ {
+		_, _ = service.Consume(context.Background(), "wk_secret", "10.0.0.1")
+	}
+
+	report, err := service.CurrentUsage(context.Background(), "wk_secret")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), report.Usage.Day.Used)
+	assert.Equal(t, int64(2), report.Usage.Month.Used)
+}
+
+func TestCurrentUsage_UnusedKeyReportsZero(t *testing.T) {
+	repo, _, service := setupKeyTest(false)
+	key := &APIKey{ID: 7, Name: "partner", DailyQuota: 5, MonthlyQuota: 100}
+	repo.On("FindByHash", hashKey("wk_secret")).Return(key, nil)
+
+	report, err := service.CurrentUsage(context.Background(), "wk_secret")
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), report.Usage.Day.Used)
+	assert.Equal(t, int64(0), report.Usage.Month.Used)
+}
+
+func TestConsume_APIKeyRequired(t *testing.T) {
+	_, _, service := setupKeyTest(true)
+
+	_, err := service.Consume(context.Background(), "", "10.0.0.1")
+	assert.ErrorIs(t, err, ErrAPIKeyRequired)
+}
+
+func TestConsume_ValidKeyUsesItsQuota(t *testing.T) {
+	repo, counters, service := setupKeyTest(false)
+	key := &APIKey{ID: 7, Name: "partner", DailyQuota: 5, MonthlyQuota: -1}
+	repo.On("FindByHash", hashKey("wk_secret")).Return(key, nil)
+
+	usage, err := service.Consume(context.Background(), "wk_secret", "10.0.0.1")
+	require.NoError(t, err)
+
+	window, ok := usage.Binding()
+	assert.True(t, ok)
+	assert.Equal(t, int64(5), window.Limit)
+	assert.Equal(t, int64(4), window.Remaining())
+	assert.True(t, usage.Month.Unlimited())
+	assert.Equal(t, int64(1), counters.values["quota:key:7:day:2025-06-15"])
+	assert.NotContains(t, counters.values, "quota:key:7:month:2025-06")
+}
+
+func TestConsume_InvalidOrRevokedKey(t *testing.T) {
+	repo, _, service := setupKeyTest(false)
+	revokedAt := fixedNow
+	repo.On("FindByHash", hashKey("wk_unknown")).Return(nil, gorm.ErrRecordNotFound)
+	repo.On("FindByHash", hashKey("wk_revoked")).Return(&APIKey{ID: 2, RevokedAt: &revokedAt}, nil)
+
+	_, err := service.Consume(context.Background(), "wk_unknown", "10.0.0.1")
+	assert.ErrorIs(t, err, ErrInvalidAPIKey)
+
+	_, err = service.Consume(context.Background(), "wk_revoked", "10.0.0.1")
+	assert.ErrorIs(t, err, ErrInvalidAPIKey)
+}
+
+func TestConsume_FailsOpenWhenCountersAreDown(t *testing.T) {
+	_, counters, service := setupKeyTest(false)
+	counters.err = errors.New("redis down")
+
+	for i := 0; i < 5; i++ {
+		_, err := service.Consume(context.Background(), "", "10.0.0.1")
+		assert.NoError(t, err)
+	}
+}
+
+func TestCurrentUsage_DoesNotCount(t *testing.T) {
+	repo, counters, service := setupKeyTest(false)
+	key := &APIKey{ID: 7, Name: "partner", DailyQuota: 5, MonthlyQuota: 100}
+	repo.On("FindByHash", hashKey("wk_secret")).Return(key, nil)
+
+	_, err := service.Consume(context.Background(), "wk_secret", "10.0.0.1")
+	require.NoError(t, err)
+
+	report, err := service.CurrentUsage(context.Background(), "wk_secret")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), report.Usage.Day.Used)
+	assert.Equal(t, int64(1), report.Usage.Month.Used)
+	assert.Equal(t, int64(1), counters.values["quota:key:7:day:2025-06-15"])
+}
+
+func TestRevoke(t *testing.T) {
+	repo, _, service := setupKeyTest(false)
+	repo.On("FindByID", uint(3)).Return(&APIKey{ID: 3, Name: "partner"}, nil)
+	repo.On("Update", mock.MatchedBy(func(key APIKey) bool {
+		return key.ID == 3 && key.RevokedAt != nil
+	})).Return(nil).Once()
+
+	require.NoError(t, service.Revoke(context.Background(), 3))
+	repo.AssertExpectations(t)
+}
+
+func TestConsume_CachesKeyLookups(t *testing.T) {
+	repo, _, service := setupKeyTest(false)
+	key := &APIKey{ID: 7, Name: "partner", Hash: hashKey("wk_secret"), DailyQuota: 5, MonthlyQuota: 100}
+	repo.On("FindByHash", hashKey("wk_secret")).Return(key, nil).Once()
+
+	for i := 0; i < 3; i++ {
+		_, err := service.Consume(context.Background(), "wk_secret", "10.0.0.1")
+		require.NoError(t, err)
+	}
+	repo.AssertNumberOfCalls(t, "FindByHash", 1)
+
+	repo.On("FindByID", uint(7)).Return(key, nil)
+	repo.On("Update", mock.AnythingOfType("apikey.APIKey")).Return(nil)
+	require.NoError(t, service.Revoke(context.Background(), 7))
+
+	revoked := *key
+	revokedAt := fixedNow
+	revoked.RevokedAt = &revokedAt
+	repo.On("FindByHash", hashKey("wk_secret")).Return(&revoked, nil).Once()
+
+	_, err := service.Consume(context.Background(), "wk_secret", "10.0.0.1")
+	assert.ErrorIs(t, err, ErrInvalidAPIKey)
+}
+
+func TestRevoke_NotFound(t *testing.T) {
+	repo, _, service := setupKeyTest(false)
+	repo.On("FindByID", uint(3)).Return(nil, gorm.ErrRecordNotFound)
+
+	assert.ErrorIs(t, service.Revoke(context.Background(), 3), ErrKeyNotFound)
+}
+
+func TestUsage_Binding(t *testing.T) {
+	usage := Usage{
+		Day:   WindowUsage{Used: 10, Limit: 100},
+		Month: WindowUsage{Used: 995, Limit: 1000},
+	}
+
+	window, ok := usage.Binding()
+	assert.True(t, ok)
+	assert.Equal(t, int64(1000), window.Limit)
+
+	_, ok = Usage{Day: WindowUsage{Limit: -1}, Month: WindowUsage{Limit: -1}}.Binding()
+	assert.False(t, ok)
+}
@@ -0,0 +1,85 @@
+package apikey
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrAPIKeyRequired = errors.New("API key required")
+	ErrInvalidAPIKey  = errors.New("invalid API key")
+	ErrQuotaExceeded  = errors.New("quota exceeded")
+	ErrKeyNotFound    = errors.New("API key not found")
+	ErrNameRequired   = errors.New("name is required")
+	ErrFailedToSave   = errors.New("failed to save API key")
+	ErrFailedToLoad   = errors.New("failed to load API keys")
+)
+
+// Quota limits the requests of one client per UTC day and month. A
+// negative limit means unlimited.
+type Quota struct {
+	Daily   int64
+	Monthly int64
+}
+
+type CreateRequest struct {
+	Name         string `json:"name"`
+	DailyQuota   *int64 `json:"daily_quota"`
+	MonthlyQuota *int64 `json:"monthly_quota"`
+}
+
+// CreatedKey carries the plain key, which is only ever shown once.
+type CreatedKey struct {
+	APIKey
+	Key string `json:"key"`
+}
+
+// WindowUsage is the number of requests made in the current day or month.
+type WindowUsage struct {
+	Used  int64     `json:"used"`
+	Limit int64     `json:"limit"`
+	Reset time.Time `json:"reset"`
+}
+
+func (w WindowUsage) Unlimited() bool {
+	return w.Limit < 0
+}
+
+func (w WindowUsage) Remaining() int64 {
+	return max(w.Limit-w.Used, 0)
+}
+
+func (w WindowUsage) Exceeded() bool {
+	return !w.Unlimited() && w.Used > w.Limit
+}
+
+type Usage struct {
+	Day   WindowUsage `json:"day"`
+	Month WindowUsage `json:"month"`
+}
+
+func (u Usage) Exceeded() bool {
+	return u.Day.Exceeded() || u.Month.Exceeded()
+}
+
+// Binding returns the window closest to its limit, the one reported in
+// the X-RateLimit headers. ok is false when both windows are unlimited.
+func (u Usage) Binding() (window WindowUsage, ok bool) {
+	switch {
+	case u.Day.Unlimited() && u.Month.Unlimited():
+		return WindowUsage{}, false
+	case u.Day.Unlimited():
+		return u.Month, true
+	case u.Month.Unlimited():
+		return u.Day, true
+	case u.Month.Remaining() < u.Day.Remaining():
+		return u.Month, true
+	default:
+		return u.Day, true
+	}
+}
+
+type KeyUsage struct {
+	APIKey
+	Usage Usage `json:"usage"`
+}
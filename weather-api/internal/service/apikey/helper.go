@@ -0,0 +1,38 @@
+package apikey
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/problem"
+	"github.com/gin-gonic/gin"
+)
+
+// HeaderAPIKey carries a partner's key on public endpoints.
+const HeaderAPIKey = "X-API-Key"
+
+func HandleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrNameRequired):
+		problem.AbortValidation(c, problem.FieldError{Field: "name", Code: "required", Message: err.Error()})
+
+	case errors.Is(err, ErrAPIKeyRequired):
+		problem.Abort(c, http.StatusUnauthorized, "api_key_required", err)
+
+	case errors.Is(err, ErrInvalidAPIKey):
+		problem.Abort(c, http.StatusUnauthorized, "invalid_api_key", err)
+
+	case errors.Is(err, ErrQuotaExceeded):
+		problem.Abort(c, http.StatusTooManyRequests, "quota_exceeded", err)
+
+	case errors.Is(err, ErrKeyNotFound):
+		problem.Abort(c, http.StatusNotFound, "api_key_not_found", err)
+
+	case errors.Is(err, ErrFailedToSave),
+		errors.Is(err, ErrFailedToLoad):
+		problem.Abort(c, http.StatusInternalServerError, "persistence_failed", err)
+
+	default:
+		problem.AbortUnmapped(c, err)
+	}
+}
@@ -0,0 +1,20 @@
+package apikey
+
+import "time"
+
+// APIKey lets a partner call the weather endpoint with its own quota. Only
+// the SHA-256 hash of the key is stored, Prefix identifies it in listings.
+type APIKey struct {
+	ID           uint       `gorm:"primaryKey" json:"id"`
+	Name         string     `gorm:"not null" json:"name"`
+	Prefix       string     `gorm:"type:varchar(16);not null" json:"prefix"`
+	Hash         string     `gorm:"uniqueIndex;not null" json:"-"`
+	DailyQuota   int64      `gorm:"not null" json:"daily_quota"`
+	MonthlyQuota int64      `gorm:"not null" json:"monthly_quota"`
+	RevokedAt    *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+func (k APIKey) Quota() Quota {
+	return Quota{Daily: k.DailyQuota, Monthly: k.MonthlyQuota}
+}
@@ -0,0 +1,110 @@
+package subscription
+
+import (
+	"context"
+	"sync"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/logger"
+	metricP "github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/metrics"
+)
+
+type subscriptionLister interface {
+	GetConfirmedSubscriptionsByFrequency(ctx context.Context, freq Frequency) []Subscription
+}
+
+type weatherRefresher interface {
+	RefreshWeather(ctx context.Context, city string) error
+	RefreshForecast(ctx context.Context, city string) error
+}
+
+// CacheWarmer refreshes the cached weather of every city a dispatch is
+// about to send, so the dispatch reads the cache instead of waiting on
+// the providers one city at a time.
+type CacheWarmer struct {
+	subscriptions subscriptionLister
+	weather       weatherRefresher
+	concurrency   int
+	logger        logger.Logger
+}
+
+func NewCacheWarmer(subscriptions subscriptionLister, weather weatherRefresher, concurrency int,
+	logger logger.Logger) *CacheWarmer {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	return &CacheWarmer{
+		subscriptions: subscriptions,
+		weather:       weather,
+		concurrency:   concurrency,
+		logger:        logger,
+	}
+}
+
+// WarmUp refreshes the distinct cities of the confirmed subscriptions of
+// freq, at most concurrency at a time. Daily emails carry a forecast, so
+// it is refreshed too. It returns the number of cities that failed.
+func (cw *CacheWarmer) WarmUp(ctx context.Context, freq Frequency) int {
+	cities := distinctCities(cw.subscriptions.GetConfirmedSubscriptionsByFrequency(ctx, freq))
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		failures int
+	)
+	slots := make(chan struct{}, cw.concurrency)
+
+	for _, city := range cities {
+		wg.Add(1)
+		slots <- struct{}{}
+
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			if err := cw.refresh(ctx, freq, city); err != nil {
+				cw.logger.WithContext(ctx).Error("Failed to warm up weather cache", "city", city, "error", err)
+
+				mu.Lock()
+				failures++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	metricP.CacheWarmUp(string(freq), len(cities), failures)
+	cw.logger.WithContext(ctx).Info("Warmed up weather cache",
+		"frequency", string(freq),
+		"cities", len(cities),
+		"failures", failures)
+
+	return failures
+}
+
+func (cw *CacheWarmer) refresh(ctx context.Context, freq Frequency, city string) error {
+	if err := cw.weather.RefreshWeather(ctx, city); err != nil {
+		return err
+	}
+
+	if freq == FrequencyDaily {
+		return cw.weather.RefreshForecast(ctx, city)
+	}
+
+	return nil
+}
+
+// distinctCities keeps the order in which cities first appear.
+func distinctCities(subs []Subscription) []string {
+	seen := make(map[string]bool)
+	cities := []string{}
+
+	for _, sub := range subs {
+		if !seen[sub.City] {
+			seen[sub.City] = true
+			cities = append(cities, sub.City)
+		}
+	}
+
+	return cities
+}
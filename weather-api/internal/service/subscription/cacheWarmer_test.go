@@ -0,0 +1,93 @@
+//go:build unit
+// +build unit
+
+package subscription
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubLister struct {
+	subs []Subscription
+}
+
+func (s stubLister) GetConfirmedSubscriptionsByFrequency(context.Context, Frequency) []Subscription {
+	return s.subs
+}
+
+// fakeRefresher records refreshed cities and the peak number of refreshes
+// running at once.
+type fakeRefresher struct {
+	mu        sync.Mutex
+	weather   []string
+	forecast  []string
+	failing   map[string]bool
+	running   atomic.Int32
+	maxActive atomic.Int32
+}
+
+func (f *fakeRefresher) RefreshWeather(_ context.Context, city string) error {
+	active := f.running.Add(1)
+	defer f.running.Add(-1)
+	for {
+		peak := f.maxActive.Load()
+		if active <= peak || f.maxActive.CompareAndSwap(peak, active) {
+			break
+		}
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.weather = append(f.weather, city)
+
+	if f.failing[city] {
+		return errors.New("provider timeout")
+	}
+	return nil
+}
+
+func (f *fakeRefresher) RefreshForecast(_ context.Context, city string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.forecast = append(f.forecast, city)
+	return nil
+}
+
+func TestWarmUp_RefreshesDistinctCitiesWithBoundedConcurrency(t *testing.T) {
+	mockLog, _ := logger.NewTestLogger()
+
+	subs := []Subscription{}
+	for _, city := range []string{"Kyiv", "Lviv", "Kyiv", "Odesa", "Dnipro", "Lviv", "Kharkiv"} {
+		subs = append(subs, Subscription{Email: city + "@example.com", City: city})
+	}
+	refresher := &fakeRefresher{failing: map[string]bool{"Odesa": true}}
+	warmer := NewCacheWarmer(stubLister{subs: subs}, refresher, 2, *mockLog)
+
+	failures := warmer.WarmUp(context.Background(), FrequencyHourly)
+
+	assert.Equal(t, 1, failures)
+	assert.ElementsMatch(t, []string{"Kyiv", "Lviv", "Odesa", "Dnipro", "Kharkiv"}, refresher.weather)
+	assert.Empty(t, refresher.forecast, "hourly emails carry no forecast")
+	assert.LessOrEqual(t, refresher.maxActive.Load(), int32(2))
+}
+
+func TestWarmUp_DailyRefreshesForecast(t *testing.T) {
+	mockLog, _ := logger.NewTestLogger()
+
+	refresher := &fakeRefresher{failing: map[string]bool{"Odesa": true}}
+	warmer := NewCacheWarmer(stubLister{subs: []Subscription{{City: "Kyiv"}, {City: "Odesa"}}},
+		refresher, 5, *mockLog)
+
+	warmer.WarmUp(context.Background(), FrequencyDaily)
+
+	assert.Equal(t, []string{"Kyiv"}, refresher.forecast, "no forecast when the weather failed")
+}
@@ -2,6 +2,7 @@ package subscription
 
 import (
 	"errors"
+	"time"
 
 	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/client"
 )
@@ -13,6 +14,9 @@ var (
 	ErrInvalidToken             = errors.New("invalid token")
 	ErrTokenNotFound            = errors.New("token not found")
 	ErrFailedToSaveSubscription = errors.New("failed to save subscription")
+	ErrSubscriberNotFound       = errors.New("no confirmed subscriptions for this email")
+	ErrFailedToLoadSubscription = errors.New("failed to load subscriptions")
+	ErrFailedToPublish          = errors.New("failed to publish email job")
 )
 
 type EmailType string
@@ -22,14 +26,63 @@ const (
 	EmailTypeConfirmSuccess     EmailType = "ConfirmSuccess"
 )
 
+// MessageID identifies the email in the mailer's delivery log, so a
+// redelivered job counts as another attempt of the same email.
 type EmailJob struct {
+	MessageID    string
 	To           string
 	EmailType    EmailType
 	Subscription Subscription
 }
 
+// Forecast is set for daily subscriptions only, hourly emails show the
+// current conditions.
 type WeatherUpdateJob struct {
+	MessageID    string
 	To           string
 	Weather      client.WeatherDTO
+	Forecast     *client.ForecastDTO
 	Subscription Subscription
 }
+
+// WeatherDigestJob combines the weather of every city one address follows
+// at the same frequency into a single email.
+type WeatherDigestJob struct {
+	MessageID string
+	To        string
+	Frequency Frequency
+	Language  Language
+	Items     []DigestItem
+}
+
+// Forecast is set in daily digests only, like in WeatherUpdateJob.
+type DigestItem struct {
+	Subscription Subscription
+	Weather      client.WeatherDTO
+	Forecast     *client.ForecastDTO
+}
+
+// SuppressionEvent is published by the mailer when an address hard-bounces
+// or reports our emails as spam.
+type SuppressionEvent struct {
+	Email        string
+	Reason       string
+	SuppressedAt time.Time
+}
+
+type SubscriptionStatus string
+
+const (
+	StatusPendingConfirmation SubscriptionStatus = "pending_confirmation"
+	StatusConfirmed           SubscriptionStatus = "confirmed"
+	StatusUnsubscribed        SubscriptionStatus = "unsubscribed"
+)
+
+// StatusResponseV2 is the /api/v2 body of the subscription endpoints, which
+// answer v1 clients with plain text.
+type StatusResponseV2 struct {
+	Status    SubscriptionStatus `json:"status"`
+	Email     string             `json:"email,omitempty"`
+	City      string             `json:"city,omitempty"`
+	Frequency Frequency          `json:"frequency,omitempty"`
+}
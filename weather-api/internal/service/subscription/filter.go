@@ -0,0 +1,30 @@
+package subscription
+
+import "time"
+
+// Filter narrows the subscriptions listed or exported by admins. Empty
+// fields match everything; CreatedTo is exclusive. A zero PageSize
+// returns every match, which only the CSV export uses.
+type Filter struct {
+	Email       string
+	City        string
+	Frequency   Frequency
+	Confirmed   *bool
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	Page        int
+	PageSize    int
+}
+
+func (f Filter) Offset() int {
+	return (f.Page - 1) * f.PageSize
+}
+
+// CityFrequencyCount is the number of subscriptions for one city and
+// frequency.
+type CityFrequencyCount struct {
+	City      string    `json:"city"`
+	Frequency Frequency `json:"frequency"`
+	Total     int64     `json:"total"`
+	Confirmed int64     `json:"confirmed"`
+}
@@ -4,22 +4,48 @@ import (
 	"errors"
 	"net/http"
 
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/client"
+	emailvalidation "github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/emailValidation"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/problem"
 	"github.com/gin-gonic/gin"
 )
 
 func HandleError(c *gin.Context, err error) {
+	var validationErr *emailvalidation.ValidationError
+
 	switch {
+	case errors.As(err, &validationErr):
+		problem.AbortValidation(c, problem.FieldError{
+			Field: "email", Code: validationErr.Code, Message: validationErr.Message})
+
+	case errors.Is(err, client.ErrCityNotFound):
+		problem.AbortValidation(c, problem.FieldError{
+			Field: "city", Code: "city_not_found", Message: err.Error()})
+
 	case errors.Is(err, ErrInvalidRequest),
-		errors.Is(err, ErrInvalidInput),
-		errors.Is(err, ErrInvalidToken),
-		errors.Is(err, ErrTokenNotFound),
-		errors.Is(err, ErrFailedToSaveSubscription):
-		c.String(http.StatusBadRequest, err.Error())
+		errors.Is(err, ErrInvalidInput):
+		problem.Abort(c, http.StatusBadRequest, "invalid_input", err)
+
+	case errors.Is(err, ErrInvalidToken):
+		problem.Abort(c, http.StatusBadRequest, "invalid_token", err)
+
+	case errors.Is(err, ErrTokenNotFound):
+		problem.Abort(c, http.StatusNotFound, "token_not_found", err)
+
+	case errors.Is(err, ErrSubscriberNotFound):
+		problem.Abort(c, http.StatusNotFound, "subscriber_not_found", err)
 
 	case errors.Is(err, ErrEmailAlreadySubscribed):
-		c.String(http.StatusConflict, err.Error())
+		problem.Abort(c, http.StatusConflict, "already_subscribed", err)
+
+	case errors.Is(err, ErrFailedToSaveSubscription),
+		errors.Is(err, ErrFailedToLoadSubscription):
+		problem.Abort(c, http.StatusInternalServerError, "persistence_failed", err)
+
+	case errors.Is(err, ErrFailedToPublish):
+		problem.Abort(c, http.StatusServiceUnavailable, "queue_unavailable", err)
 
 	default:
-		c.String(http.StatusBadRequest, err.Error())
+		problem.AbortUnmapped(c, err)
 	}
 }
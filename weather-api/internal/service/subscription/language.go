@@ -0,0 +1,93 @@
+package subscription
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type Language string
+
+const (
+	LanguageEnglish   Language = "en"
+	LanguageUkrainian Language = "uk"
+)
+
+const DefaultLanguage = LanguageEnglish
+
+var supportedLanguages = map[string]Language{
+	string(LanguageEnglish):   LanguageEnglish,
+	string(LanguageUkrainian): LanguageUkrainian,
+}
+
+// ResolveLanguage picks the email language for a new subscription: the
+// explicitly requested one if supported, otherwise the best supported match
+// from the Accept-Language header, otherwise English.
+func ResolveLanguage(requested string, acceptLanguage string) Language {
+	if lang, ok := ParseLanguage(requested); ok {
+		return lang
+	}
+
+	for _, tag := range parseAcceptLanguage(acceptLanguage) {
+		if lang, ok := ParseLanguage(tag); ok {
+			return lang
+		}
+	}
+
+	return DefaultLanguage
+}
+
+// ParseLanguage accepts a language tag such as "uk" or "uk-UA" and returns the
+// matching supported language.
+func ParseLanguage(tag string) (Language, bool) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+
+	lang, ok := supportedLanguages[tag]
+	return lang, ok
+}
+
+type weightedTag struct {
+	tag    string
+	weight float64
+}
+
+// parseAcceptLanguage returns the tags of an Accept-Language header ordered by
+// their q-value, highest first.
+func parseAcceptLanguage(header string) []string {
+	var tags []weightedTag
+
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		tag := strings.TrimSpace(fields[0])
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		weight := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if q, found := strings.CutPrefix(param, "q="); found {
+				if parsed, err := strconv.ParseFloat(q, 64); err == nil {
+					weight = parsed
+				}
+			}
+		}
+
+		if weight > 0 {
+			tags = append(tags, weightedTag{tag: tag, weight: weight})
+		}
+	}
+
+	sort.SliceStable(tags, func(i, j int) bool {
+		return tags[i].weight > tags[j].weight
+	})
+
+	result := make([]string, 0, len(tags))
+	for _, t := range tags {
+		result = append(result, t.tag)
+	}
+	return result
+}
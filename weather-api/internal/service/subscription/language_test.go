@@ -0,0 +1,34 @@
+//go:build unit
+// +build unit
+
+package subscription
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveLanguage(t *testing.T) {
+	tests := []struct {
+		name           string
+		requested      string
+		acceptLanguage string
+		expected       Language
+	}{
+		{"requested language wins", "uk", "en-US,en;q=0.9", LanguageUkrainian},
+		{"requested region tag", "uk-UA", "", LanguageUkrainian},
+		{"unsupported requested falls back to header", "de", "uk-UA,uk;q=0.9", LanguageUkrainian},
+		{"header ordered by q-value", "", "de;q=1.0,en;q=0.5,uk;q=0.8", LanguageUkrainian},
+		{"header first supported tag", "", "fr-FR,en-GB;q=0.9", LanguageEnglish},
+		{"zero q-value ignored", "", "uk;q=0,en;q=0.1", LanguageEnglish},
+		{"nothing supported", "de", "fr,pl;q=0.5", DefaultLanguage},
+		{"empty", "", "", DefaultLanguage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ResolveLanguage(tt.requested, tt.acceptLanguage))
+		})
+	}
+}
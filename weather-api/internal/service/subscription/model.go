@@ -2,6 +2,7 @@ package subscription
 
 import (
 	"fmt"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -15,11 +16,26 @@ const (
 
 type Subscription struct {
 	gorm.Model           // embeds ID, CreatedAt, UpdatedAt, DeletedAt
-	Email      string    `gorm:"unique;not null"`
-	City       string    `gorm:"not null"`
+	Email      string    `gorm:"uniqueIndex:idx_subscriptions_lower_email_city,expression:lower(email);not null"`
+	City       string    `gorm:"uniqueIndex:idx_subscriptions_lower_email_city;not null"`
 	Frequency  Frequency `gorm:"type:varchar(10);not null"`
 	Token      string    `gorm:"unique;not null"`
 	Confirmed  bool      `gorm:"not null;default:false"`
+	Language   Language  `gorm:"type:varchar(8);not null;default:'en'"`
+
+	// OnlyOnChange subscriptions are only emailed when the weather differs
+	// noticeably from LastSent.
+	OnlyOnChange bool        `gorm:"not null;default:false"`
+	LastSent     SentWeather `gorm:"embedded;embeddedPrefix:last_sent_"`
+}
+
+// SentWeather is the weather of the last email sent to a change-only
+// subscription. At is nil until the first one is sent.
+type SentWeather struct {
+	Temperature float64
+	Humidity    float64
+	Description string
+	At          *time.Time
 }
 
 func ParseFrequency(freq string) (Frequency, error) {
@@ -1,56 +1,83 @@
 package subscription
 
 import (
+	"context"
+	"errors"
 	"net/http"
-	"regexp"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/apiversion"
+	emailvalidation "github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/emailValidation"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/problem"
 
 	"github.com/gin-gonic/gin"
 )
 
 type subscribeService interface {
-	SubscribeForWeatherUpdates(email string, city string, frequency Frequency) error
-	ConfirmSubscription(token string) error
-	Unsubscribe(token string) error
-	GetConfirmedSubscriptionsByFrequency(freq Frequency) []Subscription
-	SendSubscriptionEmails(freq Frequency)
+	SubscribeForWeatherUpdates(ctx context.Context, email string, city string, frequency Frequency,
+		language Language, onlyOnChange bool) error
+	ConfirmSubscription(ctx context.Context, token string) error
+	GetSubscriptionByToken(ctx context.Context, token string) (*Subscription, error)
+	Unsubscribe(ctx context.Context, token string) error
+	GetConfirmedSubscriptionsByFrequency(ctx context.Context, freq Frequency) []Subscription
+	SendSubscriptionEmails(ctx context.Context, freq Frequency)
+}
+
+type emailValidator interface {
+	Validate(ctx context.Context, email string) error
 }
 
 type SubscribeController struct {
-	service subscribeService
+	service        subscribeService
+	emailValidator emailValidator
 }
 
-func NewSubscribeController(service subscribeService) *SubscribeController {
-	return &SubscribeController{service: service}
+func NewSubscribeController(service subscribeService, emailValidator emailValidator) *SubscribeController {
+	return &SubscribeController{service: service, emailValidator: emailValidator}
 }
 
 func (sc *SubscribeController) SubscribeForWeatherUpdates(c *gin.Context) {
 
 	var body struct {
-		Email     string `json:"email"`
-		City      string `json:"city"`
-		Frequency string `json:"frequency"`
+		Email        string `json:"email"`
+		City         string `json:"city"`
+		Frequency    string `json:"frequency"`
+		Language     string `json:"language"`
+		OnlyOnChange bool   `json:"only_on_change"`
 	}
 
 	err := c.ShouldBindJSON(&body)
 
 	if err != nil {
-		c.String(http.StatusBadRequest, "invalid input")
+		problem.AbortInvalidBody(c)
 		return
 	}
 
-	frequency, err := sc.validateSubscriptionInputAndParseFrequency(body.Email, body.City, body.Frequency)
-	if err != nil {
-		HandleError(c, err)
+	frequency, fields := sc.validateSubscriptionInputAndParseFrequency(c.Request.Context(), body.Email, body.City, body.Frequency)
+	if len(fields) > 0 {
+		problem.AbortValidation(c, fields...)
 		return
 	}
 
-	errRes := sc.service.SubscribeForWeatherUpdates(body.Email, body.City, frequency)
+	language := ResolveLanguage(body.Language, c.GetHeader("Accept-Language"))
+
+	errRes := sc.service.SubscribeForWeatherUpdates(c.Request.Context(), body.Email, body.City, frequency,
+		language, body.OnlyOnChange)
 
 	if errRes != nil {
 		HandleError(c, errRes)
 		return
 	}
 
+	if apiversion.From(c) == apiversion.V2 {
+		c.JSON(http.StatusAccepted, StatusResponseV2{
+			Status:    StatusPendingConfirmation,
+			Email:     body.Email,
+			City:      body.City,
+			Frequency: frequency,
+		})
+		return
+	}
+
 	c.String(http.StatusOK, "Subscription successful. Confirmation email sent.")
 }
 
@@ -58,57 +85,109 @@ func (sc *SubscribeController) ConfirmSubscription(c *gin.Context) {
 	token := c.Param("token")
 
 	if token == "" {
-		c.String(http.StatusBadRequest, ErrInvalidToken.Error())
+		HandleError(c, ErrInvalidToken)
 		return
 	}
 
-	err := sc.service.ConfirmSubscription(token)
+	err := sc.service.ConfirmSubscription(c.Request.Context(), token)
 
 	if err != nil {
 		HandleError(c, err)
 		return
 	}
 
+	if apiversion.From(c) == apiversion.V2 {
+		c.JSON(http.StatusOK, StatusResponseV2{Status: StatusConfirmed})
+		return
+	}
+
 	c.String(http.StatusOK, "You confirmed weather update.")
 }
 
+// ConfirmUnsubscribe renders a page asking the user to confirm unsubscribing.
+func (sc *SubscribeController) ConfirmUnsubscribe(c *gin.Context) {
+	token := c.Param("token")
+
+	if token == "" {
+		HandleError(c, ErrInvalidToken)
+		return
+	}
+
+	sub, err := sc.service.GetSubscriptionByToken(c.Request.Context(), token)
+
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	page, err := renderUnsubscribePage(*sub, c.Request.URL.Path)
+	if err != nil {
+		problem.Abort(c, http.StatusInternalServerError, "render_failed", err)
+		return
+	}
+
+	c.Data(http.StatusOK, "text/html; charset=utf-8", page)
+}
+
+// Unsubscribe deletes the subscription. It serves both the confirmation page
+// form and RFC 8058 one-click requests from mail clients.
 func (sc *SubscribeController) Unsubscribe(c *gin.Context) {
 	token := c.Param("token")
 
 	if token == "" {
-		c.String(http.StatusBadRequest, ErrInvalidToken.Error())
+		HandleError(c, ErrInvalidToken)
 		return
 	}
 
-	err := sc.service.Unsubscribe(token)
+	err := sc.service.Unsubscribe(c.Request.Context(), token)
 
 	if err != nil {
 		HandleError(c, err)
 		return
 	}
 
+	if apiversion.From(c) == apiversion.V2 {
+		c.JSON(http.StatusOK, StatusResponseV2{Status: StatusUnsubscribed})
+		return
+	}
+
 	c.String(http.StatusOK, "You unsubscribe from weather update.")
 }
 
-func (sc *SubscribeController) validateSubscriptionInputAndParseFrequency(email string,
-	city string, frequencyStr string) (Frequency, error) {
-	if email == "" || city == "" || frequencyStr == "" {
-		return "", ErrInvalidInput
+// validateSubscriptionInputAndParseFrequency returns an error for every
+// invalid field, so the subscriber can fix them all at once.
+func (sc *SubscribeController) validateSubscriptionInputAndParseFrequency(ctx context.Context, email string,
+	city string, frequencyStr string) (Frequency, []problem.FieldError) {
+	var fields []problem.FieldError
+
+	if email == "" {
+		fields = append(fields, required("email"))
+	} else if err := sc.emailValidator.Validate(ctx, email); err != nil {
+		var validationErr *emailvalidation.ValidationError
+		if !errors.As(err, &validationErr) {
+			validationErr = emailvalidation.ErrInvalidSyntax
+		}
+		fields = append(fields, problem.FieldError{
+			Field: "email", Code: validationErr.Code, Message: validationErr.Message})
 	}
 
-	if !sc.isValidEmail(email) {
-		return "", ErrInvalidInput
+	if city == "" {
+		fields = append(fields, required("city"))
 	}
 
-	frequency, err := ParseFrequency(frequencyStr)
-	if err != nil {
-		return "", ErrInvalidInput
+	var frequency Frequency
+	if frequencyStr == "" {
+		fields = append(fields, required("frequency"))
+	} else if parsed, err := ParseFrequency(frequencyStr); err != nil {
+		fields = append(fields, problem.FieldError{
+			Field: "frequency", Code: "invalid", Message: "frequency must be hourly or daily"})
+	} else {
+		frequency = parsed
 	}
 
-	return frequency, nil
+	return frequency, fields
 }
 
-func (sc *SubscribeController) isValidEmail(email string) bool {
-	re := regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
-	return re.MatchString(email)
+func required(field string) problem.FieldError {
+	return problem.FieldError{Field: field, Code: "required", Message: field + " is required"}
 }
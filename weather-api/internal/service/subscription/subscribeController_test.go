@@ -0,0 +1,131 @@
+//go:build unit
+// +build unit
+
+package subscription
+
+import (
+	"context"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/logger"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/apiversion"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/problem"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/requestid"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+type stubSubscribeService struct {
+	err error
+}
+
+func (s stubSubscribeService) SubscribeForWeatherUpdates(context.Context, string, string, Frequency,
+	Language, bool) error {
+	return s.err
+}
+
+func (s stubSubscribeService) ConfirmSubscription(context.Context, string) error {
+	return s.err
+}
+
+func (s stubSubscribeService) GetSubscriptionByToken(context.Context, string) (*Subscription, error) {
+	return nil, s.err
+}
+
+func (s stubSubscribeService) Unsubscribe(context.Context, string) error {
+	return s.err
+}
+
+func (s stubSubscribeService) GetConfirmedSubscriptionsByFrequency(context.Context, Frequency) []Subscription {
+	return nil
+}
+
+func (s stubSubscribeService) SendSubscriptionEmails(context.Context, Frequency) {}
+
+type acceptAllEmails struct{}
+
+func (acceptAllEmails) Validate(context.Context, string) error { return nil }
+
+func assertGolden(t *testing.T, name string, actual string) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name+".golden")
+
+	if *update {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(actual), 0o644))
+	}
+
+	expected, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, string(expected), actual)
+}
+
+// TestSubscribeController_V1Golden pins the v1 bodies byte for byte, under
+// /api and /api/v1, so response changes can't leak into the old version.
+func TestSubscribeController_V1Golden(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	const validBody = `{"email":"user@example.com","city":"Kyiv","frequency":"daily"}`
+
+	tests := []struct {
+		name        string
+		err         error
+		method      string
+		path        string
+		body        string
+		status      int
+		contentType string
+	}{
+		{"subscribe", nil, http.MethodPost, "/subscribe", validBody,
+			http.StatusOK, "text/plain; charset=utf-8"},
+		{"confirm", nil, http.MethodGet, "/confirm/token", "",
+			http.StatusOK, "text/plain; charset=utf-8"},
+		{"unsubscribe", nil, http.MethodPost, "/unsubscribe/token", "",
+			http.StatusOK, "text/plain; charset=utf-8"},
+		{"subscribe_invalid_body", nil, http.MethodPost, "/subscribe", `{"email":`,
+			http.StatusBadRequest, problem.ContentType},
+		{"subscribe_missing_fields", nil, http.MethodPost, "/subscribe", `{}`,
+			http.StatusBadRequest, problem.ContentType},
+		{"subscribe_already_subscribed", ErrEmailAlreadySubscribed, http.MethodPost, "/subscribe", validBody,
+			http.StatusConflict, problem.ContentType},
+		{"confirm_token_not_found", ErrTokenNotFound, http.MethodGet, "/confirm/token", "",
+			http.StatusNotFound, problem.ContentType},
+	}
+
+	for _, prefix := range []string{"/api", "/api/v1"} {
+		for _, tt := range tests {
+			t.Run(prefix+"/"+tt.name, func(t *testing.T) {
+				mockLog, _ := logger.NewTestLogger()
+				controller := NewSubscribeController(stubSubscribeService{err: tt.err}, acceptAllEmails{})
+
+				router := gin.New()
+				router.Use(requestid.Middleware(), problem.Handler(*mockLog))
+				api := router.Group(prefix, apiversion.Middleware(apiversion.V1))
+				api.POST("/subscribe", controller.SubscribeForWeatherUpdates)
+				api.GET("/confirm/:token", controller.ConfirmSubscription)
+				api.POST("/unsubscribe/:token", controller.Unsubscribe)
+
+				req := httptest.NewRequest(tt.method, prefix+tt.path, strings.NewReader(tt.body))
+				req.Header.Set("Content-Type", "application/json")
+				req.Header.Set(requestid.Header, "test-request")
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				assert.Equal(t, tt.status, w.Code)
+				assert.Equal(t, tt.contentType, w.Header().Get("Content-Type"))
+				dir := strings.ReplaceAll(strings.TrimPrefix(prefix, "/"), "/", "_")
+				assertGolden(t, filepath.Join(dir, tt.name), w.Body.String())
+			})
+		}
+	}
+}
@@ -1,62 +1,80 @@
 package subscription
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
+	"strings"
+	"time"
 
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/logger"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/rabbitmq"
 	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/client"
-	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/rabbitmq"
-	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/logger"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type mailPublisher interface {
-	Publish(queue string, payload any) error
+	Publish(ctx context.Context, queue string, payload any) error
+}
+
+type rabbitMQConsumer interface {
+	Consume(queue string, handler func(ctx context.Context, body []byte) error)
 }
 
 type subscriptionRepository interface {
-	Create(sub Subscription) error
-	Update(sub Subscription) error
-	FindByToken(token string) (*Subscription, error)
-	Delete(sub Subscription) error
-	FindByEmail(email string) (*Subscription, error)
-	FindByFrequencyAndConfirmation(freq Frequency) ([]Subscription, error)
+	Create(ctx context.Context, sub Subscription) error
+	Update(ctx context.Context, sub Subscription) error
+	FindByToken(ctx context.Context, token string) (*Subscription, error)
+	Delete(ctx context.Context, sub Subscription) error
+	FindByEmailAndCity(ctx context.Context, email string, city string) (*Subscription, error)
+	FindAllByEmail(ctx context.Context, email string) ([]Subscription, error)
+	DeactivateByEmail(ctx context.Context, email string) (int64, error)
+	FindByFrequencyAndConfirmation(ctx context.Context, freq Frequency) ([]Subscription, error)
+	UpdateLastSent(ctx context.Context, id uint, sent SentWeather) error
 }
 
 type weatherService interface {
-	GetWeather(city string) (*client.WeatherDTO, error)
+	GetWeather(ctx context.Context, city string) (*client.WeatherDTO, error)
+	GetForecast(ctx context.Context, city string) (*client.ForecastDTO, error)
 }
 
 type SubscribeService struct {
 	weatherService         weatherService
 	subscriptionRepository subscriptionRepository
 	mailPublisher          mailPublisher
+	thresholds             ChangeThresholds
 	logger                 logger.Logger
 }
 
 func NewSubscribeService(weatherService weatherService,
 	repository subscriptionRepository,
-	mailPublisher mailPublisher, logger logger.Logger) *SubscribeService {
+	mailPublisher mailPublisher, thresholds ChangeThresholds,
+	logger logger.Logger) *SubscribeService {
 	return &SubscribeService{
 		weatherService:         weatherService,
 		subscriptionRepository: repository,
 		mailPublisher:          mailPublisher,
+		thresholds:             thresholds,
 		logger:                 logger,
 	}
 }
 
-func (ss *SubscribeService) SubscribeForWeatherUpdates(email string,
-	city string, frequency Frequency) error {
+func (ss *SubscribeService) SubscribeForWeatherUpdates(ctx context.Context, email string,
+	city string, frequency Frequency, language Language, onlyOnChange bool) error {
 
-	if _, err := ss.weatherService.GetWeather(city); err != nil {
+	if _, err := ss.weatherService.GetWeather(ctx, city); err != nil {
 		return err
 	}
 
-	ss.logger.Info("Validating subscription input",
+	ss.logger.WithContext(ctx).Info("Validating subscription input",
 		"email", email,
 		"city", city,
-		"frequency", frequency)
+		"frequency", frequency,
+		"language", language,
+		"onlyOnChange", onlyOnChange)
 
-	subscribed := ss.emailSubscribed(email)
+	subscribed := ss.alreadySubscribed(ctx, email, city)
 	if subscribed {
 		return ErrEmailAlreadySubscribed
 	}
@@ -64,44 +82,47 @@ func (ss *SubscribeService) SubscribeForWeatherUpdates(email string,
 	token := ss.generateToken()
 
 	newSubscription := Subscription{Email: email,
-		City:      city,
-		Frequency: frequency,
-		Token:     token,
-		Confirmed: false,
+		City:         city,
+		Frequency:    frequency,
+		Token:        token,
+		Confirmed:    false,
+		Language:     language,
+		OnlyOnChange: onlyOnChange,
 	}
 
-	if err := ss.subscriptionRepository.Create(newSubscription); err != nil {
-		ss.logger.Error("Failed to create subscription",
+	if err := ss.subscriptionRepository.Create(ctx, newSubscription); err != nil {
+		ss.logger.WithContext(ctx).Error("Failed to create subscription",
 			"email", email,
 			"error", err)
 		return ErrFailedToSaveSubscription
 	}
 
-	ss.logger.Info("Subscription created", "email", email)
+	ss.logger.WithContext(ctx).Info("Subscription created", "email", email)
 
 	job := EmailJob{
+		MessageID:    uuid.New().String(),
 		To:           newSubscription.Email,
 		EmailType:    EmailTypeCreateSubscription,
 		Subscription: newSubscription,
 	}
 
-	err := ss.mailPublisher.Publish(rabbitmq.SendEmail, job)
+	err := ss.mailPublisher.Publish(ctx, rabbitmq.SendEmail, job)
 
 	if err != nil {
-		ss.logger.Error("Failed to publish email job",
+		ss.logger.WithContext(ctx).Error("Failed to publish email job",
 			"email", newSubscription.Email,
 			"error", err)
-		return errors.New("failed to publish email job")
+		return ErrFailedToPublish
 	}
 
-	ss.logger.Info("Subscription email job published", "email", email)
+	ss.logger.WithContext(ctx).Info("Subscription email job published", "email", email)
 
 	return nil
 }
 
-func (ss *SubscribeService) ConfirmSubscription(token string) error {
+func (ss *SubscribeService) ConfirmSubscription(ctx context.Context, token string) error {
 
-	sub, err := ss.subscriptionRepository.FindByToken(token)
+	sub, err := ss.subscriptionRepository.FindByToken(ctx, token)
 
 	if err != nil {
 		return ErrTokenNotFound
@@ -109,8 +130,8 @@ func (ss *SubscribeService) ConfirmSubscription(token string) error {
 
 	sub.Confirmed = true
 
-	if err := ss.subscriptionRepository.Update(*sub); err != nil {
-		ss.logger.Error("Failed to update subscription",
+	if err := ss.subscriptionRepository.Update(ctx, *sub); err != nil {
+		ss.logger.WithContext(ctx).Error("Failed to update subscription",
 			"token", token,
 			"error", err)
 
@@ -118,26 +139,37 @@ func (ss *SubscribeService) ConfirmSubscription(token string) error {
 	}
 
 	job := EmailJob{
+		MessageID:    uuid.New().String(),
 		To:           sub.Email,
 		EmailType:    EmailTypeConfirmSuccess,
 		Subscription: *sub,
 	}
 
-	err = ss.mailPublisher.Publish(rabbitmq.SendEmail, job)
+	err = ss.mailPublisher.Publish(ctx, rabbitmq.SendEmail, job)
 
 	if err != nil {
-		ss.logger.Error("Failed to publish confirmation email job",
+		ss.logger.WithContext(ctx).Error("Failed to publish confirmation email job",
 			"email", sub.Email,
 			"error", err)
-		return errors.New("failed to publish email job")
+		return ErrFailedToPublish
 	}
 
 	return nil
 }
 
-func (ss *SubscribeService) Unsubscribe(token string) error {
+func (ss *SubscribeService) GetSubscriptionByToken(ctx context.Context, token string) (*Subscription, error) {
+	sub, err := ss.subscriptionRepository.FindByToken(ctx, token)
+
+	if err != nil || sub == nil {
+		return nil, ErrTokenNotFound
+	}
+
+	return sub, nil
+}
+
+func (ss *SubscribeService) Unsubscribe(ctx context.Context, token string) error {
 
-	sub, err := ss.subscriptionRepository.FindByToken(token)
+	sub, err := ss.subscriptionRepository.FindByToken(ctx, token)
 
 	if err != nil {
 		return ErrTokenNotFound
@@ -147,8 +179,8 @@ func (ss *SubscribeService) Unsubscribe(token string) error {
 		return nil
 	}
 
-	if err := ss.subscriptionRepository.Delete(*sub); err != nil {
-		ss.logger.Error("Failed to delete subscription",
+	if err := ss.subscriptionRepository.Delete(ctx, *sub); err != nil {
+		ss.logger.WithContext(ctx).Error("Failed to delete subscription",
 			"token", token,
 			"error", err)
 
@@ -158,8 +190,50 @@ func (ss *SubscribeService) Unsubscribe(token string) error {
 	return nil
 }
 
-func (ss *SubscribeService) emailSubscribed(email string) bool {
-	_, err := ss.subscriptionRepository.FindByEmail(email)
+// StartSuppressionWorker deactivates the subscriptions of suppressed
+// addresses. An event whose deactivation fails goes back to the queue.
+func (ss *SubscribeService) StartSuppressionWorker(consumer rabbitMQConsumer) {
+	consumer.Consume(rabbitmq.SubscriptionSuppressed, func(ctx context.Context, body []byte) error {
+		log := ss.logger.WithContext(ctx)
+
+		var event SuppressionEvent
+		if err := json.Unmarshal(body, &event); err != nil {
+			log.Error("Failed to unmarshal SuppressionEvent", "error", err)
+			return nil
+		}
+
+		log.Info("Processing SuppressionEvent", "email", event.Email, "reason", event.Reason)
+
+		if err := ss.DeactivateSubscription(ctx, event.Email); err != nil {
+			log.Error("Failed to deactivate subscription", "email", event.Email, "error", err)
+			return err
+		}
+
+		return nil
+	})
+}
+
+// DeactivateSubscription stops weather updates for an address the mailer
+// can no longer deliver to. The subscriptions are kept, so confirming one
+// again with the original link reactivates it.
+func (ss *SubscribeService) DeactivateSubscription(ctx context.Context, email string) error {
+	deactivated, err := ss.subscriptionRepository.DeactivateByEmail(ctx, email)
+	if err != nil {
+		return ErrFailedToSaveSubscription
+	}
+
+	if deactivated == 0 {
+		ss.logger.WithContext(ctx).Info("No subscription to deactivate", "email", email)
+		return nil
+	}
+
+	ss.logger.WithContext(ctx).Info("Subscriptions deactivated", "email", email, "count", deactivated)
+
+	return nil
+}
+
+func (ss *SubscribeService) alreadySubscribed(ctx context.Context, email string, city string) bool {
+	_, err := ss.subscriptionRepository.FindByEmailAndCity(ctx, email, city)
 
 	return err == nil
 }
@@ -168,39 +242,228 @@ func (ss *SubscribeService) generateToken() string {
 	return uuid.New().String()
 }
 
-func (ss *SubscribeService) SendSubscriptionEmails(freq Frequency) {
-	subs := ss.GetConfirmedSubscriptionsByFrequency(freq)
-	ss.logger.Info("Sending subscription emails",
+// SendSubscriptionEmails sends one email per address: a single city gets
+// a weather update, several cities of the same address are combined into
+// a digest. Change-only subscriptions whose weather has not changed are
+// left out.
+func (ss *SubscribeService) SendSubscriptionEmails(ctx context.Context, freq Frequency) {
+	subs := ss.GetConfirmedSubscriptionsByFrequency(ctx, freq)
+	ss.logger.WithContext(ctx).Info("Sending subscription emails",
 		"frequency", string(freq),
 		"count", len(subs))
 
+	ss.dispatch(ctx, freq, subs)
+}
+
+// SendSubscriberEmails runs the dispatch for a single address outside the
+// schedule, once for each frequency it has confirmed subscriptions for.
+func (ss *SubscribeService) SendSubscriberEmails(ctx context.Context, email string) error {
+	subs, err := ss.subscriptionRepository.FindAllByEmail(ctx, email)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrSubscriberNotFound
+	}
+	if err != nil {
+		ss.logger.WithContext(ctx).Error("Failed to fetch subscriptions",
+			"email", email,
+			"error", err)
+		return ErrFailedToLoadSubscription
+	}
+
+	byFrequency := make(map[Frequency][]Subscription)
 	for _, sub := range subs {
-		weather, err := ss.weatherService.GetWeather(sub.City)
+		if sub.Confirmed {
+			byFrequency[sub.Frequency] = append(byFrequency[sub.Frequency], sub)
+		}
+	}
+
+	if len(byFrequency) == 0 {
+		return ErrSubscriberNotFound
+	}
+
+	for _, freq := range []Frequency{FrequencyHourly, FrequencyDaily} {
+		if len(byFrequency[freq]) > 0 {
+			ss.dispatch(ctx, freq, byFrequency[freq])
+		}
+	}
+
+	return nil
+}
+
+func (ss *SubscribeService) dispatch(ctx context.Context, freq Frequency, subs []Subscription) {
+	for _, group := range groupByEmail(subs) {
+		items := ss.changedItems(ctx, ss.fetchDigestItems(ctx, group))
+
+		var err error
+
+		switch len(items) {
+		case 0:
+			continue
+		case 1:
+			err = ss.publishWeatherUpdate(ctx, items[0])
+		default:
+			err = ss.publishDigest(ctx, freq, items)
+		}
+
+		if err == nil {
+			ss.rememberSent(ctx, items)
+		}
+	}
+}
+
+func (ss *SubscribeService) fetchDigestItems(ctx context.Context, subs []Subscription) []DigestItem {
+	items := make([]DigestItem, 0, len(subs))
+
+	for _, sub := range subs {
+		weather, err := ss.weatherService.GetWeather(ctx, sub.City)
 		if err != nil {
-			ss.logger.Error("Failed to fetch weather data",
+			ss.logger.WithContext(ctx).Error("Failed to fetch weather data",
 				"city", sub.City,
 				"error", err)
 			continue
 		}
 
-		job := WeatherUpdateJob{
-			To:           sub.Email,
-			Subscription: sub,
-			Weather:      *weather}
+		items = append(items, DigestItem{Subscription: sub, Weather: *weather})
+	}
+
+	return items
+}
 
-		if err := ss.mailPublisher.Publish(rabbitmq.WeatherUpdate, job); err != nil {
-			ss.logger.Error("Failed to publish weather update",
+// changedItems drops the change-only subscriptions whose weather is within
+// the thresholds of the last one sent to them.
+func (ss *SubscribeService) changedItems(ctx context.Context, items []DigestItem) []DigestItem {
+	changed := make([]DigestItem, 0, len(items))
+
+	for _, item := range items {
+		sub := item.Subscription
+
+		if sub.OnlyOnChange && !ss.thresholds.Changed(sub.LastSent, item.Weather) {
+			ss.logger.WithContext(ctx).Info("Weather has not changed, skipping update",
 				"email", sub.Email,
+				"city", sub.City)
+			continue
+		}
+
+		changed = append(changed, item)
+	}
+
+	return changed
+}
+
+// rememberSent stores the weather just published for change-only
+// subscriptions, the next cycle compares against it.
+func (ss *SubscribeService) rememberSent(ctx context.Context, items []DigestItem) {
+	now := time.Now()
+
+	for _, item := range items {
+		if !item.Subscription.OnlyOnChange {
+			continue
+		}
+
+		sent := SentWeather{
+			Temperature: item.Weather.Temperature,
+			Humidity:    item.Weather.Humidity,
+			Description: item.Weather.Description,
+			At:          &now,
+		}
+
+		if err := ss.subscriptionRepository.UpdateLastSent(ctx, item.Subscription.ID, sent); err != nil {
+			ss.logger.WithContext(ctx).Error("Failed to save last sent weather",
+				"email", item.Subscription.Email,
+				"city", item.Subscription.City,
 				"error", err)
 		}
 	}
 }
 
-func (ss *SubscribeService) GetConfirmedSubscriptionsByFrequency(freq Frequency) []Subscription {
-	subs, err := ss.subscriptionRepository.FindByFrequencyAndConfirmation(freq)
+func (ss *SubscribeService) publishWeatherUpdate(ctx context.Context, item DigestItem) error {
+	job := WeatherUpdateJob{
+		MessageID:    uuid.New().String(),
+		To:           item.Subscription.Email,
+		Subscription: item.Subscription,
+		Weather:      item.Weather}
+
+	if item.Subscription.Frequency == FrequencyDaily {
+		job.Forecast = ss.fetchForecast(ctx, item.Subscription.City)
+	}
+
+	err := ss.mailPublisher.Publish(ctx, rabbitmq.WeatherUpdate, job)
+	if err != nil {
+		ss.logger.WithContext(ctx).Error("Failed to publish weather update",
+			"email", item.Subscription.Email,
+			"error", err)
+	}
+
+	return err
+}
+
+// fetchForecast returns nil when no provider has a forecast, the daily
+// email then falls back to the current conditions.
+func (ss *SubscribeService) fetchForecast(ctx context.Context, city string) *client.ForecastDTO {
+	forecast, err := ss.weatherService.GetForecast(ctx, city)
+	if err != nil {
+		ss.logger.WithContext(ctx).Error("Failed to fetch forecast, sending current conditions only",
+			"city", city,
+			"error", err)
+		return nil
+	}
+
+	return forecast
+}
+
+func (ss *SubscribeService) publishDigest(ctx context.Context, freq Frequency, items []DigestItem) error {
+	first := items[0].Subscription
+
+	if freq == FrequencyDaily {
+		for i := range items {
+			items[i].Forecast = ss.fetchForecast(ctx, items[i].Subscription.City)
+		}
+	}
+
+	job := WeatherDigestJob{
+		MessageID: uuid.New().String(),
+		To:        first.Email,
+		Frequency: freq,
+		Language:  first.Language,
+		Items:     items,
+	}
+
+	err := ss.mailPublisher.Publish(ctx, rabbitmq.WeatherDigest, job)
+	if err != nil {
+		ss.logger.WithContext(ctx).Error("Failed to publish weather digest",
+			"email", first.Email,
+			"cities", len(items),
+			"error", err)
+	}
+
+	return err
+}
+
+// groupByEmail keeps the order in which addresses first appear. Addresses
+// differing only in case are one mailbox, so they share a group.
+func groupByEmail(subs []Subscription) [][]Subscription {
+	index := make(map[string]int)
+	groups := [][]Subscription{}
+
+	for _, sub := range subs {
+		key := strings.ToLower(sub.Email)
+
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], sub)
+	}
+
+	return groups
+}
+
+func (ss *SubscribeService) GetConfirmedSubscriptionsByFrequency(ctx context.Context, freq Frequency) []Subscription {
+	subs, err := ss.subscriptionRepository.FindByFrequencyAndConfirmation(ctx, freq)
 
 	if err != nil {
-		ss.logger.Error("Failed to fetch confirmed subscriptions",
+		ss.logger.WithContext(ctx).Error("Failed to fetch confirmed subscriptions",
 			"frequency", string(freq),
 			"error", err)
 		return []Subscription{}
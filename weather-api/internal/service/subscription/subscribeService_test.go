@@ -4,14 +4,17 @@
 package subscription
 
 import (
+	"context"
 	"errors"
 	"testing"
+	"time"
 
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/logger"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/rabbitmq"
 	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/client"
-	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/rabbitmq"
-	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/logger"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // --- Mocks ---
@@ -20,17 +23,23 @@ type mockWeatherService struct {
 	mock.Mock
 }
 
-func (m *mockWeatherService) GetWeather(city string) (*client.WeatherDTO, error) {
+func (m *mockWeatherService) GetWeather(_ context.Context, city string) (*client.WeatherDTO, error) {
 	args := m.Called(city)
 	dto, _ := args.Get(0).(*client.WeatherDTO)
 	return dto, args.Error(1)
 }
 
+func (m *mockWeatherService) GetForecast(_ context.Context, city string) (*client.ForecastDTO, error) {
+	args := m.Called(city)
+	dto, _ := args.Get(0).(*client.ForecastDTO)
+	return dto, args.Error(1)
+}
+
 type mockMailPublisher struct {
 	mock.Mock
 }
 
-func (m *mockMailPublisher) Publish(queue string, payload any) error {
+func (m *mockMailPublisher) Publish(_ context.Context, queue string, payload any) error {
 	args := m.Called(queue, payload)
 	return args.Error(0)
 }
@@ -39,34 +48,49 @@ type mockSubscriptionRepository struct {
 	mock.Mock
 }
 
-func (m *mockSubscriptionRepository) Create(sub Subscription) error {
+func (m *mockSubscriptionRepository) Create(_ context.Context, sub Subscription) error {
 	args := m.Called(sub)
 	return args.Error(0)
 }
-func (m *mockSubscriptionRepository) Update(sub Subscription) error {
+func (m *mockSubscriptionRepository) Update(_ context.Context, sub Subscription) error {
 	args := m.Called(sub)
 	return args.Error(0)
 }
-func (m *mockSubscriptionRepository) FindByToken(token string) (*Subscription, error) {
+func (m *mockSubscriptionRepository) FindByToken(_ context.Context, token string) (*Subscription, error) {
 	args := m.Called(token)
 	sub, _ := args.Get(0).(*Subscription)
 	return sub, args.Error(1)
 }
-func (m *mockSubscriptionRepository) Delete(sub Subscription) error {
+func (m *mockSubscriptionRepository) Delete(_ context.Context, sub Subscription) error {
 	args := m.Called(sub)
 	return args.Error(0)
 }
-func (m *mockSubscriptionRepository) FindByEmail(email string) (*Subscription, error) {
-	args := m.Called(email)
+func (m *mockSubscriptionRepository) FindByEmailAndCity(_ context.Context, email string, city string) (*Subscription, error) {
+	args := m.Called(email, city)
 	sub, _ := args.Get(0).(*Subscription)
 	return sub, args.Error(1)
 }
-func (m *mockSubscriptionRepository) FindByFrequencyAndConfirmation(freq Frequency) ([]Subscription, error) {
+func (m *mockSubscriptionRepository) DeactivateByEmail(_ context.Context, email string) (int64, error) {
+	args := m.Called(email)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockSubscriptionRepository) FindAllByEmail(_ context.Context, email string) ([]Subscription, error) {
+	args := m.Called(email)
+	subs, _ := args.Get(0).([]Subscription)
+	return subs, args.Error(1)
+}
+func (m *mockSubscriptionRepository) FindByFrequencyAndConfirmation(_ context.Context, freq Frequency) ([]Subscription, error) {
 	args := m.Called(freq)
 	subs, _ := args.Get(0).([]Subscription)
 	return subs, args.Error(1)
 }
 
+func (m *mockSubscriptionRepository) UpdateLastSent(_ context.Context, id uint, sent SentWeather) error {
+	args := m.Called(id, sent)
+	return args.Error(0)
+}
+
 // --- Tests ---
 
 func TestSubscribeForWeatherUpdates_Success(t *testing.T) {
@@ -75,8 +99,10 @@ func TestSubscribeForWeatherUpdates_Success(t *testing.T) {
 	mockRepo := new(mockSubscriptionRepository)
 
 	mockWeather.On("GetWeather", "Kyiv").Return(&client.WeatherDTO{}, nil)
-	mockRepo.On("FindByEmail", "test@example.com").Return(nil, errors.New("record not found"))
-	mockRepo.On("Create", mock.AnythingOfType("Subscription")).Return(nil)
+	mockRepo.On("FindByEmailAndCity", "test@example.com", "Kyiv").Return(nil, errors.New("record not found"))
+	mockRepo.On("Create", mock.MatchedBy(func(sub Subscription) bool {
+		return sub.Language == LanguageUkrainian && sub.OnlyOnChange
+	})).Return(nil)
 	mockPublisher.On("Publish", rabbitmq.SendEmail, mock.AnythingOfType("EmailJob")).Return(nil)
 	mockLogger, _ := logger.NewTestLogger()
 
@@ -91,7 +117,7 @@ func TestSubscribeForWeatherUpdates_Success(t *testing.T) {
 	city := "Kyiv"
 	freq := Frequency("daily")
 
-	err := service.SubscribeForWeatherUpdates(email, city, freq)
+	err := service.SubscribeForWeatherUpdates(context.Background(), email, city, freq, LanguageUkrainian, true)
 	assert.NoError(t, err)
 	mockWeather.AssertExpectations(t)
 	mockRepo.AssertExpectations(t)
@@ -112,7 +138,7 @@ func TestSubscribeForWeatherUpdates_WeatherServiceError(t *testing.T) {
 		logger:                 *mockLogger,
 	}
 
-	err := service.SubscribeForWeatherUpdates("test@example.com", "Kyiv", Frequency("daily"))
+	err := service.SubscribeForWeatherUpdates(context.Background(), "test@example.com", "Kyiv", Frequency("daily"), LanguageEnglish, false)
 
 	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
 	mockPublisher.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything)
@@ -126,7 +152,7 @@ func TestSubscribeForWeatherUpdates_EmailAlreadySubscribed(t *testing.T) {
 	mockRepo := new(mockSubscriptionRepository)
 
 	mockWeather.On("GetWeather", "Kyiv").Return(&client.WeatherDTO{}, nil)
-	mockRepo.On("FindByEmail", "test@example.com").Return(&Subscription{Email: "test@example.com"}, nil)
+	mockRepo.On("FindByEmailAndCity", "test@example.com", "Kyiv").Return(&Subscription{Email: "test@example.com"}, nil)
 	mockLogger, _ := logger.NewTestLogger()
 
 	service := &SubscribeService{
@@ -136,7 +162,7 @@ func TestSubscribeForWeatherUpdates_EmailAlreadySubscribed(t *testing.T) {
 		logger:                 *mockLogger,
 	}
 
-	err := service.SubscribeForWeatherUpdates("test@example.com", "Kyiv", Frequency("daily"))
+	err := service.SubscribeForWeatherUpdates(context.Background(), "test@example.com", "Kyiv", Frequency("daily"), LanguageEnglish, false)
 	assert.Equal(t, ErrEmailAlreadySubscribed, err)
 
 	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
@@ -151,7 +177,7 @@ func TestSubscribeForWeatherUpdates_CreateError(t *testing.T) {
 	mockRepo := new(mockSubscriptionRepository)
 
 	mockWeather.On("GetWeather", "Kyiv").Return(&client.WeatherDTO{}, nil)
-	mockRepo.On("FindByEmail", "test@example.com").Return(nil, errors.New("record not found"))
+	mockRepo.On("FindByEmailAndCity", "test@example.com", "Kyiv").Return(nil, errors.New("record not found"))
 	mockRepo.On("Create", mock.AnythingOfType("Subscription")).Return(errors.New("db error"))
 	mockLogger, _ := logger.NewTestLogger()
 
@@ -162,7 +188,7 @@ func TestSubscribeForWeatherUpdates_CreateError(t *testing.T) {
 		logger:                 *mockLogger,
 	}
 
-	err := service.SubscribeForWeatherUpdates("test@example.com", "Kyiv", Frequency("daily"))
+	err := service.SubscribeForWeatherUpdates(context.Background(), "test@example.com", "Kyiv", Frequency("daily"), LanguageEnglish, false)
 	assert.Equal(t, ErrFailedToSaveSubscription, err)
 	mockPublisher.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything)
 	mockWeather.AssertExpectations(t)
@@ -195,7 +221,7 @@ func TestConfirmSubscription_Success(t *testing.T) {
 		logger:                 *mockLogger,
 	}
 
-	err := service.ConfirmSubscription("token123")
+	err := service.ConfirmSubscription(context.Background(), "token123")
 	assert.NoError(t, err)
 	mockRepo.AssertExpectations(t)
 	mockPublisher.AssertExpectations(t)
@@ -214,7 +240,7 @@ func TestConfirmSubscription_TokenNotFound(t *testing.T) {
 		logger:                 *mockLogger,
 	}
 
-	err := service.ConfirmSubscription("invalid-token")
+	err := service.ConfirmSubscription(context.Background(), "invalid-token")
 
 	mockPublisher.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything)
 	assert.Equal(t, ErrTokenNotFound, err)
@@ -242,12 +268,45 @@ func TestConfirmSubscription_UpdateError(t *testing.T) {
 		logger:                 *mockLogger,
 	}
 
-	err := service.ConfirmSubscription("token123")
+	err := service.ConfirmSubscription(context.Background(), "token123")
 
 	mockPublisher.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything)
 	assert.Equal(t, ErrFailedToSaveSubscription, err)
 	mockRepo.AssertExpectations(t)
 }
+func TestGetSubscriptionByToken_Success(t *testing.T) {
+	mockRepo := new(mockSubscriptionRepository)
+	mockSub := &Subscription{Email: "test@example.com", City: "Kyiv", Token: "token123"}
+
+	mockRepo.On("FindByToken", "token123").Return(mockSub, nil)
+	mockLogger, _ := logger.NewTestLogger()
+	service := &SubscribeService{
+		subscriptionRepository: mockRepo,
+		logger:                 *mockLogger,
+	}
+
+	sub, err := service.GetSubscriptionByToken(context.Background(), "token123")
+	assert.NoError(t, err)
+	assert.Equal(t, mockSub, sub)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetSubscriptionByToken_NotFound(t *testing.T) {
+	mockRepo := new(mockSubscriptionRepository)
+
+	mockRepo.On("FindByToken", "invalid-token").Return(nil, errors.New("not found"))
+	mockLogger, _ := logger.NewTestLogger()
+	service := &SubscribeService{
+		subscriptionRepository: mockRepo,
+		logger:                 *mockLogger,
+	}
+
+	sub, err := service.GetSubscriptionByToken(context.Background(), "invalid-token")
+	assert.Nil(t, sub)
+	assert.Equal(t, ErrTokenNotFound, err)
+	mockRepo.AssertExpectations(t)
+}
+
 func TestUnsubscribe_Success(t *testing.T) {
 	mockRepo := new(mockSubscriptionRepository)
 	mockSub := &Subscription{
@@ -266,7 +325,7 @@ func TestUnsubscribe_Success(t *testing.T) {
 		logger:                 *mockLogger,
 	}
 
-	err := service.Unsubscribe("token123")
+	err := service.Unsubscribe(context.Background(), "token123")
 	assert.NoError(t, err)
 	mockRepo.AssertExpectations(t)
 }
@@ -280,7 +339,7 @@ func TestUnsubscribe_TokenNotFound(t *testing.T) {
 		logger:                 *mockLogger,
 	}
 
-	err := service.Unsubscribe("invalid-token")
+	err := service.Unsubscribe(context.Background(), "invalid-token")
 	assert.Equal(t, ErrTokenNotFound, err)
 
 	mockRepo.AssertNotCalled(t, "Delete", mock.Anything)
@@ -297,7 +356,7 @@ func TestUnsubscribe_SubscriptionNil(t *testing.T) {
 		logger:                 *mockLogger,
 	}
 
-	err := service.Unsubscribe("token123")
+	err := service.Unsubscribe(context.Background(), "token123")
 	assert.NoError(t, err)
 
 	mockRepo.AssertNotCalled(t, "Delete", mock.Anything)
@@ -323,13 +382,13 @@ func TestUnsubscribe_DeleteError(t *testing.T) {
 		logger:                 *mockLogger,
 	}
 
-	err := service.Unsubscribe("token123")
+	err := service.Unsubscribe(context.Background(), "token123")
 	assert.Equal(t, ErrInvalidInput, err)
 	mockRepo.AssertExpectations(t)
 }
-func TestEmailSubscribed_ReturnsTrueWhenSubscribed(t *testing.T) {
+func TestAlreadySubscribed_ReturnsTrueWhenSubscribed(t *testing.T) {
 	mockRepo := new(mockSubscriptionRepository)
-	mockRepo.On("FindByEmail", "test@example.com").Return(&Subscription{Email: "test@example.com"}, nil)
+	mockRepo.On("FindByEmailAndCity", "test@example.com", "Kyiv").Return(&Subscription{Email: "test@example.com"}, nil)
 	mockLogger, _ := logger.NewTestLogger()
 
 	service := &SubscribeService{
@@ -337,22 +396,22 @@ func TestEmailSubscribed_ReturnsTrueWhenSubscribed(t *testing.T) {
 		logger:                 *mockLogger,
 	}
 
-	subscribed := service.emailSubscribed("test@example.com")
+	subscribed := service.alreadySubscribed(context.Background(), "test@example.com", "Kyiv")
 	assert.True(t, subscribed)
 
 	mockRepo.AssertExpectations(t)
 }
 
-func TestEmailSubscribed_ReturnsError(t *testing.T) {
+func TestAlreadySubscribed_ReturnsError(t *testing.T) {
 	mockRepo := new(mockSubscriptionRepository)
-	mockRepo.On("FindByEmail", "test@example.com").Return(nil, errors.New("db error"))
+	mockRepo.On("FindByEmailAndCity", "test@example.com", "Kyiv").Return(nil, errors.New("db error"))
 	mockLogger, _ := logger.NewTestLogger()
 	service := &SubscribeService{
 		subscriptionRepository: mockRepo,
 		logger:                 *mockLogger,
 	}
 
-	subscribed := service.emailSubscribed("test@example.com")
+	subscribed := service.alreadySubscribed(context.Background(), "test@example.com", "Kyiv")
 	assert.False(t, subscribed)
 
 	mockRepo.AssertExpectations(t)
@@ -371,7 +430,7 @@ func TestGetConfirmedSubscriptionsByFrequency_ReturnsSubscriptions(t *testing.T)
 		logger:                 *mockLogger,
 	}
 
-	result := service.GetConfirmedSubscriptionsByFrequency(Frequency("daily"))
+	result := service.GetConfirmedSubscriptionsByFrequency(context.Background(), Frequency("daily"))
 	assert.Equal(t, expectedSubs, result)
 	mockRepo.AssertExpectations(t)
 }
@@ -385,7 +444,7 @@ func TestGetConfirmedSubscriptionsByFrequency_RepoError_ReturnsEmptySlice(t *tes
 		logger:                 *mockLogger,
 	}
 
-	result := service.GetConfirmedSubscriptionsByFrequency(Frequency("daily"))
+	result := service.GetConfirmedSubscriptionsByFrequency(context.Background(), Frequency("daily"))
 	assert.Empty(t, result)
 	mockRepo.AssertExpectations(t)
 }
@@ -403,6 +462,8 @@ func TestSendSubscriptionEmails_SendsEmails(t *testing.T) {
 	mockRepo.On("FindByFrequencyAndConfirmation", Frequency("daily")).Return(subs, nil)
 	mockWeather.On("GetWeather", "Kyiv").Return(&client.WeatherDTO{Temperature: 10}, nil)
 	mockWeather.On("GetWeather", "Lviv").Return(&client.WeatherDTO{Temperature: 20}, nil)
+	mockWeather.On("GetForecast", "Kyiv").Return(&client.ForecastDTO{MaxTemperature: 14}, nil)
+	mockWeather.On("GetForecast", "Lviv").Return(&client.ForecastDTO{MaxTemperature: 22}, nil)
 
 	mockPublisher.On("Publish", rabbitmq.WeatherUpdate, mock.AnythingOfType("WeatherUpdateJob")).Return(nil).Twice()
 
@@ -416,7 +477,7 @@ func TestSendSubscriptionEmails_SendsEmails(t *testing.T) {
 		logger:                 *mockLogger,
 	}
 
-	service.SendSubscriptionEmails(Frequency("daily"))
+	service.SendSubscriptionEmails(context.Background(), Frequency("daily"))
 	mockRepo.AssertExpectations(t)
 	mockWeather.AssertExpectations(t)
 	mockPublisher.AssertExpectations(t)
@@ -441,10 +502,370 @@ func TestSendSubscriptionEmails_WeatherError_SkipsEmail(t *testing.T) {
 		logger:                 *mockLogger,
 	}
 
-	service.SendSubscriptionEmails(Frequency("daily"))
+	service.SendSubscriptionEmails(context.Background(), Frequency("daily"))
 
 	mockPublisher.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything)
 
 	mockRepo.AssertExpectations(t)
 	mockWeather.AssertExpectations(t)
 }
+
+func TestDeactivateSubscription_Success(t *testing.T) {
+	mockRepo := new(mockSubscriptionRepository)
+
+	mockRepo.On("DeactivateByEmail", "test@example.com").Return(int64(2), nil).Once()
+
+	mockLogger, _ := logger.NewTestLogger()
+
+	service := &SubscribeService{
+		subscriptionRepository: mockRepo,
+		logger:                 *mockLogger,
+	}
+
+	err := service.DeactivateSubscription(context.Background(), "test@example.com")
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestDeactivateSubscription_NotFound(t *testing.T) {
+	mockRepo := new(mockSubscriptionRepository)
+
+	mockRepo.On("DeactivateByEmail", "test@example.com").Return(int64(0), nil)
+
+	mockLogger, _ := logger.NewTestLogger()
+
+	service := &SubscribeService{
+		subscriptionRepository: mockRepo,
+		logger:                 *mockLogger,
+	}
+
+	err := service.DeactivateSubscription(context.Background(), "test@example.com")
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestDeactivateSubscription_UpdateError(t *testing.T) {
+	mockRepo := new(mockSubscriptionRepository)
+	mockRepo.On("DeactivateByEmail", "test@example.com").Return(int64(0), errors.New("db error"))
+
+	mockLogger, _ := logger.NewTestLogger()
+
+	service := &SubscribeService{
+		subscriptionRepository: mockRepo,
+		logger:                 *mockLogger,
+	}
+
+	err := service.DeactivateSubscription(context.Background(), "test@example.com")
+	assert.ErrorIs(t, err, ErrFailedToSaveSubscription)
+}
+
+// capturingConsumer keeps the handlers registered per queue.
+type capturingConsumer struct {
+	handlers map[string]func(ctx context.Context, body []byte) error
+}
+
+func (c *capturingConsumer) Consume(queue string, handler func(ctx context.Context, body []byte) error) {
+	if c.handlers == nil {
+		c.handlers = map[string]func(ctx context.Context, body []byte) error{}
+	}
+	c.handlers[queue] = handler
+}
+
+func TestSuppressionWorker_FailedDeactivationIsReturned(t *testing.T) {
+	mockRepo := new(mockSubscriptionRepository)
+	mockRepo.On("DeactivateByEmail", "test@example.com").Return(int64(0), errors.New("db down")).Once()
+	mockRepo.On("DeactivateByEmail", "test@example.com").Return(int64(1), nil).Once()
+
+	mockLogger, _ := logger.NewTestLogger()
+	service := &SubscribeService{
+		subscriptionRepository: mockRepo,
+		logger:                 *mockLogger,
+	}
+
+	consumer := &capturingConsumer{}
+	service.StartSuppressionWorker(consumer)
+	handle := consumer.handlers[rabbitmq.SubscriptionSuppressed]
+
+	body := []byte(`{"Email":"test@example.com","Reason":"bounce"}`)
+	assert.ErrorIs(t, handle(context.Background(), body), ErrFailedToSaveSubscription)
+	assert.NoError(t, handle(context.Background(), body))
+	assert.NoError(t, handle(context.Background(), []byte("not json")))
+}
+
+func TestSendSubscriptionEmails_GroupsCitiesIntoDigest(t *testing.T) {
+	mockRepo := new(mockSubscriptionRepository)
+	mockWeather := new(mockWeatherService)
+	mockPublisher := new(mockMailPublisher)
+	mockLogger, _ := logger.NewTestLogger()
+
+	subs := []Subscription{
+		{Email: "multi@example.com", City: "Kyiv", Frequency: FrequencyDaily, Confirmed: true, Language: LanguageUkrainian},
+		{Email: "single@example.com", City: "Odesa", Frequency: FrequencyDaily, Confirmed: true},
+		{Email: "multi@example.com", City: "Lviv", Frequency: FrequencyDaily, Confirmed: true, Language: LanguageUkrainian},
+	}
+	mockRepo.On("FindByFrequencyAndConfirmation", FrequencyDaily).Return(subs, nil)
+	mockWeather.On("GetWeather", "Kyiv").Return(&client.WeatherDTO{Temperature: 10}, nil)
+	mockWeather.On("GetWeather", "Lviv").Return(&client.WeatherDTO{Temperature: 12}, nil)
+	mockWeather.On("GetWeather", "Odesa").Return(&client.WeatherDTO{Temperature: 20}, nil)
+	mockWeather.On("GetForecast", "Kyiv").Return(&client.ForecastDTO{MaxTemperature: 14}, nil)
+	mockWeather.On("GetForecast", "Lviv").Return(&client.ForecastDTO{MaxTemperature: 16}, nil)
+	mockWeather.On("GetForecast", "Odesa").Return(&client.ForecastDTO{MaxTemperature: 25}, nil)
+
+	mockPublisher.On("Publish", rabbitmq.WeatherDigest, mock.MatchedBy(func(job WeatherDigestJob) bool {
+		return job.To == "multi@example.com" && job.Language == LanguageUkrainian &&
+			len(job.Items) == 2 && job.Items[0].Subscription.City == "Kyiv" &&
+			job.Items[1].Weather.Temperature == 12 && job.MessageID != ""
+	})).Return(nil).Once()
+	mockPublisher.On("Publish", rabbitmq.WeatherUpdate, mock.MatchedBy(func(job WeatherUpdateJob) bool {
+		return job.To == "single@example.com" && job.Forecast != nil && job.Forecast.MaxTemperature == 25
+	})).Return(nil).Once()
+
+	service := &SubscribeService{
+		weatherService:         mockWeather,
+		mailPublisher:          mockPublisher,
+		subscriptionRepository: mockRepo,
+		logger:                 *mockLogger,
+	}
+
+	service.SendSubscriptionEmails(context.Background(), FrequencyDaily)
+
+	mockPublisher.AssertExpectations(t)
+}
+
+func TestSendSubscriptionEmails_DailyDigestCarriesForecasts(t *testing.T) {
+	mockRepo := new(mockSubscriptionRepository)
+	mockWeather := new(mockWeatherService)
+	mockPublisher := new(mockMailPublisher)
+	mockLogger, _ := logger.NewTestLogger()
+
+	subs := []Subscription{
+		{Email: "multi@example.com", City: "Kyiv", Frequency: FrequencyDaily, Confirmed: true},
+		{Email: "multi@example.com", City: "Lviv", Frequency: FrequencyDaily, Confirmed: true},
+	}
+	mockRepo.On("FindByFrequencyAndConfirmation", FrequencyDaily).Return(subs, nil)
+	mockWeather.On("GetWeather", "Kyiv").Return(&client.WeatherDTO{Temperature: 10}, nil)
+	mockWeather.On("GetWeather", "Lviv").Return(&client.WeatherDTO{Temperature: 12}, nil)
+	mockWeather.On("GetForecast", "Kyiv").Return(&client.ForecastDTO{MaxTemperature: 14}, nil)
+	mockWeather.On("GetForecast", "Lviv").Return(nil, errors.New("forecast error"))
+
+	var published WeatherDigestJob
+	mockPublisher.On("Publish", rabbitmq.WeatherDigest, mock.AnythingOfType("WeatherDigestJob")).
+		Run(func(args mock.Arguments) { published = args.Get(1).(WeatherDigestJob) }).Return(nil).Once()
+
+	service := &SubscribeService{
+		weatherService:         mockWeather,
+		mailPublisher:          mockPublisher,
+		subscriptionRepository: mockRepo,
+		logger:                 *mockLogger,
+	}
+
+	service.SendSubscriptionEmails(context.Background(), FrequencyDaily)
+
+	mockPublisher.AssertExpectations(t)
+	require.Len(t, published.Items, 2)
+	require.NotNil(t, published.Items[0].Forecast)
+	assert.Equal(t, 14.0, published.Items[0].Forecast.MaxTemperature)
+	assert.Nil(t, published.Items[1].Forecast, "a city without a forecast keeps its current conditions")
+}
+
+func TestGroupByEmail_IgnoresCase(t *testing.T) {
+	subs := []Subscription{
+		{Email: "Multi@Example.com", City: "Kyiv"},
+		{Email: "single@example.com", City: "Odesa"},
+		{Email: "multi@example.com", City: "Lviv"},
+	}
+
+	groups := groupByEmail(subs)
+
+	require.Len(t, groups, 2)
+	assert.Equal(t, []Subscription{subs[0], subs[2]}, groups[0])
+	assert.Equal(t, []Subscription{subs[1]}, groups[1])
+}
+
+func TestSendSubscriptionEmails_DigestFallsBackToSingleUpdate(t *testing.T) {
+	mockRepo := new(mockSubscriptionRepository)
+	mockWeather := new(mockWeatherService)
+	mockPublisher := new(mockMailPublisher)
+	mockLogger, _ := logger.NewTestLogger()
+
+	subs := []Subscription{
+		{Email: "multi@example.com", City: "Kyiv", Frequency: FrequencyHourly, Confirmed: true},
+		{Email: "multi@example.com", City: "Atlantis", Frequency: FrequencyHourly, Confirmed: true},
+	}
+	mockRepo.On("FindByFrequencyAndConfirmation", FrequencyHourly).Return(subs, nil)
+	mockWeather.On("GetWeather", "Kyiv").Return(&client.WeatherDTO{Temperature: 10}, nil)
+	mockWeather.On("GetWeather", "Atlantis").Return(nil, errors.New("city not found"))
+
+	mockPublisher.On("Publish", rabbitmq.WeatherUpdate, mock.AnythingOfType("WeatherUpdateJob")).Return(nil).Once()
+
+	service := &SubscribeService{
+		weatherService:         mockWeather,
+		mailPublisher:          mockPublisher,
+		subscriptionRepository: mockRepo,
+		logger:                 *mockLogger,
+	}
+
+	service.SendSubscriptionEmails(context.Background(), FrequencyHourly)
+
+	mockPublisher.AssertExpectations(t)
+	mockPublisher.AssertNotCalled(t, "Publish", rabbitmq.WeatherDigest, mock.Anything)
+}
+
+func TestSendSubscriptionEmails_HourlyHasNoForecast(t *testing.T) {
+	mockRepo := new(mockSubscriptionRepository)
+	mockWeather := new(mockWeatherService)
+	mockPublisher := new(mockMailPublisher)
+	mockLogger, _ := logger.NewTestLogger()
+
+	subs := []Subscription{{Email: "test@example.com", City: "Kyiv", Frequency: FrequencyHourly, Confirmed: true}}
+	mockRepo.On("FindByFrequencyAndConfirmation", FrequencyHourly).Return(subs, nil)
+	mockWeather.On("GetWeather", "Kyiv").Return(&client.WeatherDTO{Temperature: 10}, nil)
+	mockPublisher.On("Publish", rabbitmq.WeatherUpdate, mock.MatchedBy(func(job WeatherUpdateJob) bool {
+		return job.Forecast == nil
+	})).Return(nil).Once()
+
+	service := &SubscribeService{
+		weatherService:         mockWeather,
+		mailPublisher:          mockPublisher,
+		subscriptionRepository: mockRepo,
+		logger:                 *mockLogger,
+	}
+
+	service.SendSubscriptionEmails(context.Background(), FrequencyHourly)
+
+	mockPublisher.AssertExpectations(t)
+	mockWeather.AssertNotCalled(t, "GetForecast", mock.Anything)
+}
+
+func TestSendSubscriptionEmails_ForecastError_SendsCurrentConditions(t *testing.T) {
+	mockRepo := new(mockSubscriptionRepository)
+	mockWeather := new(mockWeatherService)
+	mockPublisher := new(mockMailPublisher)
+	mockLogger, _ := logger.NewTestLogger()
+
+	subs := []Subscription{{Email: "test@example.com", City: "Kyiv", Frequency: FrequencyDaily, Confirmed: true}}
+	mockRepo.On("FindByFrequencyAndConfirmation", FrequencyDaily).Return(subs, nil)
+	mockWeather.On("GetWeather", "Kyiv").Return(&client.WeatherDTO{Temperature: 10}, nil)
+	mockWeather.On("GetForecast", "Kyiv").Return(nil, errors.New("forecast error"))
+	mockPublisher.On("Publish", rabbitmq.WeatherUpdate, mock.MatchedBy(func(job WeatherUpdateJob) bool {
+		return job.Forecast == nil && job.Weather.Temperature == 10
+	})).Return(nil).Once()
+
+	service := &SubscribeService{
+		weatherService:         mockWeather,
+		mailPublisher:          mockPublisher,
+		subscriptionRepository: mockRepo,
+		logger:                 *mockLogger,
+	}
+
+	service.SendSubscriptionEmails(context.Background(), FrequencyDaily)
+
+	mockPublisher.AssertExpectations(t)
+}
+
+func TestSendSubscriptionEmails_OnlyOnChange(t *testing.T) {
+	sentAt := time.Now().Add(-time.Hour)
+	last := SentWeather{Temperature: 10, Humidity: 50, Description: "Sunny", At: &sentAt}
+
+	unchanged := Subscription{Email: "same@example.com", City: "Kyiv", Frequency: FrequencyHourly,
+		Confirmed: true, OnlyOnChange: true, LastSent: last}
+	unchanged.ID = 1
+	changed := Subscription{Email: "changed@example.com", City: "Lviv", Frequency: FrequencyHourly,
+		Confirmed: true, OnlyOnChange: true, LastSent: last}
+	changed.ID = 2
+
+	mockRepo := new(mockSubscriptionRepository)
+	mockWeather := new(mockWeatherService)
+	mockPublisher := new(mockMailPublisher)
+	mockLogger, _ := logger.NewTestLogger()
+
+	mockRepo.On("FindByFrequencyAndConfirmation", FrequencyHourly).Return([]Subscription{unchanged, changed}, nil)
+	mockWeather.On("GetWeather", "Kyiv").Return(&client.WeatherDTO{Temperature: 11, Humidity: 55, Description: "sunny"}, nil)
+	mockWeather.On("GetWeather", "Lviv").Return(&client.WeatherDTO{Temperature: 10, Humidity: 50, Description: "Light rain"}, nil)
+	mockPublisher.On("Publish", rabbitmq.WeatherUpdate, mock.MatchedBy(func(job WeatherUpdateJob) bool {
+		return job.To == "changed@example.com"
+	})).Return(nil).Once()
+	mockRepo.On("UpdateLastSent", uint(2), mock.MatchedBy(func(sent SentWeather) bool {
+		return sent.Description == "Light rain" && sent.At != nil
+	})).Return(nil).Once()
+
+	service := NewSubscribeService(mockWeather, mockRepo, mockPublisher,
+		ChangeThresholds{Temperature: 2, Humidity: 15}, *mockLogger)
+
+	service.SendSubscriptionEmails(context.Background(), FrequencyHourly)
+
+	mockPublisher.AssertExpectations(t)
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "UpdateLastSent", uint(1), mock.Anything)
+}
+
+func TestSendSubscriptionEmails_OnlyOnChange_PublishErrorKeepsLastSent(t *testing.T) {
+	sub := Subscription{Email: "test@example.com", City: "Kyiv", Frequency: FrequencyHourly,
+		Confirmed: true, OnlyOnChange: true}
+
+	mockRepo := new(mockSubscriptionRepository)
+	mockWeather := new(mockWeatherService)
+	mockPublisher := new(mockMailPublisher)
+	mockLogger, _ := logger.NewTestLogger()
+
+	mockRepo.On("FindByFrequencyAndConfirmation", FrequencyHourly).Return([]Subscription{sub}, nil)
+	mockWeather.On("GetWeather", "Kyiv").Return(&client.WeatherDTO{Temperature: 10}, nil)
+	mockPublisher.On("Publish", rabbitmq.WeatherUpdate, mock.Anything).Return(errors.New("broker down"))
+
+	service := NewSubscribeService(mockWeather, mockRepo, mockPublisher,
+		ChangeThresholds{Temperature: 2, Humidity: 15}, *mockLogger)
+
+	service.SendSubscriptionEmails(context.Background(), FrequencyHourly)
+
+	mockRepo.AssertNotCalled(t, "UpdateLastSent", mock.Anything, mock.Anything)
+}
+
+func TestSendSubscriberEmails(t *testing.T) {
+	mockRepo := new(mockSubscriptionRepository)
+	mockWeather := new(mockWeatherService)
+	mockPublisher := new(mockMailPublisher)
+	mockLogger, _ := logger.NewTestLogger()
+
+	subs := []Subscription{
+		{Email: "test@example.com", City: "Kyiv", Frequency: FrequencyHourly, Confirmed: true},
+		{Email: "test@example.com", City: "Lviv", Frequency: FrequencyHourly, Confirmed: false},
+	}
+	mockRepo.On("FindAllByEmail", "test@example.com").Return(subs, nil)
+	mockWeather.On("GetWeather", "Kyiv").Return(&client.WeatherDTO{Temperature: 10}, nil)
+	mockPublisher.On("Publish", rabbitmq.WeatherUpdate, mock.MatchedBy(func(job WeatherUpdateJob) bool {
+		return job.Subscription.City == "Kyiv"
+	})).Return(nil).Once()
+
+	service := NewSubscribeService(mockWeather, mockRepo, mockPublisher, ChangeThresholds{}, *mockLogger)
+
+	assert.NoError(t, service.SendSubscriberEmails(context.Background(), "test@example.com"))
+	mockPublisher.AssertExpectations(t)
+	mockWeather.AssertNotCalled(t, "GetWeather", "Lviv")
+}
+
+func TestSendSubscriberEmails_NoConfirmedSubscriptions(t *testing.T) {
+	mockRepo := new(mockSubscriptionRepository)
+	mockLogger, _ := logger.NewTestLogger()
+
+	mockRepo.On("FindAllByEmail", "test@example.com").
+		Return([]Subscription{{Email: "test@example.com", City: "Kyiv", Confirmed: false}}, nil)
+
+	service := NewSubscribeService(new(mockWeatherService), mockRepo, new(mockMailPublisher),
+		ChangeThresholds{}, *mockLogger)
+
+	assert.ErrorIs(t, service.SendSubscriberEmails(context.Background(), "test@example.com"), ErrSubscriberNotFound)
+}
+
+func TestSendSubscriberEmails_LookupErrorIsNotNotFound(t *testing.T) {
+	mockRepo := new(mockSubscriptionRepository)
+	mockLogger, _ := logger.NewTestLogger()
+
+	mockRepo.On("FindAllByEmail", "test@example.com").Return(nil, errors.New("connection refused"))
+
+	service := NewSubscribeService(new(mockWeatherService), mockRepo, new(mockMailPublisher),
+		ChangeThresholds{}, *mockLogger)
+
+	err := service.SendSubscriberEmails(context.Background(), "test@example.com")
+	assert.ErrorIs(t, err, ErrFailedToLoadSubscription)
+	assert.NotErrorIs(t, err, ErrSubscriberNotFound)
+}
@@ -0,0 +1,84 @@
+package subscription
+
+import (
+	"bytes"
+	"html/template"
+)
+
+// The unsubscribe link in emails opens this page instead of deleting the
+// subscription right away, because mail scanners prefetch GET links.
+// Submitting the form POSTs to the same URL as the one-click endpoint.
+var unsubscribePage = template.Must(template.New("unsubscribe").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="UTF-8" />
+  <title>Unsubscribe</title>
+  <style>
+    body {
+      margin: 0;
+      font-family: 'Segoe UI', Tahoma, Geneva, Verdana, sans-serif;
+      background-color: #f3e8ff;
+      display: flex;
+      align-items: center;
+      justify-content: center;
+      height: 100vh;
+    }
+
+    .container {
+      background: white;
+      padding: 40px;
+      border-radius: 12px;
+      box-shadow: 0 4px 12px rgba(0, 0, 0, 0.1);
+      max-width: 400px;
+      text-align: center;
+    }
+
+    h2 {
+      color: #6a1b9a;
+    }
+
+    button {
+      padding: 10px 20px;
+      font-size: 1em;
+      background-color: #ab47bc;
+      color: white;
+      border: none;
+      border-radius: 6px;
+      cursor: pointer;
+    }
+  </style>
+</head>
+<body>
+  <div class="container">
+    <h2>Unsubscribe</h2>
+    <p>Stop receiving {{.Frequency}} weather updates for <strong>{{.City}}</strong> at {{.Email}}?</p>
+    <form method="POST" action="{{.Action}}">
+      <button type="submit">Unsubscribe</button>
+    </form>
+  </div>
+</body>
+</html>
+`))
+
+type unsubscribePageData struct {
+	Email     string
+	City      string
+	Frequency Frequency
+	Action    string
+}
+
+func renderUnsubscribePage(sub Subscription, action string) ([]byte, error) {
+	var buf bytes.Buffer
+
+	err := unsubscribePage.Execute(&buf, unsubscribePageData{
+		Email:     sub.Email,
+		City:      sub.City,
+		Frequency: sub.Frequency,
+		Action:    action,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
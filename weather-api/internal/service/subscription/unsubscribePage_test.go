@@ -0,0 +1,24 @@
+//go:build unit
+// +build unit
+
+package subscription
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderUnsubscribePage(t *testing.T) {
+	sub := Subscription{Email: "test@example.com", City: "<Kyiv>", Frequency: FrequencyDaily}
+
+	page, err := renderUnsubscribePage(sub, "/api/unsubscribe/token123")
+	require.NoError(t, err)
+
+	html := string(page)
+	assert.Contains(t, html, `action="/api/unsubscribe/token123"`)
+	assert.Contains(t, html, `method="POST"`)
+	assert.Contains(t, html, "&lt;Kyiv&gt;")
+	assert.Contains(t, html, "daily weather updates")
+}
@@ -0,0 +1,32 @@
+package subscription
+
+import (
+	"math"
+	"strings"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/client"
+)
+
+// ChangeThresholds decide whether fresh weather differs enough from the
+// last sent one to email a change-only subscription.
+type ChangeThresholds struct {
+	Temperature     float64 // degrees Celsius
+	Humidity        float64 // percentage points
+	IgnoreCondition bool    // a new description alone is not a change
+}
+
+func (t ChangeThresholds) Changed(last SentWeather, current client.WeatherDTO) bool {
+	if last.At == nil {
+		return true
+	}
+
+	if math.Abs(current.Temperature-last.Temperature) >= t.Temperature {
+		return true
+	}
+
+	if math.Abs(current.Humidity-last.Humidity) >= t.Humidity {
+		return true
+	}
+
+	return !t.IgnoreCondition && !strings.EqualFold(current.Description, last.Description)
+}
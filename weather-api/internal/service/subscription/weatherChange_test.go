@@ -0,0 +1,40 @@
+//go:build unit
+// +build unit
+
+package subscription
+
+import (
+	"testing"
+	"time"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/client"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChangeThresholds_Changed(t *testing.T) {
+	sentAt := time.Now()
+	last := SentWeather{Temperature: 10, Humidity: 50, Description: "Sunny", At: &sentAt}
+	thresholds := ChangeThresholds{Temperature: 2, Humidity: 15}
+
+	tests := []struct {
+		name     string
+		last     SentWeather
+		current  client.WeatherDTO
+		expected bool
+	}{
+		{"nothing sent yet", SentWeather{}, client.WeatherDTO{Temperature: 10}, true},
+		{"small changes", last, client.WeatherDTO{Temperature: 11.5, Humidity: 60, Description: "sunny"}, false},
+		{"temperature drop", last, client.WeatherDTO{Temperature: 8, Humidity: 50, Description: "Sunny"}, true},
+		{"humidity rise", last, client.WeatherDTO{Temperature: 10, Humidity: 65, Description: "Sunny"}, true},
+		{"new condition", last, client.WeatherDTO{Temperature: 10, Humidity: 50, Description: "Thunderstorm"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, thresholds.Changed(tt.last, tt.current))
+		})
+	}
+
+	thresholds.IgnoreCondition = true
+	assert.False(t, thresholds.Changed(last, client.WeatherDTO{Temperature: 10, Humidity: 50, Description: "Cloudy"}))
+}
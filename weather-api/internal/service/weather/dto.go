@@ -1,7 +1,37 @@
 package weather
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/client"
+)
 
 var (
-	ErrInvalidCityInput = errors.New("invalid city input")
+	ErrInvalidCityInput   = errors.New("invalid city input")
+	ErrWeatherUnavailable = errors.New("weather providers are unavailable")
 )
+
+// WeatherResponseV2 is the /api/v2 weather body. Units are part of the
+// field names and the current conditions are nested, leaving room for a
+// forecast next to them.
+type WeatherResponseV2 struct {
+	City    string           `json:"city"`
+	Current CurrentWeatherV2 `json:"current"`
+}
+
+type CurrentWeatherV2 struct {
+	TemperatureC    float64 `json:"temperature_c"`
+	HumidityPercent float64 `json:"humidity_percent"`
+	Description     string  `json:"description"`
+}
+
+func newWeatherResponseV2(city string, weather client.WeatherDTO) WeatherResponseV2 {
+	return WeatherResponseV2{
+		City: city,
+		Current: CurrentWeatherV2{
+			TemperatureC:    weather.Temperature,
+			HumidityPercent: weather.Humidity,
+			Description:     weather.Description,
+		},
+	}
+}
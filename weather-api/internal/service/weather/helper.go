@@ -0,0 +1,28 @@
+package weather
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/client"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/problem"
+	"github.com/gin-gonic/gin"
+)
+
+func HandleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrInvalidCityInput):
+		problem.AbortValidation(c, problem.FieldError{Field: "city", Code: "required", Message: err.Error()})
+
+	case errors.Is(err, client.ErrCityNotFound):
+		problem.Abort(c, http.StatusNotFound, "city_not_found", err)
+
+	case errors.Is(err, client.ErrInvalidRequest):
+		problem.Abort(c, http.StatusBadRequest, "invalid_request", err)
+
+	default:
+		// Provider errors can carry request URLs with our API keys, they
+		// are only logged by the chain.
+		problem.Abort(c, http.StatusBadGateway, "weather_unavailable", ErrWeatherUnavailable)
+	}
+}
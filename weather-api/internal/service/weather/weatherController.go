@@ -1,15 +1,16 @@
 package weather
 
 import (
-	"errors"
+	"context"
 	"net/http"
 
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/apiversion"
 	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/client"
 	"github.com/gin-gonic/gin"
 )
 
 type weatherService interface {
-	GetWeather(city string) (*client.WeatherDTO, error)
+	GetWeather(ctx context.Context, city string) (*client.WeatherDTO, error)
 }
 
 type WeatherController struct {
@@ -23,24 +24,20 @@ func NewWeatherController(service weatherService) *WeatherController {
 func (wc *WeatherController) GetWeather(c *gin.Context) {
 	city, err := validateCityQuery(c)
 	if err != nil {
-		c.String(http.StatusBadRequest, err.Error())
+		HandleError(c, err)
 		return
 	}
 
-	response, err := wc.service.GetWeather(city)
+	response, err := wc.service.GetWeather(c.Request.Context(), city)
 
 	if err != nil {
-		switch {
-		case errors.Is(err, client.ErrCityNotFound):
-			c.String(http.StatusNotFound, err.Error())
-			return
-		case errors.Is(err, client.ErrInvalidRequest):
-			c.String(http.StatusBadRequest, err.Error())
-			return
-		default:
-			c.String(http.StatusBadRequest, "Bad request")
-			return
-		}
+		HandleError(c, err)
+		return
+	}
+
+	if apiversion.From(c) == apiversion.V2 {
+		c.JSON(http.StatusOK, newWeatherResponseV2(city, *response))
+		return
 	}
 
 	c.JSON(http.StatusOK, response)
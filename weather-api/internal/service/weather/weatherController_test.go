@@ -0,0 +1,59 @@
+//go:build unit
+// +build unit
+
+package weather
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/apiversion"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/client"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubWeatherService struct {
+	weather *client.WeatherDTO
+}
+
+func (s stubWeatherService) GetWeather(_ context.Context, city string) (*client.WeatherDTO, error) {
+	return s.weather, nil
+}
+
+func getWeather(t *testing.T, prefix string) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	controller := NewWeatherController(stubWeatherService{
+		weather: &client.WeatherDTO{Temperature: 21.5, Humidity: 55, Description: "Sunny"},
+	})
+
+	router := gin.New()
+	router.Group("/api", apiversion.Middleware(apiversion.V1)).GET("/weather", controller.GetWeather)
+	router.Group("/api/v1", apiversion.Middleware(apiversion.V1)).GET("/weather", controller.GetWeather)
+	router.Group("/api/v2", apiversion.Middleware(apiversion.V2)).GET("/weather", controller.GetWeather)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, prefix+"/weather?city=Kyiv", nil))
+	return w
+}
+
+func TestGetWeather_V1KeepsItsShape(t *testing.T) {
+	for _, prefix := range []string{"/api", "/api/v1"} {
+		w := getWeather(t, prefix)
+
+		assert.Equal(t, http.StatusOK, w.Code, prefix)
+		assert.Equal(t, `{"temperature":21.5,"humidity":55,"description":"Sunny"}`, w.Body.String(), prefix)
+	}
+}
+
+func TestGetWeather_V2(t *testing.T) {
+	w := getWeather(t, "/api/v2")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"city":"Kyiv","current":{"temperature_c":21.5,"humidity_percent":55,"description":"Sunny"}}`,
+		w.Body.String())
+}
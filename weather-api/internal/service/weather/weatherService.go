@@ -1,20 +1,22 @@
 package weather
 
 import (
+	"context"
 	"time"
 
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/logger"
 	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/client"
 	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/redis"
-	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/logger"
 )
 
 type weatherChain interface {
-	GetWeather(city string) (*client.WeatherDTO, error)
+	GetWeather(ctx context.Context, city string) (*client.WeatherDTO, error)
+	GetForecast(ctx context.Context, city string) (*client.ForecastDTO, error)
 }
 
 type redisProvider interface {
-	SetWithTTL(key string, value interface{}, ttl time.Duration) error
-	Get(key string, dest interface{}) error
+	SetWithTTL(ctx context.Context, key string, value interface{}, ttl time.Duration) error
+	Get(ctx context.Context, key string, dest interface{}) error
 }
 
 type WeatherService struct {
@@ -32,35 +34,88 @@ func NewWeatherAPIService(weatherChain weatherChain, redisProvider redisProvider
 	}
 }
 
-func (ws *WeatherService) GetWeather(city string) (*client.WeatherDTO, error) {
+func (ws *WeatherService) GetWeather(ctx context.Context, city string) (*client.WeatherDTO, error) {
 
 	var weatherFromRedis client.WeatherDTO
 
-	err := ws.redisProvider.Get(redis.WeatherKey+city, &weatherFromRedis)
+	err := ws.redisProvider.Get(ctx, redis.WeatherKey+city, &weatherFromRedis)
 
 	if err == nil {
-		ws.logger.Info("Weather data retrieved from Redis", "city", city)
+		ws.logger.WithContext(ctx).Info("Weather data retrieved from Redis", "city", city)
 		return &weatherFromRedis, nil
 	}
 
 	// Log Redis errors (not cache misses)
 	if err.Error() != "redis: nil" { // or use redis.Nil constant if available
-		ws.logger.Error("Failed to get weather from Redis", "city", city, "error", err)
+		ws.logger.WithContext(ctx).Error("Failed to get weather from Redis", "city", city, "error", err)
 
 	}
 
-	weatherDto, err := ws.weatherChain.GetWeather(city)
+	weatherDto, err := ws.weatherChain.GetWeather(ctx, city)
 	if err != nil {
-		ws.logger.Error("Failed to get weather from chain", "city", city, "error", err)
+		ws.logger.WithContext(ctx).Error("Failed to get weather from chain", "city", city, "error", err)
 
 		return nil, err
 	}
 
-	err = ws.redisProvider.SetWithTTL(redis.WeatherKey+city, weatherDto, redis.WeatherTTL)
+	err = ws.redisProvider.SetWithTTL(ctx, redis.WeatherKey+city, weatherDto, redis.WeatherTTL)
 
 	if err != nil {
-		ws.logger.Error("Failed to save weather in Redis", "city", city, "error", err)
+		ws.logger.WithContext(ctx).Error("Failed to save weather in Redis", "city", city, "error", err)
 	}
 
 	return weatherDto, nil
 }
+
+// GetForecast returns today's forecast for city, cached for an hour.
+func (ws *WeatherService) GetForecast(ctx context.Context, city string) (*client.ForecastDTO, error) {
+
+	var forecastFromRedis client.ForecastDTO
+
+	err := ws.redisProvider.Get(ctx, redis.ForecastKey+city, &forecastFromRedis)
+
+	if err == nil {
+		ws.logger.WithContext(ctx).Info("Forecast retrieved from Redis", "city", city)
+		return &forecastFromRedis, nil
+	}
+
+	if err.Error() != "redis: nil" {
+		ws.logger.WithContext(ctx).Error("Failed to get forecast from Redis", "city", city, "error", err)
+	}
+
+	forecastDto, err := ws.weatherChain.GetForecast(ctx, city)
+	if err != nil {
+		ws.logger.WithContext(ctx).Error("Failed to get forecast from chain", "city", city, "error", err)
+
+		return nil, err
+	}
+
+	err = ws.redisProvider.SetWithTTL(ctx, redis.ForecastKey+city, forecastDto, redis.ForecastTTL)
+
+	if err != nil {
+		ws.logger.WithContext(ctx).Error("Failed to save forecast in Redis", "city", city, "error", err)
+	}
+
+	return forecastDto, nil
+}
+
+// RefreshWeather fetches city from the providers and overwrites its cache
+// entry, so the next reads don't wait on a provider.
+func (ws *WeatherService) RefreshWeather(ctx context.Context, city string) error {
+	weatherDto, err := ws.weatherChain.GetWeather(ctx, city)
+	if err != nil {
+		return err
+	}
+
+	return ws.redisProvider.SetWithTTL(ctx, redis.WeatherKey+city, weatherDto, redis.WeatherTTL)
+}
+
+// RefreshForecast does the same as RefreshWeather for today's forecast.
+func (ws *WeatherService) RefreshForecast(ctx context.Context, city string) error {
+	forecastDto, err := ws.weatherChain.GetForecast(ctx, city)
+	if err != nil {
+		return err
+	}
+
+	return ws.redisProvider.SetWithTTL(ctx, redis.ForecastKey+city, forecastDto, redis.ForecastTTL)
+}
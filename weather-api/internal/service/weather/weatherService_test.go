@@ -4,12 +4,14 @@
 package weather
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
 
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/common/logger"
 	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/client"
-	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/logger"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-ValeriiaHuza/weather-api/internal/redis"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -20,22 +22,28 @@ type mockWeatherChain struct {
 	mock.Mock
 }
 
-func (m *mockWeatherChain) GetWeather(city string) (*client.WeatherDTO, error) {
+func (m *mockWeatherChain) GetWeather(_ context.Context, city string) (*client.WeatherDTO, error) {
 	args := m.Called(city)
 	dto, _ := args.Get(0).(*client.WeatherDTO)
 	return dto, args.Error(1)
 }
 
+func (m *mockWeatherChain) GetForecast(_ context.Context, city string) (*client.ForecastDTO, error) {
+	args := m.Called(city)
+	dto, _ := args.Get(0).(*client.ForecastDTO)
+	return dto, args.Error(1)
+}
+
 type mockRedisProvider struct {
 	mock.Mock
 }
 
-func (m *mockRedisProvider) SetWithTTL(key string, value interface{}, ttl time.Duration) error {
+func (m *mockRedisProvider) SetWithTTL(_ context.Context, key string, value interface{}, ttl time.Duration) error {
 	args := m.Called(key, value, ttl)
 	return args.Error(0)
 }
 
-func (m *mockRedisProvider) Get(key string, dest interface{}) error {
+func (m *mockRedisProvider) Get(_ context.Context, key string, dest interface{}) error {
 	args := m.Called(key, dest)
 
 	if dto, ok := args.Get(1).(*client.WeatherDTO); ok {
@@ -44,6 +52,12 @@ func (m *mockRedisProvider) Get(key string, dest interface{}) error {
 		}
 	}
 
+	if dto, ok := args.Get(1).(*client.ForecastDTO); ok {
+		if out, ok := dest.(*client.ForecastDTO); ok {
+			*out = *dto
+		}
+	}
+
 	return args.Error(0)
 }
 
@@ -65,7 +79,7 @@ func TestGetWeather_Success(t *testing.T) {
 	mockLog, _ := logger.NewTestLogger()
 	service := NewWeatherAPIService(mockClient, mockRedis, *mockLog)
 
-	result, err := service.GetWeather("Kyiv")
+	result, err := service.GetWeather(context.Background(), "Kyiv")
 	assert.NoError(t, err)
 	assert.Equal(t, expected, result)
 	mockRedis.AssertCalled(t, "Get", mock.Anything, mock.Anything)
@@ -91,7 +105,7 @@ func TestGetWeather_CacheMiss_Success(t *testing.T) {
 
 	mockRedis.On("SetWithTTL", "weather:"+city, expected, mock.Anything).Return(nil)
 
-	result, err := service.GetWeather(city)
+	result, err := service.GetWeather(context.Background(), city)
 
 	assert.NoError(t, err)
 	assert.Equal(t, expected, result)
@@ -111,7 +125,7 @@ func TestGetWeather_CacheMiss_APIError(t *testing.T) {
 	mockRedis.On("Get", mock.Anything, mock.Anything).Return(errors.New("not found"), nil)
 	mockClient.On("GetWeather", city).Return(nil, errors.New("api error"))
 
-	result, err := service.GetWeather(city)
+	result, err := service.GetWeather(context.Background(), city)
 	assert.Error(t, err)
 	assert.Nil(t, result)
 	mockRedis.AssertCalled(t, "Get", mock.Anything, mock.Anything)
@@ -131,8 +145,72 @@ func TestGetWeather_CacheMiss_APISuccess_RedisSetError(t *testing.T) {
 	mockClient.On("GetWeather", city).Return(expected, nil)
 	mockRedis.On("SetWithTTL", mock.Anything, expected, mock.Anything).Return(errors.New("redis error"))
 
-	result, err := service.GetWeather(city)
+	result, err := service.GetWeather(context.Background(), city)
 	assert.NoError(t, err)
 	assert.Equal(t, expected, result)
 	mockRedis.AssertCalled(t, "SetWithTTL", mock.Anything, expected, mock.Anything)
 }
+
+func TestGetForecast_FromCache(t *testing.T) {
+	expected := &client.ForecastDTO{MaxTemperature: 24, MinTemperature: 13, Sunrise: "05:00", Sunset: "21:10"}
+
+	mockRedis := new(mockRedisProvider)
+	mockClient := new(mockWeatherChain)
+	mockRedis.On("Get", "forecast:Kyiv", mock.Anything).Return(nil, expected)
+
+	mockLog, _ := logger.NewTestLogger()
+	service := NewWeatherAPIService(mockClient, mockRedis, *mockLog)
+
+	result, err := service.GetForecast(context.Background(), "Kyiv")
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result)
+	mockClient.AssertNotCalled(t, "GetForecast", mock.Anything)
+}
+
+func TestGetForecast_CacheMiss_Success(t *testing.T) {
+	expected := &client.ForecastDTO{MaxTemperature: 24, MinTemperature: 13}
+
+	mockRedis := new(mockRedisProvider)
+	mockClient := new(mockWeatherChain)
+	mockRedis.On("Get", "forecast:Lviv", mock.Anything).Return(errors.New("redis: nil"), nil)
+	mockClient.On("GetForecast", "Lviv").Return(expected, nil)
+	mockRedis.On("SetWithTTL", "forecast:Lviv", expected, time.Hour).Return(nil)
+
+	mockLog, _ := logger.NewTestLogger()
+	service := NewWeatherAPIService(mockClient, mockRedis, *mockLog)
+
+	result, err := service.GetForecast(context.Background(), "Lviv")
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result)
+	mockRedis.AssertExpectations(t)
+}
+
+func TestGetForecast_CacheMiss_ChainError(t *testing.T) {
+	mockRedis := new(mockRedisProvider)
+	mockClient := new(mockWeatherChain)
+	mockRedis.On("Get", mock.Anything, mock.Anything).Return(errors.New("redis: nil"), nil)
+	mockClient.On("GetForecast", "Odesa").Return(nil, errors.New("api error"))
+
+	mockLog, _ := logger.NewTestLogger()
+	service := NewWeatherAPIService(mockClient, mockRedis, *mockLog)
+
+	result, err := service.GetForecast(context.Background(), "Odesa")
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	mockRedis.AssertNotCalled(t, "SetWithTTL", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRefreshWeather_OverwritesCache(t *testing.T) {
+	mockRedis := new(mockRedisProvider)
+	mockClient := new(mockWeatherChain)
+	mockLog, _ := logger.NewTestLogger()
+	service := NewWeatherAPIService(mockClient, mockRedis, *mockLog)
+
+	fresh := &client.WeatherDTO{Temperature: 12, Humidity: 70, Description: "Rain"}
+	mockClient.On("GetWeather", "Kyiv").Return(fresh, nil)
+	mockRedis.On("SetWithTTL", "weather:Kyiv", fresh, redis.WeatherTTL).Return(nil)
+
+	assert.NoError(t, service.RefreshWeather(context.Background(), "Kyiv"))
+	mockRedis.AssertNotCalled(t, "Get", mock.Anything, mock.Anything)
+	mockRedis.AssertExpectations(t)
+}